@@ -4,9 +4,12 @@ package main
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
 
+	"github.com/alaleks/shortener/internal/app/cli"
 	"github.com/alaleks/shortener/internal/app/serv"
 )
 
@@ -39,10 +42,21 @@ func info() {
 }
 
 func main() {
+	// subcommands work with the storage directly, e.g. shortener import -user 1 -file urls.csv
+	if cli.IsCommand(os.Args[1:]) {
+		if err := cli.Run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
 	info()
 	server := serv.New()
 
-	// Run server for pprof
+	// Run server for pprof and grpc metrics
+	http.Handle("/debug/grpc/metrics", server.Metrics)
+
 	go func() {
 		server.Logger.LZ.Fatal(http.ListenAndServe(":3031", nil))
 	}()
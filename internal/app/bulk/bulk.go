@@ -0,0 +1,186 @@
+// Package bulk implements the import and export of the shortened URLs
+// of a user in CSV and JSON Lines (NDJSON) formats.
+//
+// It is shared by the web server handlers and the command line interface.
+package bulk
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+// Format is the format of the imported or exported data.
+type Format string
+
+// Supported formats.
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+const (
+	exportPageSize = 1000
+	// importChunkSize is the number of rows shortened in one storage call.
+	importChunkSize = 100
+)
+
+// List of typical errors.
+var (
+	ErrUnknownFormat = errors.New("format must be csv or ndjson")
+	ErrInvalidRow    = errors.New("row is invalid")
+)
+
+// Result represents the result of the import of one row.
+type Result struct {
+	CorID    string `json:"correlation_id,omitempty"`
+	ShortURL string `json:"short_url,omitempty"`
+	Err      string `json:"error,omitempty"`
+	Row      int    `json:"row"`
+	// Stopped is set in the last result if the import has been stopped
+	// by the error, the rows before it have been imported.
+	Stopped bool `json:"stopped,omitempty"`
+}
+
+// ParseFormat returns the format by its name, the empty name means CSV.
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case "", CSV:
+		return CSV, nil
+	case NDJSON, "jsonl":
+		return NDJSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// FormatByContentType returns the format by the media type.
+func FormatByContentType(contentType string) (Format, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "", "text/csv":
+		return CSV, nil
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		return NDJSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	if f == NDJSON {
+		return "application/x-ndjson"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+// Import reads the rows and shortens their URLs for the user in chunks.
+//
+// Invalid rows do not interrupt the import, the result of each row
+// is passed to the callback in the order of the rows and the flush
+// callback is called after each chunk if it is not nil. If the source
+// can't be read, the rows read before are imported and their results
+// are passed before the error is returned. The import is stopped
+// if the callback returns an error.
+func Import(service *usecase.Service, userID string, reader Reader, callback func(Result) error, flush func()) error {
+	var (
+		results = make([]Result, 0, importChunkSize)
+		items   = make([]usecase.ImportItem, 0, importChunkSize)
+		// indexes of the results of the items.
+		indexes = make([]int, 0, importChunkSize)
+	)
+
+	for row := 1; ; row++ {
+		item, err := reader.Read()
+		// the end of the data or the source can't be read.
+		done := err != nil && !errors.Is(err, ErrInvalidRow)
+
+		switch {
+		case done:
+		case err != nil:
+			results = append(results, Result{Row: row, CorID: item.CorID, Err: err.Error()})
+		default:
+			indexes = append(indexes, len(results))
+			results = append(results, Result{Row: row})
+			items = append(items, item)
+		}
+
+		if len(results) == importChunkSize || done && len(results) > 0 {
+			for i, result := range service.ImportMany(userID, items) {
+				results[indexes[i]] = newResult(results[indexes[i]].Row, result)
+			}
+
+			for _, result := range results {
+				if err := callback(result); err != nil {
+					return err
+				}
+			}
+
+			if flush != nil {
+				flush()
+			}
+
+			results, items, indexes = results[:0], items[:0], indexes[:0]
+		}
+
+		if done {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+// Export writes all shortened URLs of the user including the removed ones.
+//
+// URLs are read from storage page by page, the flush callback
+// is called after each page if it is not nil.
+func Export(service *usecase.Service, userID string, writer Writer, flush func()) error {
+	opts := storage.ListOptions{Limit: exportPageSize, Status: storage.StatusAll}
+
+	for {
+		page, err := service.ListPage(userID, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, url := range page.URLs {
+			if err := writer.Write(url); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		if flush != nil {
+			flush()
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+
+		opts.Cursor = page.NextCursor
+	}
+}
+
+// newResult converts the result of shortening to the result of the row.
+func newResult(row int, result usecase.BatchResult) Result {
+	out := Result{Row: row, CorID: result.CorID, ShortURL: result.ShortURL}
+	if result.Err != nil {
+		out.Err = result.Err.Error()
+	}
+
+	return out
+}
@@ -0,0 +1,156 @@
+package bulk_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/alaleks/shortener/internal/app/bulk"
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestImportExport(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	tests := []struct {
+		name   string
+		format bulk.Format
+		data   string
+		errors []bool
+	}{
+		{
+			name:   "CSV с заголовком",
+			format: bulk.CSV,
+			data: "original_url,alias,correlation_id,expires_at\n" +
+				"http://github.com/alaleks/shortener,gh,1,2100-01-01T00:00:00Z\n" +
+				"wrong,,2\n" +
+				"http://go.dev,,3,tomorrow\n" +
+				"http://pkg.go.dev,gh,4\n",
+			errors: []bool{false, true, true, true},
+		},
+		{
+			name:   "NDJSON",
+			format: bulk.NDJSON,
+			data: `{"original_url":"http://github.com/alaleks/shortener","alias":"gh","correlation_id":"1"}` + "\n\n" +
+				`{"original_url":` + "\n" +
+				`{"original_url":"http://go.dev","expires_at":"2000-01-01T00:00:00Z"}` + "\n",
+			errors: []bool{false, true, true},
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			conf := config.New(config.Options{})
+			service := usecase.New(storage.InitStore(conf, logger.NewLogger()), conf, nil)
+			userID := "1"
+
+			reader, err := bulk.NewReader(strings.NewReader(item.data), item.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var results []bulk.Result
+
+			err = bulk.Import(service, userID, reader, func(result bulk.Result) error {
+				results = append(results, result)
+
+				return nil
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != len(item.errors) {
+				t.Fatalf("number of results should be %d but received %d", len(item.errors), len(results))
+			}
+
+			for i, result := range results {
+				if (result.Err != "") != item.errors[i] || result.Row != i+1 {
+					t.Errorf("result of row %d is unexpected: %+v", i+1, result)
+				}
+			}
+
+			if !strings.HasSuffix(results[0].ShortURL, "/gh") {
+				t.Errorf("short URL should be with alias gh but received %s", results[0].ShortURL)
+			}
+
+			var buffer bytes.Buffer
+
+			writer, _ := bulk.NewWriter(&buffer, item.format)
+			if err := bulk.Export(service, userID, writer, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(buffer.String(), "http://github.com/alaleks/shortener") {
+				t.Errorf("export should contain imported URL but received %s", buffer.String())
+			}
+		})
+	}
+}
+
+func TestImportStopped(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	var (
+		conf    = config.New(config.Options{})
+		service = usecase.New(storage.InitStore(conf, logger.NewLogger()), conf, nil)
+		data    strings.Builder
+		errRead = errors.New("connection reset")
+		results []bulk.Result
+		flushed int
+	)
+
+	// строки читаются частями, после 250 строк источник недоступен
+	for i := 0; i < 250; i++ {
+		fmt.Fprintf(&data, "http://example.com/%d,,%d\n", i, i)
+	}
+
+	reader, err := bulk.NewReader(io.MultiReader(strings.NewReader(data.String()), iotest.ErrReader(errRead)), bulk.CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = bulk.Import(service, "1", reader, func(result bulk.Result) error {
+		results = append(results, result)
+
+		return nil
+	}, func() { flushed++ })
+	if !errors.Is(err, errRead) {
+		t.Errorf("error should be %v but received %v", errRead, err)
+	}
+
+	if len(results) != 250 || flushed != 3 {
+		t.Fatalf("250 rows should be imported in 3 chunks but received %d rows in %d chunks", len(results), flushed)
+	}
+
+	for i, result := range results {
+		if result.Err != "" || result.Row != i+1 || result.CorID != strconv.Itoa(i) || result.ShortURL == "" {
+			t.Errorf("result of row %d is unexpected: %+v", i+1, result)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for name, format := range map[string]bulk.Format{"": bulk.CSV, "CSV": bulk.CSV, "ndjson": bulk.NDJSON} {
+		if got, err := bulk.ParseFormat(name); err != nil || got != format {
+			t.Errorf("format of %q should be %s but received %s", name, format, got)
+		}
+	}
+
+	if _, err := bulk.ParseFormat("xml"); err == nil {
+		t.Errorf("error should be %v but received nil", bulk.ErrUnknownFormat)
+	}
+}
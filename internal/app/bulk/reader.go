@@ -0,0 +1,143 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+const maxLineSize = 1 << 20
+
+// Reader reads the imported URLs row by row.
+//
+// Read returns io.EOF at the end of the data. If the row is invalid,
+// the error wraps ErrInvalidRow and the reading can be continued.
+type Reader interface {
+	Read() (usecase.ImportItem, error)
+}
+
+// csvReader reads rows with the columns: original URL,
+// alias, correlation ID and expiry time in RFC 3339 format.
+// Only the first column is required, the header row is optional.
+type csvReader struct {
+	reader *csv.Reader
+	first  bool
+}
+
+// ndjsonReader reads JSON objects one per line.
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+}
+
+// ndjsonItem represents the imported URL in JSON.
+type ndjsonItem struct {
+	ExpiresAt   *time.Time `json:"expires_at"`
+	OriginalURL string     `json:"original_url"`
+	Alias       string     `json:"alias"`
+	CorID       string     `json:"correlation_id"`
+}
+
+// NewReader returns the reader of the data in the format.
+func NewReader(source io.Reader, format Format) (Reader, error) {
+	switch format {
+	case CSV:
+		reader := csv.NewReader(source)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		reader.ReuseRecord = true
+
+		return &csvReader{reader: reader, first: true}, nil
+	case NDJSON:
+		scanner := bufio.NewScanner(source)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Read implements the Reader interface.
+func (r *csvReader) Read() (usecase.ImportItem, error) {
+	record, err := r.reader.Read()
+
+	var parseErr *csv.ParseError
+
+	switch {
+	case errors.As(err, &parseErr):
+		return usecase.ImportItem{}, fmt.Errorf("%w: %s", ErrInvalidRow, parseErr.Err)
+	case err != nil:
+		return usecase.ImportItem{}, err
+	}
+
+	// skip the header row.
+	if r.first {
+		r.first = false
+
+		if name := strings.ToLower(strings.TrimSpace(record[0])); name == "original_url" || name == "url" {
+			return r.Read()
+		}
+	}
+
+	column := func(i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	item := usecase.ImportItem{
+		OriginalURL: column(0),
+		Alias:       column(1),
+		CorID:       column(2),
+	}
+
+	if expiry := column(3); expiry != "" {
+		expiresAt, err := time.Parse(time.RFC3339, expiry)
+		if err != nil {
+			return item, fmt.Errorf("%w: expiry time must be in RFC 3339 format", ErrInvalidRow)
+		}
+
+		item.ExpiresAt = &expiresAt
+	}
+
+	return item, nil
+}
+
+// Read implements the Reader interface.
+func (r *ndjsonReader) Read() (usecase.ImportItem, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var item ndjsonItem
+
+		if err := json.Unmarshal(line, &item); err != nil {
+			return usecase.ImportItem{}, fmt.Errorf("%w: %s", ErrInvalidRow, err)
+		}
+
+		return usecase.ImportItem{
+			ExpiresAt:   item.ExpiresAt,
+			OriginalURL: item.OriginalURL,
+			Alias:       item.Alias,
+			CorID:       item.CorID,
+		}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return usecase.ImportItem{}, err
+	}
+
+	return usecase.ImportItem{}, io.EOF
+}
@@ -0,0 +1,108 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+// Writer writes the exported URLs, Flush must be called
+// to write the buffered data.
+type Writer interface {
+	Write(url storage.UserURL) error
+	Flush() error
+}
+
+// csvHeader is the header row of the exported CSV,
+// tags are separated by semicolons.
+var csvHeader = []string{
+	"short_url", "original_url", "correlation_id", "created_at",
+	"expires_at", "clicks", "removed", "tags",
+}
+
+// csvWriter writes URLs in CSV with the header row.
+type csvWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+// ndjsonWriter writes URLs as JSON objects one per line.
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewWriter returns the writer of the data in the format.
+func NewWriter(destination io.Writer, format Format) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{writer: csv.NewWriter(destination)}, nil
+	case NDJSON:
+		buffer := bufio.NewWriter(destination)
+
+		return &ndjsonWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Write implements the Writer interface.
+func (w *csvWriter) Write(url storage.UserURL) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	var expiresAt string
+	if url.ExpiresAt != nil {
+		expiresAt = url.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return w.writer.Write([]string{
+		url.ShortURL,
+		url.LongURL,
+		url.CorrelationID,
+		url.CreatedAt.Format(time.RFC3339),
+		expiresAt,
+		strconv.FormatUint(uint64(url.Clicks), 10),
+		strconv.FormatBool(url.Removed),
+		strings.Join(url.Tags, ";"),
+	})
+}
+
+// Flush implements the Writer interface.
+func (w *csvWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+// writeHeader writes the header row once.
+func (w *csvWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+
+	w.header = true
+
+	return w.writer.Write(csvHeader)
+}
+
+// Write implements the Writer interface.
+func (w *ndjsonWriter) Write(url storage.UserURL) error {
+	return w.encoder.Encode(url)
+}
+
+// Flush implements the Writer interface.
+func (w *ndjsonWriter) Flush() error {
+	return w.buffer.Flush()
+}
@@ -0,0 +1,160 @@
+// Package cli implements the subcommands of the command line interface,
+// which work with the storage directly without the web server.
+//
+// The storage is configured by the environment variables
+// (DATABASE_DSN, FILE_STORAGE_PATH, BASE_URL) or the CONFIG file.
+//
+//	shortener import [-user id] [-format csv|ndjson] [-file path]
+//	shortener export -user id [-format csv|ndjson] [-file path]
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/alaleks/shortener/internal/app/bulk"
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+// Names of the subcommands.
+const (
+	CommandImport = "import"
+	CommandExport = "export"
+)
+
+// List of typical errors.
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrUserRequired   = errors.New("user id is required")
+)
+
+// options represents the flags of the subcommands.
+type options struct {
+	userID string
+	format string
+	file   string
+}
+
+// IsCommand returns true if the arguments start with the subcommand.
+func IsCommand(args []string) bool {
+	return len(args) > 0 && (args[0] == CommandImport || args[0] == CommandExport)
+}
+
+// Run runs the subcommand with the arguments without the program name.
+//
+// The data is read from stdin and written to stdout
+// if the file is not specified.
+func Run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if !IsCommand(args) {
+		return ErrUnknownCommand
+	}
+
+	opts, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	format, err := bulk.ParseFormat(opts.format)
+	if err != nil {
+		return err
+	}
+
+	conf := config.New(config.Options{Env: true})
+	store := storage.InitStore(conf, logger.NewLogger())
+	service := usecase.New(store, conf, nil)
+
+	defer func() {
+		store.Pool.Stop()
+		_ = store.St.Close()
+	}()
+
+	if args[0] == CommandImport {
+		return runImport(service, store, opts, format, stdin, stdout)
+	}
+
+	return runExport(service, opts, format, stdout)
+}
+
+// runImport imports the URLs for the user and writes the result
+// of each row in NDJSON, a new user is created if it is not specified.
+func runImport(service *usecase.Service, store *storage.Store, opts options,
+	format bulk.Format, stdin io.Reader, stdout io.Writer,
+) error {
+	source := stdin
+
+	if opts.file != "" {
+		file, err := os.Open(opts.file)
+		if err != nil {
+			return fmt.Errorf("failed open file: %w", err)
+		}
+		defer file.Close()
+
+		source = file
+	}
+
+	if opts.userID == "" {
+		opts.userID = strconv.Itoa(int(store.St.Create()))
+		fmt.Fprintf(os.Stderr, "created user %s\n", opts.userID)
+	}
+
+	reader, err := bulk.NewReader(source, format)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+
+	return bulk.Import(service, opts.userID, reader, func(result bulk.Result) error {
+		return encoder.Encode(result)
+	}, nil)
+}
+
+// runExport exports all URLs of the user.
+func runExport(service *usecase.Service, opts options, format bulk.Format, stdout io.Writer) error {
+	if opts.userID == "" {
+		return ErrUserRequired
+	}
+
+	destination := stdout
+
+	if opts.file != "" {
+		file, err := os.Create(opts.file)
+		if err != nil {
+			return fmt.Errorf("failed create file: %w", err)
+		}
+		defer file.Close()
+
+		destination = file
+	}
+
+	writer, err := bulk.NewWriter(destination, format)
+	if err != nil {
+		return err
+	}
+
+	return bulk.Export(service, opts.userID, writer, nil)
+}
+
+// parseFlags parses the flags of the subcommand.
+func parseFlags(args []string) (options, error) {
+	var opts options
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.StringVar(&opts.userID, "user", "", "user id")
+	flags.StringVar(&opts.format, "format", string(bulk.CSV), "data format: csv or ndjson")
+	flags.StringVar(&opts.file, "file", "", "file path, stdin or stdout by default")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return opts, fmt.Errorf("failed parse flags: %w", err)
+	}
+
+	return opts, nil
+}
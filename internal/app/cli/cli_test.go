@@ -0,0 +1,111 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/bulk"
+	"github.com/alaleks/shortener/internal/app/cli"
+)
+
+func TestIsCommand(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	tests := []struct {
+		args []string
+		is   bool
+	}{
+		{args: []string{"import", "-user", "1"}, is: true},
+		{args: []string{"export"}, is: true},
+		{args: []string{"-a", "localhost:8080"}},
+		{args: nil},
+	}
+
+	for _, item := range tests {
+		if is := cli.IsCommand(item.args); is != item.is {
+			t.Errorf("%v should be command %v but received %v", item.args, item.is, is)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	// данные для теста
+	dir := t.TempDir()
+	t.Setenv("FILE_STORAGE_PATH", filepath.Join(dir, "storage"))
+	t.Setenv("DATABASE_DSN", "")
+
+	source := filepath.Join(dir, "urls.ndjson")
+	data := `{"original_url":"http://github.com/alaleks/shortener","alias":"gh","correlation_id":"1"}` + "\n" +
+		`{"original_url":"wrong","correlation_id":"2"}` + "\n"
+
+	if err := os.WriteFile(source, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// импорт из файла
+	var out bytes.Buffer
+
+	if err := cli.Run([]string{"import", "-user", "1", "-format", "ndjson", "-file", source}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []bulk.Result
+
+	for decoder := json.NewDecoder(&out); decoder.More(); {
+		var result bulk.Result
+		if err := decoder.Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		results = append(results, result)
+	}
+
+	if len(results) != 2 || !strings.HasSuffix(results[0].ShortURL, "/gh") || results[1].Err == "" {
+		t.Fatalf("import should return imported and invalid rows but received %+v", results)
+	}
+
+	// импорт из stdin
+	out.Reset()
+
+	if err := cli.Run([]string{"import", "-user", "1"}, strings.NewReader("http://go.dev,,3\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), `"correlation_id":"3"`) {
+		t.Errorf("import should return result of row but received %s", out.String())
+	}
+
+	// экспорт сохраненных ссылок
+	out.Reset()
+
+	if err := cli.Run([]string{"export", "-user", "1"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "http://github.com/alaleks/shortener") || !strings.Contains(out.String(), "http://go.dev") {
+		t.Errorf("export should contain imported URLs but received %s", out.String())
+	}
+
+	// ошибки команд
+	tests := []struct {
+		name string
+		args []string
+		err  error
+	}{
+		{name: "неизвестная команда", args: []string{"serve"}, err: cli.ErrUnknownCommand},
+		{name: "экспорт без пользователя", args: []string{"export"}, err: cli.ErrUserRequired},
+		{name: "неизвестный формат", args: []string{"export", "-user", "1", "-format", "xml"}, err: bulk.ErrUnknownFormat},
+	}
+
+	for _, item := range tests {
+		if err := cli.Run(item.args, nil, &out); !errors.Is(err, item.err) {
+			t.Errorf("%s: error should be %v but received %v", item.name, item.err, err)
+		}
+	}
+}
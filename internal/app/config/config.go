@@ -4,17 +4,22 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	defaultSizeUID = 5
 )
 
+// ErrInvalidRateLimit is an indicator that the rate limit has an invalid format.
+var ErrInvalidRateLimit = errors.New(`rate limit is invalid, should be: "<requests>/<s|m|h>"`)
+
 // Configurator interface is used for application settings
 // by combining interfaces Recipient and Tuner interfaces.
 type Configurator interface {
@@ -28,11 +33,21 @@ type Recipient interface {
 	GetBaseURL() string
 	GetFileStoragePath() string
 	GetTrustedSubnet() string
+	GetTrustedProxies() string
 	GetDSN() string
 	GetSecretKey() []byte
 	GetSizeUID() int
 	EnableTLS() bool
 	GetGRPCPort() string
+	GetGRPCTLS() GRPCTLS
+	EnableGRPCReflection() bool
+	GetGatewayPrefix() string
+	GetRateLimits() RateLimits
+	GetIdempotencyTTL() time.Duration
+	GetGeoDBPath() string
+	GetDomains() []string
+	GetLinkCheck() LinkCheck
+	Err() error
 }
 
 // Tuner interface implements methods for configuring tuning.
@@ -55,14 +70,101 @@ type AppConfig struct {
 	dsn string
 	// cfgFile is the path for configuration file.
 	cfgFile string
-	// classless addressing (CIDR)
+	// trustedSubnet is the comma-separated list of networks (CIDR)
+	// that have access to the internal statistics.
 	trustedSubnet string
+	// trustedProxies is the comma-separated list of networks (CIDR)
+	// of proxies that are allowed to pass the client IP address in headers.
+	trustedProxies string
 	// secretKey is designed for encryption and decryption of authorization data.
 	secretKey []byte
 	// sizeUID sets the size of the short URL ID.
 	sizeUID int
 	// tls is used to enable TLS.
 	tls bool
+	// grpcTLS sets the certificate files of the grpc server.
+	grpcTLS GRPCTLS
+	// grpcReflection is used to enable the grpc server reflection.
+	grpcReflection bool
+	// gatewayPrefix is the path prefix of the REST/JSON gateway of the grpc service.
+	gatewayPrefix string
+	// rateLimits sets the limits of requests per client for groups of routes.
+	rateLimits RateLimits
+	// idempotencyTTL is the time during which the response
+	// to the batch request with the idempotency key is stored.
+	idempotencyTTL time.Duration
+	// geoDBPath is the path of the CSV file mapping networks (CIDR)
+	// to country codes, which is used by the geo targeting of redirects.
+	geoDBPath string
+	// domains are the custom domains of short URLs
+	// in addition to the domain of the base URL.
+	domains []string
+	// linkCheck sets the background checks of the destinations of short URLs.
+	linkCheck LinkCheck
+	// errs are the errors of the invalid options which have been skipped.
+	errs []error
+}
+
+// GRPCTLS contains the paths of the certificate files of the grpc server.
+type GRPCTLS struct {
+	// CertFile is the path of the server certificate in PEM format.
+	CertFile string
+	// KeyFile is the path of the server private key in PEM format.
+	KeyFile string
+	// ClientCAFile is the path of the CA certificates in PEM format
+	// that are used to verify client certificates (mTLS).
+	ClientCAFile string
+}
+
+// Enabled returns true if the certificate and the key are set.
+func (g GRPCTLS) Enabled() bool {
+	return g.CertFile != "" && g.KeyFile != ""
+}
+
+// RateLimit represents the number of requests allowed per period.
+// The zero value means that the limit is disabled.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimits contains the rate limits for groups of routes.
+//
+// The limits of the groups are counted for each user, the IP limits
+// are counted for each IP address and are shared by all users behind it
+// (e.g. behind NAT), so they should be larger.
+type RateLimits struct {
+	// Shorten is the limit for shortening a single URL.
+	Shorten RateLimit
+	// Batch is the limit for batch shortening.
+	Batch RateLimit
+	// Redirect is the limit for redirects by a short URL.
+	Redirect RateLimit
+	// ShortenIP is the limit of the IP address for shortening a single URL.
+	ShortenIP RateLimit
+	// BatchIP is the limit of the IP address for batch shortening.
+	BatchIP RateLimit
+	// RedirectIP is the limit of the IP address for redirects.
+	RedirectIP RateLimit
+	// Unlock is the limit of the password attempts for one short URL.
+	Unlock RateLimit
+	// UnlockIP is the limit of the IP address for the password attempts.
+	UnlockIP RateLimit
+}
+
+// LinkCheck contains the settings of the background checks
+// of the destinations of short URLs.
+type LinkCheck struct {
+	// Interval is the period of the checks of each destination,
+	// the zero value disables the checks.
+	Interval time.Duration
+	// Concurrency is the maximum number of simultaneous requests.
+	Concurrency int
+}
+
+// Enabled returns true if the checks are turned on.
+func (l LinkCheck) Enabled() bool {
+	return l.Interval > 0
 }
 
 // configJSON is the JSON structure for configuration.
@@ -70,10 +172,31 @@ type configJSON struct {
 	ServerAddress   string `json:"server_address"`
 	BaseURL         string `json:"base_url"`
 	GrpcServerPort  string `json:"grpc_server_port"`
+	GrpcCertFile    string `json:"grpc_tls_cert"`
+	GrpcKeyFile     string `json:"grpc_tls_key"`
+	GrpcClientCA    string `json:"grpc_tls_client_ca"`
+	GatewayPrefix   string `json:"gateway_prefix"`
 	FileStoragePath string `json:"file_storage_path"`
 	DSN             string `json:"database_dsn"`
 	TrustedSubnet   string `json:"trusted_subnet"`
+	TrustedProxies  string `json:"trusted_proxies"`
+	RateShorten     string `json:"rate_limit_shorten"`
+	RateBatch       string `json:"rate_limit_batch"`
+	RateRedirect    string `json:"rate_limit_redirect"`
+	RateShortenIP   string `json:"rate_limit_shorten_ip"`
+	RateBatchIP     string `json:"rate_limit_batch_ip"`
+	RateRedirectIP  string `json:"rate_limit_redirect_ip"`
+	RateUnlock      string `json:"rate_limit_unlock"`
+	RateUnlockIP    string `json:"rate_limit_unlock_ip"`
+	IdempotencyTTL  string `json:"idempotency_ttl"`
+	GeoDBPath       string `json:"geo_db_path"`
 	EnableHTTPS     bool   `json:"enable_https"`
+	GrpcReflection  bool   `json:"grpc_reflection"`
+	// Domains are the custom domains of short URLs.
+	Domains []string `json:"domains"`
+	// LinkCheckInterval is the duration, e.g. 1h, "0" disables the checks.
+	LinkCheckInterval    string `json:"link_check_interval"`
+	LinkCheckConcurrency int    `json:"link_check_concurrency"`
 }
 
 // The Options structure contains application configuration
@@ -94,7 +217,26 @@ type confFlags struct {
 	tls             *string
 	cfgFile         *string
 	trustedSubnet   *string
+	trustedProxies  *string
 	grpcPort        *string
+	grpcCertFile    *string
+	grpcKeyFile     *string
+	grpcClientCA    *string
+	grpcReflection  *string
+	gatewayPrefix   *string
+	rateShorten     *string
+	rateBatch       *string
+	rateRedirect    *string
+	rateShortenIP   *string
+	rateBatchIP     *string
+	rateRedirectIP  *string
+	rateUnlock      *string
+	rateUnlockIP    *string
+	idempotencyTTL  *string
+	geoDBPath       *string
+	domains         *string
+	linkInterval    *string
+	linkConcurrency *string
 }
 
 // New returns a pointer of struct that implements the Configurator interface.
@@ -102,12 +244,27 @@ func New(opt Options) *AppConfig {
 	appConf := AppConfig{
 		serverAddr:      "localhost:8080",
 		grpcPort:        ":50051",
+		gatewayPrefix:   "/v1",
+		idempotencyTTL:  24 * time.Hour,
 		baseURL:         "http://localhost:8080/",
 		fileStoragePath: "",
 		dsn:             "",
 		secretKey:       []byte("9EE3BF9351DFCFF24CD6DA2C4D963"),
 		sizeUID:         defaultSizeUID,
 		tls:             false,
+		linkCheck:       LinkCheck{Interval: time.Hour, Concurrency: 4},
+		rateLimits: RateLimits{
+			Shorten:  RateLimit{Requests: 100, Period: time.Minute},
+			Batch:    RateLimit{Requests: 10, Period: time.Minute},
+			Redirect: RateLimit{Requests: 1000, Period: time.Minute},
+			// the IP limits are ten times larger than the limits of the user.
+			ShortenIP:  RateLimit{Requests: 1000, Period: time.Minute},
+			BatchIP:    RateLimit{Requests: 100, Period: time.Minute},
+			RedirectIP: RateLimit{Requests: 10000, Period: time.Minute},
+			// each password attempt is checked by bcrypt, so the limits are strict.
+			Unlock:   RateLimit{Requests: 5, Period: time.Minute},
+			UnlockIP: RateLimit{Requests: 20, Period: time.Minute},
+		},
 	}
 
 	if opt.Env {
@@ -131,6 +288,42 @@ func (a *AppConfig) GetGRPCPort() string {
 	return a.grpcPort
 }
 
+// GetGRPCTLS returns the certificate files of the grpc server.
+func (a *AppConfig) GetGRPCTLS() GRPCTLS {
+	return a.grpcTLS
+}
+
+// EnableGRPCReflection returns true if the grpc server reflection is enabled.
+func (a *AppConfig) EnableGRPCReflection() bool {
+	return a.grpcReflection
+}
+
+// GetIdempotencyTTL returns the time during which the response
+// to the batch request with the idempotency key is stored.
+func (a *AppConfig) GetIdempotencyTTL() time.Duration {
+	return a.idempotencyTTL
+}
+
+// GetGeoDBPath returns the path of the geo database file.
+func (a *AppConfig) GetGeoDBPath() string {
+	return a.geoDBPath
+}
+
+// GetDomains returns the custom domains of short URLs.
+func (a *AppConfig) GetDomains() []string {
+	return append([]string(nil), a.domains...)
+}
+
+// GetLinkCheck returns the settings of the checks of the destinations.
+func (a *AppConfig) GetLinkCheck() LinkCheck {
+	return a.linkCheck
+}
+
+// GetGatewayPrefix returns the path prefix of the REST/JSON gateway.
+func (a *AppConfig) GetGatewayPrefix() string {
+	return a.gatewayPrefix
+}
+
 // GetBaseURL returns the url of the application.
 func (a *AppConfig) GetBaseURL() string {
 	return a.baseURL
@@ -162,11 +355,64 @@ func (a *AppConfig) GetSizeUID() int {
 	return a.sizeUID
 }
 
-// GetTrustedSubnet returns the trusted subnet.
+// GetTrustedSubnet returns the trusted subnets separated by commas.
 func (a *AppConfig) GetTrustedSubnet() string {
 	return a.trustedSubnet
 }
 
+// GetTrustedProxies returns the networks of trusted proxies separated by commas.
+func (a *AppConfig) GetTrustedProxies() string {
+	return a.trustedProxies
+}
+
+// GetRateLimits returns the rate limits for groups of routes.
+func (a *AppConfig) GetRateLimits() RateLimits {
+	return a.rateLimits
+}
+
+// Err returns the errors of the invalid options, such options are skipped
+// and the previous values are kept.
+func (a *AppConfig) Err() error {
+	return errors.Join(a.errs...)
+}
+
+// ParseRateLimit parses the rate limit in the format "<requests>/<period>",
+// where period is one of s (second), m (minute) or h (hour), e.g. "100/m".
+// The value "0" disables the limit.
+func ParseRateLimit(value string) (RateLimit, error) {
+	if value == "0" {
+		return RateLimit{}, nil
+	}
+
+	requests, unit, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+
+	qty, err := strconv.Atoi(requests)
+	if err != nil || qty < 0 {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+
+	periods := map[string]time.Duration{
+		"s": time.Second,
+		"m": time.Minute,
+		"h": time.Hour,
+	}
+
+	period, ok := periods[unit]
+	if !ok {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+
+	return RateLimit{Requests: qty, Period: period}, nil
+}
+
+// Enabled returns true if the rate limit is set.
+func (r RateLimit) Enabled() bool {
+	return r.Requests > 0 && r.Period > 0
+}
+
 // DefineOptionsEnv implements application configuration using environment variables.
 func (a *AppConfig) DefineOptionsEnv() {
 	if cgfFile, ok := os.LookupEnv("CONFIG"); ok && cgfFile != "" {
@@ -197,6 +443,34 @@ func (a *AppConfig) DefineOptionsEnv() {
 		a.dsn = dsn
 	}
 
+	if certFile, ok := os.LookupEnv("GRPC_TLS_CERT"); ok && certFile != "" {
+		a.grpcTLS.CertFile = certFile
+	}
+
+	if keyFile, ok := os.LookupEnv("GRPC_TLS_KEY"); ok && keyFile != "" {
+		a.grpcTLS.KeyFile = keyFile
+	}
+
+	if clientCA, ok := os.LookupEnv("GRPC_TLS_CLIENT_CA"); ok && clientCA != "" {
+		a.grpcTLS.ClientCAFile = clientCA
+	}
+
+	if _, ok := os.LookupEnv("GRPC_REFLECTION"); ok {
+		a.grpcReflection = true
+	}
+
+	if gatewayPrefix, ok := os.LookupEnv("GATEWAY_PREFIX"); ok && gatewayPrefix != "" {
+		a.gatewayPrefix = gatewayPrefix
+	}
+
+	if geoDBPath, ok := os.LookupEnv("GEO_DB_PATH"); ok && geoDBPath != "" {
+		a.geoDBPath = geoDBPath
+	}
+
+	if domains, ok := os.LookupEnv("DOMAINS"); ok && domains != "" {
+		a.domains = parseDomains(strings.Split(domains, ","))
+	}
+
 	if sizeUID, ok := os.LookupEnv("SIZE_UID"); ok && sizeUID != "" {
 		i, err := strconv.Atoi(sizeUID)
 		if err == nil && i > 3 {
@@ -212,6 +486,21 @@ func (a *AppConfig) DefineOptionsEnv() {
 		a.trustedSubnet = trustSubnet
 	}
 
+	if trustProxies, ok := os.LookupEnv("TRUSTED_PROXIES"); ok {
+		a.trustedProxies = trustProxies
+	}
+
+	a.setRateLimit(&a.rateLimits.Shorten, os.Getenv("RATE_LIMIT_SHORTEN"))
+	a.setRateLimit(&a.rateLimits.Batch, os.Getenv("RATE_LIMIT_BATCH"))
+	a.setRateLimit(&a.rateLimits.Redirect, os.Getenv("RATE_LIMIT_REDIRECT"))
+	a.setRateLimit(&a.rateLimits.ShortenIP, os.Getenv("RATE_LIMIT_SHORTEN_IP"))
+	a.setRateLimit(&a.rateLimits.BatchIP, os.Getenv("RATE_LIMIT_BATCH_IP"))
+	a.setRateLimit(&a.rateLimits.RedirectIP, os.Getenv("RATE_LIMIT_REDIRECT_IP"))
+	a.setRateLimit(&a.rateLimits.Unlock, os.Getenv("RATE_LIMIT_UNLOCK"))
+	a.setRateLimit(&a.rateLimits.UnlockIP, os.Getenv("RATE_LIMIT_UNLOCK_IP"))
+	a.setIdempotencyTTL(os.Getenv("IDEMPOTENCY_TTL"))
+	a.setLinkCheck(os.Getenv("LINK_CHECK_INTERVAL"), os.Getenv("LINK_CHECK_CONCURRENCY"))
+
 	// Сheck if the options are correct.
 	a.checkOptions()
 }
@@ -255,10 +544,42 @@ func (a *AppConfig) DefineOptionsFlags(args []string) {
 		a.trustedSubnet = *confFlags.trustedSubnet
 	}
 
+	if *confFlags.trustedProxies != "" {
+		a.trustedProxies = *confFlags.trustedProxies
+	}
+
 	if *confFlags.grpcPort != "" {
 		a.grpcPort = *confFlags.grpcPort
 	}
 
+	if *confFlags.grpcCertFile != "" {
+		a.grpcTLS.CertFile = *confFlags.grpcCertFile
+	}
+
+	if *confFlags.grpcKeyFile != "" {
+		a.grpcTLS.KeyFile = *confFlags.grpcKeyFile
+	}
+
+	if *confFlags.grpcClientCA != "" {
+		a.grpcTLS.ClientCAFile = *confFlags.grpcClientCA
+	}
+
+	if *confFlags.grpcReflection != "" {
+		a.grpcReflection = true
+	}
+
+	if *confFlags.gatewayPrefix != "" {
+		a.gatewayPrefix = *confFlags.gatewayPrefix
+	}
+
+	if *confFlags.geoDBPath != "" {
+		a.geoDBPath = *confFlags.geoDBPath
+	}
+
+	if *confFlags.domains != "" {
+		a.domains = parseDomains(strings.Split(*confFlags.domains, ","))
+	}
+
 	if *confFlags.sizeUID != "" {
 		i, err := strconv.Atoi(*confFlags.sizeUID)
 		if err == nil && i > 3 {
@@ -266,6 +587,17 @@ func (a *AppConfig) DefineOptionsFlags(args []string) {
 		}
 	}
 
+	a.setRateLimit(&a.rateLimits.Shorten, *confFlags.rateShorten)
+	a.setRateLimit(&a.rateLimits.Batch, *confFlags.rateBatch)
+	a.setRateLimit(&a.rateLimits.Redirect, *confFlags.rateRedirect)
+	a.setRateLimit(&a.rateLimits.ShortenIP, *confFlags.rateShortenIP)
+	a.setRateLimit(&a.rateLimits.BatchIP, *confFlags.rateBatchIP)
+	a.setRateLimit(&a.rateLimits.RedirectIP, *confFlags.rateRedirectIP)
+	a.setRateLimit(&a.rateLimits.Unlock, *confFlags.rateUnlock)
+	a.setRateLimit(&a.rateLimits.UnlockIP, *confFlags.rateUnlockIP)
+	a.setIdempotencyTTL(*confFlags.idempotencyTTL)
+	a.setLinkCheck(*confFlags.linkInterval, *confFlags.linkConcurrency)
+
 	// Сheck if the options are correct.
 	a.checkOptions()
 }
@@ -292,7 +624,88 @@ func (a *AppConfig) configureFile() {
 	a.dsn = cfg.DSN
 	a.tls = cfg.EnableHTTPS
 	a.trustedSubnet = cfg.TrustedSubnet
+	a.trustedProxies = cfg.TrustedProxies
 	a.grpcPort = cfg.GrpcServerPort
+	a.grpcTLS = GRPCTLS{
+		CertFile:     cfg.GrpcCertFile,
+		KeyFile:      cfg.GrpcKeyFile,
+		ClientCAFile: cfg.GrpcClientCA,
+	}
+	a.grpcReflection = cfg.GrpcReflection
+
+	if cfg.GatewayPrefix != "" {
+		a.gatewayPrefix = cfg.GatewayPrefix
+	}
+
+	a.geoDBPath = cfg.GeoDBPath
+	a.domains = parseDomains(cfg.Domains)
+	a.setRateLimit(&a.rateLimits.Shorten, cfg.RateShorten)
+	a.setRateLimit(&a.rateLimits.Batch, cfg.RateBatch)
+	a.setRateLimit(&a.rateLimits.Redirect, cfg.RateRedirect)
+	a.setRateLimit(&a.rateLimits.ShortenIP, cfg.RateShortenIP)
+	a.setRateLimit(&a.rateLimits.BatchIP, cfg.RateBatchIP)
+	a.setRateLimit(&a.rateLimits.RedirectIP, cfg.RateRedirectIP)
+	a.setRateLimit(&a.rateLimits.Unlock, cfg.RateUnlock)
+	a.setRateLimit(&a.rateLimits.UnlockIP, cfg.RateUnlockIP)
+	a.setIdempotencyTTL(cfg.IdempotencyTTL)
+
+	if cfg.LinkCheckConcurrency > 0 {
+		a.linkCheck.Concurrency = cfg.LinkCheckConcurrency
+	}
+
+	a.setLinkCheck(cfg.LinkCheckInterval, "")
+}
+
+// parseDomains returns the domains in lower case without the empty ones,
+// the domains are the hosts of short URLs, e.g. go.example.com.
+func parseDomains(values []string) []string {
+	domains := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if domain := strings.ToLower(strings.TrimSpace(value)); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// setIdempotencyTTL sets the idempotency TTL if the value
+// is a valid positive duration, e.g. 1h30m.
+func (a *AppConfig) setIdempotencyTTL(value string) {
+	if ttl, err := time.ParseDuration(value); err == nil && ttl > 0 {
+		a.idempotencyTTL = ttl
+	}
+}
+
+// setLinkCheck sets the interval of the link checks if the value is
+// a valid duration ("0" disables the checks) and the concurrency
+// if the value is a positive number, the empty values are skipped.
+func (a *AppConfig) setLinkCheck(interval, concurrency string) {
+	if d, err := time.ParseDuration(interval); err == nil && d >= 0 {
+		a.linkCheck.Interval = d
+	}
+
+	if n, err := strconv.Atoi(concurrency); err == nil && n > 0 {
+		a.linkCheck.Concurrency = n
+	}
+}
+
+// setRateLimit sets the rate limit if the value is not empty and valid,
+// the invalid value is reported by Err.
+func (a *AppConfig) setRateLimit(limit *RateLimit, value string) {
+	if value == "" {
+		return
+	}
+
+	rateLimit, err := ParseRateLimit(value)
+	if err != nil {
+		a.errs = append(a.errs, fmt.Errorf("%w: %q", err, value))
+
+		return
+	}
+
+	*limit = rateLimit
 }
 
 func parseFlags(args []string) (*confFlags, error) {
@@ -307,7 +720,26 @@ func parseFlags(args []string) (*confFlags, error) {
 	configFlags.sizeUID = flags.String("q", "", "SIZE_UID")
 	configFlags.tls = flags.String("s", "", "ENABLE_HTTPS")
 	configFlags.trustedSubnet = flags.String("t", "", "TRUSTED_SUBNET")
+	configFlags.trustedProxies = flags.String("tp", "", "TRUSTED_PROXIES")
 	configFlags.grpcPort = flags.String("g", "", "GRPC_PORT")
+	configFlags.grpcCertFile = flags.String("grpc-cert", "", "GRPC_TLS_CERT")
+	configFlags.grpcKeyFile = flags.String("grpc-key", "", "GRPC_TLS_KEY")
+	configFlags.grpcClientCA = flags.String("grpc-client-ca", "", "GRPC_TLS_CLIENT_CA")
+	configFlags.grpcReflection = flags.String("grpc-reflection", "", "GRPC_REFLECTION")
+	configFlags.gatewayPrefix = flags.String("gw", "", "GATEWAY_PREFIX")
+	configFlags.rateShorten = flags.String("rl-shorten", "", "RATE_LIMIT_SHORTEN")
+	configFlags.rateBatch = flags.String("rl-batch", "", "RATE_LIMIT_BATCH")
+	configFlags.rateRedirect = flags.String("rl-redirect", "", "RATE_LIMIT_REDIRECT")
+	configFlags.rateShortenIP = flags.String("rl-shorten-ip", "", "RATE_LIMIT_SHORTEN_IP")
+	configFlags.rateBatchIP = flags.String("rl-batch-ip", "", "RATE_LIMIT_BATCH_IP")
+	configFlags.rateRedirectIP = flags.String("rl-redirect-ip", "", "RATE_LIMIT_REDIRECT_IP")
+	configFlags.rateUnlock = flags.String("rl-unlock", "", "RATE_LIMIT_UNLOCK")
+	configFlags.rateUnlockIP = flags.String("rl-unlock-ip", "", "RATE_LIMIT_UNLOCK_IP")
+	configFlags.idempotencyTTL = flags.String("idempotency-ttl", "", "IDEMPOTENCY_TTL")
+	configFlags.geoDBPath = flags.String("geo-db", "", "GEO_DB_PATH")
+	configFlags.domains = flags.String("domains", "", "DOMAINS")
+	configFlags.linkInterval = flags.String("link-check-interval", "", "LINK_CHECK_INTERVAL")
+	configFlags.linkConcurrency = flags.String("link-check-concurrency", "", "LINK_CHECK_CONCURRENCY")
 	// define configs flags
 	conf1 := flags.String("c", "", "CONFIG")
 	conf2 := flags.String("config", "", "CONFIG")
@@ -345,4 +777,7 @@ func (a *AppConfig) checkOptions() {
 	if !strings.HasSuffix(a.baseURL, "/") {
 		a.baseURL += "/"
 	}
+
+	// The gateway prefix must start with a slash and must not end with it.
+	a.gatewayPrefix = "/" + strings.Trim(a.gatewayPrefix, "/")
 }
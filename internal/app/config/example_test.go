@@ -20,3 +20,29 @@ func Example() {
 	// Output:
 	// localhost:8080
 }
+
+func ExampleParseRateLimit() {
+	// Parse 100 requests per minute
+	limit, err := config.ParseRateLimit("100/m")
+	if err != nil {
+		return
+	}
+
+	fmt.Println(limit.Requests, limit.Period)
+
+	// Output:
+	// 100 1m0s
+}
+
+func ExampleAppConfig_Err() {
+	appConf := config.New(config.Options{})
+	appConf.DefineOptionsFlags([]string{"shortener", "-rl-shorten", "100/d"})
+
+	// The invalid limit is skipped, the default limit is kept
+	fmt.Println(appConf.GetRateLimits().Shorten.Requests)
+	fmt.Println(appConf.Err())
+
+	// Output:
+	// 100
+	// rate limit is invalid, should be: "<requests>/<s|m|h>": "100/d"
+}
@@ -0,0 +1,120 @@
+// Package geo resolves the country of the client IP address
+// from the local database file without external calls.
+//
+// The database is a CSV file with the network in CIDR notation
+// and the ISO 3166-1 alpha-2 country code on each line, e.g.
+//
+//	network,country
+//	192.0.2.0/24,DE
+//	2001:db8::/32,FR
+//
+// The header line and the lines starting with "#" are skipped.
+// The most specific network containing the address wins.
+package geo
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidRecord is an indicator that the line of the database is invalid.
+var ErrInvalidRecord = errors.New("geo database record must be: <network>,<country code>")
+
+// DB represents the geo database, the zero value contains no networks.
+type DB struct {
+	// networks by the prefix length.
+	networks map[int]map[netip.Prefix]string
+	// bits are the prefix lengths in the descending order.
+	bits []int
+}
+
+// Open reads the geo database from the file.
+func Open(path string) (*DB, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed open geo database: %w", err)
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+// Read reads the geo database in CSV format.
+func Read(reader io.Reader) (*DB, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	db := DB{networks: make(map[int]map[netip.Prefix]string)}
+
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && len(record) > 0 && strings.EqualFold(record[0], "network") {
+			continue
+		}
+
+		if len(record) < 2 || record[1] == "" {
+			return nil, fmt.Errorf("line %d: %w", line, ErrInvalidRecord)
+		}
+
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, ErrInvalidRecord)
+		}
+
+		db.add(prefix.Masked(), strings.ToUpper(strings.TrimSpace(record[1])))
+	}
+
+	return &db, nil
+}
+
+// Country returns the country code of the address.
+func (db *DB) Country(addr netip.Addr) (string, bool) {
+	if db == nil || !addr.IsValid() {
+		return "", false
+	}
+
+	addr = addr.Unmap()
+
+	for _, bits := range db.bits {
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			continue
+		}
+
+		if country, ok := db.networks[bits][prefix]; ok {
+			return country, true
+		}
+	}
+
+	return "", false
+}
+
+// add adds the network, IPv4 and IPv6 prefixes of the same length
+// are stored together since their addresses never match each other.
+func (db *DB) add(prefix netip.Prefix, country string) {
+	bits := prefix.Bits()
+
+	if _, ok := db.networks[bits]; !ok {
+		db.networks[bits] = make(map[netip.Prefix]string)
+		db.bits = append(db.bits, bits)
+		sort.Sort(sort.Reverse(sort.IntSlice(db.bits)))
+	}
+
+	db.networks[bits][prefix] = country
+}
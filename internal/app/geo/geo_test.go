@@ -0,0 +1,48 @@
+package geo_test
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/geo"
+)
+
+func TestCountry(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	db, err := geo.Read(strings.NewReader("network,country\n# test networks\n" +
+		"192.0.2.0/24,de\n192.0.2.128/25,AT\n2001:db8::/32,FR\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addr    string
+		country string
+		found   bool
+	}{
+		{addr: "192.0.2.1", country: "DE", found: true},
+		{addr: "192.0.2.200", country: "AT", found: true},
+		{addr: "::ffff:192.0.2.1", country: "DE", found: true},
+		{addr: "2001:db8::1", country: "FR", found: true},
+		{addr: "198.51.100.1", country: "", found: false},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.addr, func(t *testing.T) {
+			t.Parallel()
+
+			country, found := db.Country(netip.MustParseAddr(item.addr))
+			if country != item.country || found != item.found {
+				t.Errorf("country should be %q but received %q", item.country, country)
+			}
+		})
+	}
+
+	if _, err := geo.Read(strings.NewReader("192.0.2.0,DE\n")); !errors.Is(err, geo.ErrInvalidRecord) {
+		t.Errorf("error should be %v but received %v", geo.ErrInvalidRecord, err)
+	}
+}
@@ -4,199 +4,233 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
 	"net/http"
-	"net/netip"
+	"strconv"
 
-	"github.com/alaleks/shortener/internal/app/service"
-	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/qr"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
+	"github.com/alaleks/shortener/internal/app/usecase"
 	"github.com/gorilla/mux"
 )
 
+// headerIdempotencyKey is the header with the key of the idempotent request.
+const headerIdempotencyKey = "Idempotency-Key"
+
 // ShortenURLAPI implements URL shortening.
 //
 // The handler returns an abbreviated URL in the response body.
-// POST /api/shorten, JSON: {"url":"http://github.com/alaleks/shortener"}.
+// If the URL has already been shortened, the response code 409
+// is returned with the existing short URL. With "qr_code":true
+// the QR code of the short URL is returned as the data URI.
+// POST /api/shorten, JSON: {"url":"http://github.com/alaleks/shortener",
+// "tags":["go"],"folder":"spring","utm":{"utm_source":"newsletter"},"forward_query":true,
+// "rules":[{"device":"ios","url":"https://apps.apple.com/app/id1"}],"password":"secret","max_clicks":1,"domain":"go.example.com"}.
 func (h *Handlers) ShortenURLAPI(writer http.ResponseWriter, req *http.Request) {
 	var (
 		input      InputShorten
-		output     OutputShorten
-		userID     string
 		httpStatus = http.StatusCreated
 	)
 
 	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
-		output.Err = err.Error()
-	}
-
-	if err := service.IsURL(input.URL); err != nil {
-		output.Err = ErrInvalidRequest.Error()
-	}
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, ErrInvalidRequest)
 
-	if req.URL.User != nil {
-		userID = req.URL.User.Username()
+		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
+	shortURL, err := h.Service.Shorten(userID(req), input.URL, usecase.ShortenOptions{
+		Tags:         input.Tags,
+		Folder:       input.Folder,
+		UTM:          input.UTM,
+		ForwardQuery: input.ForwardQuery,
+		Rules:        input.Rules,
+		Variants:     input.Variants,
+		Password:     input.Password,
+		MaxClicks:    input.MaxClicks,
+		Domain:       input.Domain,
+	})
 
-	shortURL, err := h.Storage.St.Add(input.URL, userID)
-	if err != nil {
-		if errors.Is(err, storage.ErrAlreadyExists) {
-			httpStatus = http.StatusConflict
-		} else {
-			writer.WriteHeader(http.StatusInternalServerError)
+	switch {
+	case err == nil:
+	case errors.Is(err, usecase.ErrConflict):
+		httpStatus = http.StatusConflict
+	default:
+		writeProblem(writer, req, err)
 
-			return
-		}
+		return
 	}
 
-	writer.WriteHeader(httpStatus)
+	output := OutputShorten{Result: shortURL, Success: true}
 
-	if output.Err == "" {
-		output.Success = true
-		output.Result = shortURL
-	}
-
-	var buf bytes.Buffer
+	if input.QRCode {
+		img, err := h.Service.QRCode(shortURL, qr.DefaultOptions())
+		if err != nil {
+			writeProblem(writer, req, err)
 
-	if err := json.NewEncoder(&buf).Encode(output); err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		return
+		output.QRCode = img.DataURI()
 	}
 
-	if _, err := writer.Write(buf.Bytes()); err != nil {
-		http.Error(writer, ErrInternalError.Error(), http.StatusBadRequest)
-
-		return
-	}
+	writeJSON(writer, req, httpStatus, output)
 }
 
 // GetStatAPI implements getting statistics on the use of a short URL.
 //
-// Example: GET /api/{uid}/statistics
+// The short URL on the custom domain is passed with the domain parameter.
+// Example: GET /api/{uid}/statistics?domain=go.example.com
 func (h *Handlers) GetStatAPI(writer http.ResponseWriter, req *http.Request) {
-	var (
-		buffer bytes.Buffer
-		uid    = mux.Vars(req)["uid"]
-	)
+	uid := mux.Vars(req)["uid"]
 
 	if uid == "" {
-		http.Error(writer, ErrEmptyURL.Error(), http.StatusBadRequest)
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidInput, ErrEmptyURL)
 
 		return
 	}
 
-	stat, err := h.Storage.St.Stat(uid)
+	shortUID, err := h.Service.DomainUID(req.URL.Query().Get("domain"), uid)
 	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+		writeProblem(writer, req, err)
 
 		return
 	}
 
-	if err := json.NewEncoder(&buffer).Encode(stat); err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	stat, err := h.Service.Stat(shortUID)
+	if err != nil {
+		writeProblem(writer, req, err)
 
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
-
-	if _, err := writer.Write(buffer.Bytes()); err != nil {
-		http.Error(writer, ErrInternalError.Error(), http.StatusBadRequest)
-
-		return
-	}
+	writeJSON(writer, req, http.StatusOK, stat)
 }
 
-// GetUsersURL returns all shortened URLs for current user.
+// GetUsersURL returns the shortened URLs for current user page by page.
 //
+// The query parameters are limit and cursor for pagination,
+// sort (created_at or clicks, "-" prefix for the descending order)
+// and the filters created_from, created_to, domain, status
+// (active, removed or all), tag, folder and broken (true to get only URLs
+// whose destination was found dead by the last check). The number of URLs matching
+// the filters is returned in the X-Total-Count header, the cursor of
+// the next page in the X-Next-Cursor header and the Link header.
 // If the user is not defined or don`t has shortens urls,
 // the response code 204 is returned.
-// GET /api/user/urls
+// GET /api/user/urls?limit=100&sort=-clicks&domain=github.com
 func (h *Handlers) GetUsersURL(writer http.ResponseWriter, req *http.Request) {
-	var buffer bytes.Buffer
-
-	if req.URL.User == nil {
-		writer.WriteHeader(http.StatusNoContent)
+	query := req.URL.Query()
+
+	opts, err := usecase.ListQuery{
+		Cursor:      query.Get("cursor"),
+		Limit:       query.Get("limit"),
+		Sort:        query.Get("sort"),
+		CreatedFrom: query.Get("created_from"),
+		CreatedTo:   query.Get("created_to"),
+		Domain:      query.Get("domain"),
+		Status:      query.Get("status"),
+		Tag:         query.Get("tag"),
+		Folder:      query.Get("folder"),
+		Broken:      query.Get("broken"),
+	}.Options()
+	if err != nil {
+		writeProblem(writer, req, err)
 
 		return
 	}
 
-	out, err := h.Storage.St.GetUrlsUser(req.URL.User.Username())
-	if err != nil || out == nil {
+	page, err := h.Service.ListPage(userID(req), opts)
+
+	switch {
+	case err == nil:
+		writer.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	case errors.Is(err, usecase.ErrNotFound), errors.Is(err, usecase.ErrUnauthorized):
 		writer.WriteHeader(http.StatusNoContent)
 
 		return
-	}
-
-	if err := json.NewEncoder(&buffer).Encode(out); err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	default:
+		writeProblem(writer, req, err)
 
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
+	if page.NextCursor != "" {
+		next := *req.URL
+		query.Set("cursor", page.NextCursor)
+		next.RawQuery = query.Encode()
+		next.User = nil
+
+		writer.Header().Set("X-Next-Cursor", page.NextCursor)
+		writer.Header().Set("Link", "<"+next.RequestURI()+`>; rel="next"`)
+	}
 
-	if _, err := writer.Write(buffer.Bytes()); err != nil {
-		http.Error(writer, ErrInternalError.Error(), http.StatusBadRequest)
+	if len(page.URLs) == 0 {
+		writer.WriteHeader(http.StatusNoContent)
 
 		return
 	}
+
+	writeJSON(writer, req, http.StatusOK, page.URLs)
 }
 
 // ShortenURLBatch implements url batch shortening.
 //
-// POST /api/shorten/batch
+// The response to the request with the Idempotency-Key header is stored
+// and returned to the retried requests with the same key.
+// With atomic=true no URL is shortened if any URL is invalid.
+// POST /api/shorten/batch?atomic=true
 // JSON: [{"original_url":"http://github.com/alaleks/shortener", "correlation_id":1}]
 func (h *Handlers) ShortenURLBatch(writer http.ResponseWriter, req *http.Request) {
-	var (
-		input  []InShortenBatch
-		userID string
-	)
+	var input []InShortenBatch
 
-	if req.URL.User != nil {
-		userID = req.URL.User.Username()
-	}
-
-	body, err := io.ReadAll(req.Body)
-	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, err)
 
 		return
 	}
 
-	err = json.Unmarshal(body, &input)
+	opts := usecase.BatchOptions{IdempotencyKey: req.Header.Get(headerIdempotencyKey)}
 
-	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	if atomic := req.URL.Query().Get("atomic"); atomic != "" {
+		var err error
 
-		return
+		if opts.Atomic, err = strconv.ParseBool(atomic); err != nil {
+			writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidInput, err)
+
+			return
+		}
 	}
 
-	output, err := h.processingURLBatch(userID, input)
-	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	items := make([]usecase.BatchItem, 0, len(input))
 
-		return
+	for _, item := range input {
+		items = append(items, usecase.BatchItem{CorID: item.CorID, OriginalURL: item.OriginalURL})
 	}
 
-	res, err := json.MarshalIndent(output, " ", "  ")
-	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	results, err := h.Service.ShortenBatch(userID(req), items, opts)
+
+	switch {
+	case errors.Is(err, usecase.ErrBatchRejected):
+		writeBatchRejected(writer, req, results, err)
+
+		return
+	case err != nil:
+		writeProblem(writer, req, err)
 
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusCreated)
+	output := make([]OutShortenBatch, 0, len(results))
 
-	if _, err := writer.Write(res); err != nil {
-		http.Error(writer, ErrInternalError.Error(), http.StatusBadRequest)
+	for _, item := range results {
+		out := OutShortenBatch{CorID: item.CorID, ShortURL: item.ShortURL}
+		if item.Err != nil {
+			out.Err = item.Err.Error()
+		}
 
-		return
+		output = append(output, out)
 	}
+
+	writeJSON(writer, req, http.StatusCreated, output)
 }
 
 // ShortenDelete performs deletion all shortened URLs
@@ -205,23 +239,16 @@ func (h *Handlers) ShortenURLBatch(writer http.ResponseWriter, req *http.Request
 // This handler does not use a pool.
 // DELETE /api/user/urls
 func (h *Handlers) ShortenDelete(writer http.ResponseWriter, req *http.Request) {
-	var (
-		userID         string
-		shortUIDForDel []string
-	)
-
-	if req.URL.User != nil {
-		userID = req.URL.User.Username()
-	}
+	var shortUIDForDel []string
 
 	if err := json.NewDecoder(req.Body).Decode(&shortUIDForDel); err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, err)
 
 		return
 	}
 
-	if err := h.Storage.St.DelUrls(userID, checkShortUID(shortUIDForDel...)...); err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	if err := h.Service.Delete(userID(req), shortUIDForDel...); err != nil {
+		writeProblem(writer, req, err)
 
 		return
 	}
@@ -235,70 +262,54 @@ func (h *Handlers) ShortenDelete(writer http.ResponseWriter, req *http.Request)
 // This handler use a pool.
 // DELETE /api/user/urls
 func (h *Handlers) ShortenDeletePool(writer http.ResponseWriter, req *http.Request) {
-	var data struct {
-		userID         string
-		shortUIDForDel []string
-	}
-
-	if req.URL.User != nil {
-		data.userID = req.URL.User.Username()
-	}
+	var shortUIDForDel []string
 
-	if err := json.NewDecoder(req.Body).Decode(&data.shortUIDForDel); err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	if err := json.NewDecoder(req.Body).Decode(&shortUIDForDel); err != nil {
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, err)
 
 		return
 	}
 
-	h.Storage.Pool.AddTask(func() error {
-		err := h.Storage.St.DelUrls(data.userID,
-			checkShortUID(data.shortUIDForDel...)...)
-		if err != nil {
-			return fmt.Errorf("deletion error: %w", err)
-		}
+	if err := h.Service.DeleteAsync(userID(req), shortUIDForDel...); err != nil {
+		writeProblem(writer, req, err)
 
-		return nil
-	})
+		return
+	}
 
 	writer.WriteHeader(http.StatusAccepted)
 }
 
 // StatsInternal implement getting data about the number of shortened URLs
 // and the number of users in the app.
+//
+// Access is allowed only to clients from the trusted subnets,
+// the client IP address is resolved by the realip middleware.
 // GET /api/internal/stats
 func (h *Handlers) StatsInternal(writer http.ResponseWriter, req *http.Request) {
-	realIP, err := netip.ParseAddr(req.Header.Get("X-Real-IP"))
-	if err != nil {
-		http.Error(writer, ErrAccessTrustedSubnet.Error(), http.StatusForbidden)
-
-		return
-	}
+	realIP, _ := realip.FromRequest(req)
 
-	if !h.trustedSubnets.Contains(realIP) {
-		http.Error(writer, ErrAccessTrustedSubnet.Error(), http.StatusForbidden)
+	stat, err := h.Service.InternalStats(realIP)
+	if err != nil {
+		writeProblem(writer, req, err)
 
 		return
 	}
 
-	stat, err := h.Storage.St.GetInternalStats()
-	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	writeJSON(writer, req, http.StatusOK, stat)
+}
 
-		return
-	}
+// writeJSON writes the response in JSON with the status code.
+func writeJSON(writer http.ResponseWriter, req *http.Request, status int, data any) {
+	var buffer bytes.Buffer
 
-	res, err := json.MarshalIndent(stat, " ", "  ")
-	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+	if err := json.NewEncoder(&buffer).Encode(data); err != nil {
+		writeProblem(writer, req, err)
 
 		return
 	}
 
 	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
 
-	if _, err := writer.Write(res); err != nil {
-		http.Error(writer, ErrInternalError.Error(), http.StatusBadRequest)
-
-		return
-	}
+	_, _ = writer.Write(buffer.Bytes())
 }
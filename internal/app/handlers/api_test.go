@@ -17,6 +17,7 @@ import (
 	"github.com/alaleks/shortener/internal/app/serv/middleware"
 	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
 	"github.com/alaleks/shortener/internal/app/serv/middleware/compress"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
 	"github.com/alaleks/shortener/internal/app/storage"
 )
 
@@ -86,11 +87,8 @@ func TestGetStatAPI(t *testing.T) {
 	longURL1 := "https://github.com/alaleks/shortener"
 	longURL2 := "https://yandex.ru/pogoda/krasnodar"
 	// добавляем длинные ссылки в хранилище
-	shortURL1, _ := testHandler.Storage.St.Add(longURL1, "")
-	uid1 := strings.SplitAfterN(shortURL1, "/", -1)[3]
-
-	shortURL2, _ := testHandler.Storage.St.Add(longURL2, "")
-	uid2 := strings.SplitAfterN(shortURL2, "/", -1)[3]
+	uid1, _ := testHandler.Storage.St.Add(longURL1, "")
+	uid2, _ := testHandler.Storage.St.Add(longURL2, "")
 
 	hostStat := appConf.GetBaseURL() + "api/"
 	// для uid1 изменяем статистику
@@ -106,7 +104,7 @@ func TestGetStatAPI(t *testing.T) {
 	}{
 		{name: "стат uid #1", code: 200, stat: 1, uriStat: hostStat + uid1 + "/statistics"},
 		{name: "стат uid #2", code: 200, stat: 0, uriStat: hostStat + uid2 + "/statistics"},
-		{name: "стат некорректной короткой ссылки", code: 400, uriStat: hostStat + "badId/statistics"},
+		{name: "стат некорректной короткой ссылки", code: 404, uriStat: hostStat + "badId/statistics"},
 	}
 
 	// тестируем
@@ -391,34 +389,59 @@ func TestGetUsersURL(t *testing.T) {
 
 func TestStatsInternal(t *testing.T) {
 	// устанавливаем переменные окружения
-	t.Setenv("TRUSTED_SUBNET", "172.17.0.0/24")
+	t.Setenv("TRUSTED_SUBNET", "172.17.0.0/24, fd00::/8")
 
 	// настройки для теста
 	appConf := config.New(config.Options{Env: true, Flag: false})
 	st := storage.InitStore(appConf, nil)
 	testHandler := handlers.New(appConf, nil, st)
 	uri := appConf.GetBaseURL() + "/api/internal/stats"
+	// httptest.NewRequest использует адрес 192.0.2.1
+	trustedProxy := realip.New(realip.ParseSubnets("192.0.2.0/24"))
+	noProxy := realip.New(nil)
 
 	// данные для теста
 	tests := []struct {
-		name string
-		ip   string
-		code int
+		name     string
+		header   string
+		ip       string
+		resolver *realip.Resolver
+		code     int
 	}{
 		{
-			name: "ip входит в доверенную подсеть",
-			code: 200,
-			ip:   "172.17.0.2",
+			name:     "ip входит в доверенную подсеть",
+			code:     200,
+			header:   "X-Real-IP",
+			ip:       "172.17.0.2",
+			resolver: trustedProxy,
+		},
+		{
+			name:     "ipv6 входит в доверенную подсеть",
+			code:     200,
+			header:   "X-Forwarded-For",
+			ip:       "fd00::2",
+			resolver: trustedProxy,
+		},
+		{
+			name:     "ip не входит в доверенную подсеть",
+			code:     403,
+			header:   "X-Real-IP",
+			ip:       "172.17.1.2",
+			resolver: trustedProxy,
 		},
 		{
-			name: "ip не входит в доверенную подсеть",
-			code: 403,
-			ip:   "172.17.1.2",
+			name:     "ip имеет невалидный формат",
+			code:     403,
+			header:   "X-Real-IP",
+			ip:       "wrong",
+			resolver: trustedProxy,
 		},
 		{
-			name: "ip имеет невалидный формат",
-			code: 403,
-			ip:   "wrong",
+			name:     "заголовок подделан клиентом без доверенного прокси",
+			code:     403,
+			header:   "X-Real-IP",
+			ip:       "172.17.0.2",
+			resolver: noProxy,
 		},
 	}
 
@@ -429,11 +452,11 @@ func TestStatsInternal(t *testing.T) {
 
 			// создаем запрос, рекордер, хэндлер, запускаем сервер
 			testRec := httptest.NewRecorder()
-			h := middleware.New(compress.Compression, compress.Decompression).
+			h := middleware.New(item.resolver.RealIP, compress.Compression, compress.Decompression).
 				Configure(http.HandlerFunc(testHandler.StatsInternal))
 			req := httptest.NewRequest(http.MethodGet, uri, nil)
 			req.Header.Set("Content-Type", "application/json")
-			req.Header.Set("X-Real-IP", item.ip)
+			req.Header.Set(item.header, item.ip)
 			h.ServeHTTP(testRec, req)
 			res := testRec.Result()
 			if res != nil {
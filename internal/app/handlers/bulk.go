@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/bulk"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+const (
+	// bulkTimeout is the read and write timeout of the export requests,
+	// which exceed the default timeouts of the server.
+	bulkTimeout = 5 * time.Minute
+	// importTimeout is the read and write timeout of the import requests,
+	// it's extended after each imported chunk of rows.
+	importTimeout = 30 * time.Second
+	// maxImportSize is the maximum size of the imported data in bytes.
+	maxImportSize = 32 << 20
+)
+
+// ImportURLs implements the bulk import of URLs in CSV or NDJSON.
+//
+// The format is defined by the Content-Type header (text/csv or
+// application/x-ndjson) or the format query parameter. The rows are
+// shortened in chunks and the result of each row is streamed in NDJSON
+// after its chunk. If the data can't be read after the response is started
+// (e.g. the body is larger than maxImportSize), the last result contains
+// the error and the stopped flag, the rows before it have been imported.
+// POST /api/user/urls/import
+// CSV: original_url,alias,correlation_id,expires_at.
+// NDJSON: {"original_url":"http://github.com","alias":"gh","expires_at":"2030-01-01T00:00:00Z"}.
+func (h *Handlers) ImportURLs(writer http.ResponseWriter, req *http.Request) {
+	format, err := bulk.FormatByContentType(req.Header.Get("Content-Type"))
+	if name := req.URL.Query().Get("format"); name != "" {
+		format, err = bulk.ParseFormat(name)
+	}
+
+	if err != nil {
+		writeProblemCode(writer, req, http.StatusUnsupportedMediaType, CodeInvalidInput, err)
+
+		return
+	}
+
+	extendDeadlines(writer, importTimeout)
+
+	reader, err := bulk.NewReader(http.MaxBytesReader(writer, req.Body, maxImportSize), format)
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	var (
+		controller = http.NewResponseController(writer)
+		encoder    = json.NewEncoder(writer)
+		started    bool
+		lastRow    int
+	)
+
+	start := func() {
+		if !started {
+			started = true
+
+			writer.Header().Set("Content-Type", bulk.NDJSON.ContentType())
+			writer.WriteHeader(http.StatusOK)
+		}
+	}
+
+	err = bulk.Import(h.Service, userID(req), reader, func(result bulk.Result) error {
+		start()
+		lastRow = result.Row
+
+		return encoder.Encode(result)
+	}, func() {
+		_ = controller.Flush()
+		extendDeadlines(writer, importTimeout)
+	})
+
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case err == nil:
+		start()
+	case !started && errors.As(err, &maxBytesErr):
+		writeProblemCode(writer, req, http.StatusRequestEntityTooLarge, CodeInvalidInput, err)
+	case !started:
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidInput, err)
+	default:
+		_ = encoder.Encode(bulk.Result{Row: lastRow + 1, Err: err.Error(), Stopped: true})
+	}
+}
+
+// ExportURLs streams all URLs of current user with their statistics
+// in CSV or NDJSON.
+//
+// If the export fails after the response is started,
+// the connection is aborted to signal the incomplete data.
+// GET /api/user/urls/export?format=csv|ndjson
+func (h *Handlers) ExportURLs(writer http.ResponseWriter, req *http.Request) {
+	format, err := bulk.ParseFormat(req.URL.Query().Get("format"))
+	if err != nil {
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidInput, err)
+
+		return
+	}
+
+	uid := userID(req)
+	if uid == "" {
+		writeProblemCode(writer, req, http.StatusUnauthorized, CodeUnauthorized, usecase.ErrUnauthorized)
+
+		return
+	}
+
+	extendDeadlines(writer, bulkTimeout)
+
+	out, err := bulk.NewWriter(writer, format)
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	controller := http.NewResponseController(writer)
+
+	writer.Header().Set("Content-Type", format.ContentType())
+	writer.Header().Set("Content-Disposition", `attachment; filename="urls.`+string(format)+`"`)
+	writer.WriteHeader(http.StatusOK)
+
+	if err := bulk.Export(h.Service, uid, out, func() { _ = controller.Flush() }); err != nil {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// extendDeadlines extends the read and write deadlines of the connection
+// for long requests, errors are ignored if the writer doesn't support it.
+func extendDeadlines(writer http.ResponseWriter, timeout time.Duration) {
+	controller := http.NewResponseController(writer)
+	deadline := time.Now().Add(timeout)
+
+	_ = controller.SetReadDeadline(deadline)
+	_ = controller.SetWriteDeadline(deadline)
+}
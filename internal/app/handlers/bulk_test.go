@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/alaleks/shortener/internal/app/bulk"
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/serv/middleware"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+func TestImportExportURLs(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	st := storage.InitStore(appConf, logger)
+	testHandler := handlers.New(appConf, logger, st)
+	authorization := auth.TurnOn(testHandler.Storage.St, appConf.GetSecretKey())
+	routers := middleware.New(authorization.Authorization).Configure(router.Create(testHandler))
+
+	// импорт ссылок нового пользователя
+	req := httptest.NewRequest(http.MethodPost, "/api/user/urls/import",
+		strings.NewReader("http://github.com/alaleks/shortener,,1\nwrong,,2\n"))
+	req.Header.Set("Content-Type", "text/csv")
+
+	testRec := httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	res := testRec.Result()
+	defer res.Body.Close()
+
+	results := decodeResults(t, res.Body)
+
+	if res.StatusCode != http.StatusOK || len(results) != 2 || results[0].Err != "" || results[1].Err == "" {
+		t.Fatalf("import should return status %d and 2 results but received %d and %+v",
+			http.StatusOK, res.StatusCode, results)
+	}
+
+	// экспорт ссылок этого же пользователя
+	req = httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=ndjson", nil)
+	req.Header.Set("Cookie", res.Header.Get("Set-Cookie"))
+
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	res2 := testRec.Result()
+	defer res2.Body.Close()
+
+	body, _ := io.ReadAll(res2.Body)
+
+	if res2.StatusCode != http.StatusOK || strings.Count(string(body), "\n") != 1 ||
+		!strings.Contains(string(body), `"correlation_id":"1"`) {
+		t.Errorf("export should return status %d and 1 URL but received %d and %s",
+			http.StatusOK, res2.StatusCode, body)
+	}
+
+	// при ошибке чтения возвращаются результаты импортированных строк
+	req = httptest.NewRequest(http.MethodPost, "/api/user/urls/import",
+		io.MultiReader(strings.NewReader("http://go.dev,,1\n"), iotest.ErrReader(errors.New("connection reset"))))
+	req.Header.Set("Content-Type", "text/csv")
+
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	results = decodeResults(t, testRec.Body)
+	if testRec.Code != http.StatusOK || len(results) != 2 || results[0].ShortURL == "" ||
+		!results[1].Stopped || results[1].Row != 2 || results[1].Err == "" {
+		t.Errorf("import should return imported row and stopped result but received %d and %+v", testRec.Code, results)
+	}
+
+	// слишком большое тело запроса
+	req = httptest.NewRequest(http.MethodPost, "/api/user/urls/import",
+		strings.NewReader(`"`+strings.Repeat("a", 32<<20)))
+	req.Header.Set("Content-Type", "text/csv")
+
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	if testRec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status code should be %d but received %d", http.StatusRequestEntityTooLarge, testRec.Code)
+	}
+
+	// неизвестный формат
+	req = httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=xml", nil)
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	if testRec.Code != http.StatusBadRequest {
+		t.Errorf("status code should be %d but received %d", http.StatusBadRequest, testRec.Code)
+	}
+}
+
+// decodeResults returns the results of the import in NDJSON.
+func decodeResults(t *testing.T, body io.Reader) []bulk.Result {
+	t.Helper()
+
+	var results []bulk.Result
+
+	decoder := json.NewDecoder(body)
+
+	for decoder.More() {
+		var result bulk.Result
+		if err := decoder.Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
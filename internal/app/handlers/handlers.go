@@ -1,4 +1,7 @@
 // Package handlers implements application route handlers
+//
+// Errors are returned as RFC 7807 problem details (application/problem+json)
+// with the stable error code in the "code" member.
 package handlers
 
 import (
@@ -7,8 +10,8 @@ import (
 	"io"
 	"net/http"
 
-	"github.com/alaleks/shortener/internal/app/service"
-	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
+	"github.com/alaleks/shortener/internal/app/usecase"
 	"github.com/gorilla/mux"
 )
 
@@ -16,77 +19,100 @@ import (
 //
 // POST /, text: "http://github.com/alaleks/shortener".
 func (h *Handlers) ShortenURL(writer http.ResponseWriter, req *http.Request) {
-	var userID string
-
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidInput, err)
 
 		return
 	}
 
-	longURL := string(bytes.TrimSpace(body))
-	err = service.IsURL(longURL)
-
-	if err != nil {
-		http.Error(writer, err.Error(), http.StatusBadRequest)
-
-		return
-	}
+	shortURL, err := h.Service.Shorten(userID(req), string(bytes.TrimSpace(body)), usecase.ShortenOptions{})
 
-	if req.URL.User != nil {
-		userID = req.URL.User.Username()
-	}
-
-	shortURL, err := h.Storage.St.Add(longURL, userID)
-
-	if err != nil {
-		if errors.Is(err, storage.ErrAlreadyExists) {
-			writer.WriteHeader(http.StatusConflict)
-		} else {
-			writer.WriteHeader(http.StatusInternalServerError)
-
-			return
-		}
-	} else {
+	switch {
+	case err == nil:
 		writer.WriteHeader(http.StatusCreated)
-	}
-
-	if _, err := writer.Write([]byte(shortURL)); err != nil {
-		http.Error(writer, ErrInternalError.Error(), http.StatusBadRequest)
+	// the existing short URL is returned in the body.
+	case errors.Is(err, usecase.ErrConflict):
+		writer.WriteHeader(http.StatusConflict)
+	default:
+		writeProblem(writer, req, err)
 
 		return
 	}
+
+	_, _ = writer.Write([]byte(shortURL))
 }
 
+// The cookie keeping the variant of the short URL served to the client.
+const (
+	variantCookiePrefix = "variant_"
+	variantCookieMaxAge = 30 * 24 * 60 * 60
+)
+
 // ParseShortURL takes a short URL and redirects at the original URL.
 //
-// GET /{uid}
+// The client is redirected by the first matching targeting rule of
+// the short URL (device, language, country or network of the client)
+// to one of the weighted variants or to the original URL. The served variant
+// is kept for the client in the cookie. The password form is shown
+// for the protected short URL until the password is entered. The UTM parameters of the short URL
+// are added to the target URL, the query of the request is passed
+// if forwarding is enabled. The short URLs on the custom domains
+// are found by the Host header.
+// GET /{uid}?ref=x
 func (h *Handlers) ParseShortURL(writer http.ResponseWriter, req *http.Request) {
 	uid := mux.Vars(req)["uid"]
 
 	if uid == "" {
-		http.Error(writer, ErrEmptyURL.Error(), http.StatusBadRequest)
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidInput, ErrEmptyURL)
 
 		return
 	}
 
-	longURL, err := h.Storage.St.GetURL(uid)
-	if err != nil {
-		status := http.StatusBadRequest
+	clientIP, _ := realip.FromRequest(req)
+
+	visit := usecase.Visit{
+		Query:          req.URL.Query(),
+		IP:             clientIP,
+		UserAgent:      req.UserAgent(),
+		AcceptLanguage: req.Header.Get("Accept-Language"),
+		Host:           req.Host,
+	}
+
+	if cookie, err := req.Cookie(variantCookiePrefix + uid); err == nil {
+		visit.Variant = cookie.Value
+	}
+
+	if cookie, err := req.Cookie(unlockCookiePrefix + uid); err == nil {
+		visit.Unlocked = h.signer.ReadLinkSigning(cookie.Value, h.Service.ShortUID(req.Host, uid)) == nil
+	}
 
-		if errors.Is(err, storage.ErrShortURLRemoved) {
-			status = http.StatusGone
-		}
+	redirect, err := h.Service.Resolve(uid, visit)
 
-		http.Error(writer, err.Error(), status)
+	switch {
+	case err == nil:
+	case errors.Is(err, usecase.ErrPasswordRequired):
+		writePasswordForm(writer, req, http.StatusOK, "")
+
+		return
+	default:
+		writeProblem(writer, req, err)
 
 		return
 	}
 
-	h.Storage.St.Update(uid)
+	if redirect.Variant != "" && redirect.Variant != visit.Variant {
+		http.SetCookie(writer, &http.Cookie{
+			Name:     variantCookiePrefix + uid,
+			Value:    redirect.Variant,
+			Path:     "/" + uid,
+			MaxAge:   variantCookieMaxAge,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
 
-	writer.Header().Set("Location", longURL)
+	writer.Header().Set("Location", redirect.URL)
 	writer.WriteHeader(http.StatusTemporaryRedirect)
 }
 
@@ -95,8 +121,17 @@ func (h *Handlers) ParseShortURL(writer http.ResponseWriter, req *http.Request)
 // GET /ping
 func (h *Handlers) Ping(writer http.ResponseWriter, req *http.Request) {
 	if err := h.Storage.St.Ping(); err != nil {
-		writer.WriteHeader(http.StatusInternalServerError)
+		writeProblemCode(writer, req, http.StatusInternalServerError, CodeStorageUnavailable, err)
 
 		return
 	}
 }
+
+// userID returns the user ID set by the authorization middleware.
+func userID(req *http.Request) string {
+	if req.URL.User == nil {
+		return ""
+	}
+
+	return req.URL.User.Username()
+}
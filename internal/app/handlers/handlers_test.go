@@ -79,7 +79,8 @@ func TestParseShortURL(t *testing.T) {
 	st := storage.InitStore(appConf, logger)
 	testHandler := handlers.New(appConf, logger, st)
 	longURL := "https://github.com/alaleks/shortener"
-	shortURL, _ := testHandler.Storage.St.Add(longURL, "1")
+	shortUID, _ := testHandler.Storage.St.Add(longURL, "1")
+	shortURL := appConf.GetBaseURL() + shortUID
 	routers := router.Create(testHandler)
 
 	tests := []struct {
@@ -97,7 +98,7 @@ func TestParseShortURL(t *testing.T) {
 			longURL: "", shortURL: appConf.GetBaseURL(),
 		},
 		{
-			name: "парсинг некорректной короткой ссылки - 2", code: 400,
+			name: "парсинг некорректной короткой ссылки - 2", code: 404,
 			longURL: "", shortURL: appConf.GetBaseURL() + "badId",
 		},
 	}
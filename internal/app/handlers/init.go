@@ -2,17 +2,23 @@ package handlers
 
 import (
 	"errors"
-	"net/netip"
 
 	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/geo"
 	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
 	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
 )
 
-// Handler structure that includes the Storage structure.
+// Handler structure that includes the Storage structure
+// and the use cases of the application.
 type Handlers struct {
-	Storage        *storage.Store
-	trustedSubnets netip.Prefix
+	Storage *storage.Store
+	Service *usecase.Service
+	// signer signs the access to the protected short URLs.
+	signer auth.Auth
 }
 
 // List of typical errors.
@@ -22,20 +28,47 @@ var (
 	ErrInvalidUID     = errors.New("short url is invalid")
 	ErrInvalidRequest = errors.New(`json is invalid, please check what you send. 
 	Should be: {"url":"https://example.ru"}`)
-	ErrUserDoesNotExist    = errors.New("user did not use the service")
-	ErrEmptyBatch          = errors.New("URL batching error, please check the source data")
-	ErrAccessTrustedSubnet = errors.New("your IP is not included in the trusted subnet")
+	ErrUserDoesNotExist = errors.New("user did not use the service")
 )
 
-// InputShorten structure for the ShortenURLAPI method containing a URL field.
+// InputShorten structure for the ShortenURLAPI method containing a URL field
+// and the optional tags, folder and settings of the redirect.
 type InputShorten struct {
-	URL string `json:"url"`
+	UTM          storage.UTM       `json:"utm"`
+	URL          string            `json:"url"`
+	Folder       string            `json:"folder,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	Rules        []storage.Rule    `json:"rules,omitempty"`
+	Variants     []storage.Variant `json:"variants,omitempty"`
+	Password     string            `json:"password,omitempty"`
+	Domain       string            `json:"domain,omitempty"`
+	MaxClicks    uint              `json:"max_clicks,omitempty"`
+	ForwardQuery bool              `json:"forward_query,omitempty"`
+	// QRCode adds the QR code of the short URL to the response.
+	QRCode bool `json:"qr_code,omitempty"`
+}
+
+// InputLabels structure for the SetLabels method containing
+// the tags and the folder of URL, the omitted fields are not changed.
+type InputLabels struct {
+	Tags   *[]string `json:"tags"`
+	Folder *string   `json:"folder"`
+}
+
+// InputWebhook structure for the AddWebhook method containing
+// the endpoint and the subscribed events of the webhook.
+type InputWebhook struct {
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`
+	ClickThreshold uint     `json:"click_threshold,omitempty"`
 }
 
 // OutputShorten structure for the ShortenURLAPI method containing data for response.
 type OutputShorten struct {
-	Result  string `json:"result,omitempty"`
-	Err     string `json:"error,omitempty"`
+	Result string `json:"result,omitempty"`
+	Err    string `json:"error,omitempty"`
+	// QRCode is the PNG image of the QR code as the data URI.
+	QRCode  string `json:"qr_code,omitempty"`
 	Success bool   `json:"success"`
 }
 
@@ -57,10 +90,20 @@ type OutShortenBatch struct {
 func New(conf config.Configurator, logger *logger.AppLogger, st *storage.Store) *Handlers {
 	handlers := Handlers{
 		Storage: st,
+		Service: usecase.New(st, conf, realip.ParseSubnets(conf.GetTrustedSubnet())),
+		signer:  auth.TurnOn(st.St, conf.GetSecretKey()),
 	}
 
-	if network, err := netip.ParsePrefix(conf.GetTrustedSubnet()); err == nil {
-		handlers.trustedSubnets = network
+	handlers.Service.SetIdempotencyTTL(conf.GetIdempotencyTTL())
+
+	// the country targeting rules don't match without the geo database.
+	if path := conf.GetGeoDBPath(); path != "" {
+		geoDB, err := geo.Open(path)
+		if err != nil {
+			logger.LZ.Error(err)
+		}
+
+		handlers.Service.SetGeo(geoDB)
 	}
 
 	return &handlers
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alaleks/shortener/internal/app/usecase"
+	"github.com/gorilla/mux"
+)
+
+// SetLabels replaces the tags and sets the folder of URL of current user.
+//
+// The omitted fields are not changed, the empty folder removes URL from its folder.
+// PATCH /api/user/urls/{uid}, JSON: {"tags":["go","news"],"folder":"spring"}.
+func (h *Handlers) SetLabels(writer http.ResponseWriter, req *http.Request) {
+	var input InputLabels
+
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, err)
+
+		return
+	}
+
+	err := h.Service.SetLabels(userID(req), mux.Vars(req)["uid"], usecase.LabelsUpdate{
+		Tags:   input.Tags,
+		Folder: input.Folder,
+	})
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetTagStat returns the number of URLs of current user with the tag
+// and the sum of their clicks.
+//
+// GET /api/user/tags/{tag}/statistics
+func (h *Handlers) GetTagStat(writer http.ResponseWriter, req *http.Request) {
+	stat, err := h.Service.TagStat(userID(req), mux.Vars(req)["tag"])
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writeJSON(writer, req, http.StatusOK, stat)
+}
+
+// GetFolderStat returns the number of URLs of current user in the folder
+// and the sum of their clicks.
+//
+// GET /api/user/folders/{folder}/statistics
+func (h *Handlers) GetFolderStat(writer http.ResponseWriter, req *http.Request) {
+	stat, err := h.Service.FolderStat(userID(req), mux.Vars(req)["folder"])
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writeJSON(writer, req, http.StatusOK, stat)
+}
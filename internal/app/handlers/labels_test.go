@@ -0,0 +1,83 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/serv/middleware"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestSetLabels(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	st := storage.InitStore(appConf, logger)
+	testHandler := handlers.New(appConf, logger, st)
+	authorization := auth.TurnOn(testHandler.Storage.St, appConf.GetSecretKey())
+	routers := middleware.New(authorization.Authorization).Configure(router.Create(testHandler))
+
+	// сокращение ссылки с тегом
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten",
+		strings.NewReader(`{"url":"http://github.com/alaleks/shortener","tags":["go"]}`))
+
+	testRec := httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	res := testRec.Result()
+	defer res.Body.Close()
+
+	var output handlers.OutputShorten
+
+	if err := json.NewDecoder(res.Body).Decode(&output); err != nil || res.StatusCode != http.StatusCreated {
+		t.Fatalf("status code should be %d but received %d", http.StatusCreated, res.StatusCode)
+	}
+
+	cookie := res.Header.Get("Set-Cookie")
+	shortUID := output.Result[strings.LastIndex(output.Result, "/")+1:]
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "теги и папка", body: `{"tags":["go","news"],"folder":"spring"}`, status: http.StatusNoContent},
+		{name: "пустой запрос", body: `{}`, status: http.StatusBadRequest},
+		{name: "пустой тег", body: `{"tags":[" "]}`, status: http.StatusBadRequest},
+	}
+
+	for _, item := range tests {
+		req := httptest.NewRequest(http.MethodPatch, "/api/user/urls/"+shortUID, strings.NewReader(item.body))
+		req.Header.Set("Cookie", cookie)
+
+		testRec := httptest.NewRecorder()
+		routers.ServeHTTP(testRec, req)
+
+		if testRec.Code != item.status {
+			t.Errorf("%s: status code should be %d but received %d", item.name, item.status, testRec.Code)
+		}
+	}
+
+	// статистика по тегу
+	req = httptest.NewRequest(http.MethodGet, "/api/user/tags/news/statistics", nil)
+	req.Header.Set("Cookie", cookie)
+
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	var stat usecase.LabelStatistics
+
+	if err := json.NewDecoder(testRec.Body).Decode(&stat); err != nil || stat.URLs != 1 || stat.Tag != "news" {
+		t.Errorf("tag statistics should contain 1 URL but received %+v", stat)
+	}
+}
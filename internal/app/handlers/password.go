@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/usecase"
+	"github.com/gorilla/mux"
+)
+
+// The cookie allowing the redirect by the protected short URL.
+const (
+	unlockCookiePrefix = "unlock_"
+	unlockLifeTime     = 10 * time.Minute
+)
+
+// passwordForm is the page with the password form of the protected short URL,
+// the form is posted to the same URL keeping the query.
+var passwordForm = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Password required</title></head>
+<body>
+<form method="post" action="{{.Action}}">
+<p>This link is protected by a password.</p>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+<input type="password" name="password" autofocus required>
+<button type="submit">Open</button>
+</form>
+</body>
+</html>
+`))
+
+// UnlockShortURL checks the password of the protected short URL.
+//
+// If the password is right, the cookie allowing the redirect is set
+// for a short time and the client is redirected to the short URL.
+// The cookie is signed for the short UID on the domain of the request.
+// POST /{uid}, form: password=secret.
+func (h *Handlers) UnlockShortURL(writer http.ResponseWriter, req *http.Request) {
+	uid := mux.Vars(req)["uid"]
+
+	err := h.Service.Unlock(req.Host, uid, req.PostFormValue("password"))
+
+	switch {
+	case err == nil:
+	case errors.Is(err, usecase.ErrWrongPassword):
+		writePasswordForm(writer, req, http.StatusForbidden, err.Error())
+
+		return
+	default:
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	http.SetCookie(writer, &http.Cookie{
+		Name:     unlockCookiePrefix + uid,
+		Value:    h.signer.CreateLinkSigning(h.Service.ShortUID(req.Host, uid), time.Now().Add(unlockLifeTime)),
+		Path:     "/" + uid,
+		MaxAge:   int(unlockLifeTime.Seconds()),
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	location := "/" + uid
+	if req.URL.RawQuery != "" {
+		location += "?" + req.URL.RawQuery
+	}
+
+	http.Redirect(writer, req, location, http.StatusSeeOther)
+}
+
+// writePasswordForm writes the password form with the status and the error message.
+func writePasswordForm(writer http.ResponseWriter, req *http.Request, status int, message string) {
+	action := req.URL.Path
+	if req.URL.RawQuery != "" {
+		action += "?" + req.URL.RawQuery
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.WriteHeader(status)
+
+	_ = passwordForm.Execute(writer, struct {
+		Action string
+		Error  string
+	}{Action: action, Error: message})
+}
@@ -0,0 +1,148 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUnlockShortURL(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	testHandler := handlers.New(appConf, logger, &storage.Store{St: storage.NewDefault(appConf)})
+	routers := router.Create(testHandler)
+	longURL := "https://github.com/alaleks/shortener"
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten",
+		strings.NewReader(`{"url":"`+longURL+`","password":"secret"}`))
+	testRec := httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	var output handlers.OutputShorten
+
+	if err := json.NewDecoder(testRec.Body).Decode(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/" + output.Result[strings.LastIndex(output.Result, "/")+1:]
+
+	// без пароля показывается форма
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	if testRec.Code != http.StatusOK || !strings.Contains(testRec.Body.String(), `name="password"`) {
+		t.Fatalf("password form should be returned but received %d", testRec.Code)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		status   int
+	}{
+		{name: "неверный пароль", password: "wrong", status: http.StatusForbidden},
+		{name: "верный пароль", password: "secret", status: http.StatusSeeOther},
+	}
+
+	var cookies []*http.Cookie
+
+	for _, item := range tests {
+		req := httptest.NewRequest(http.MethodPost, path+"?ref=x",
+			strings.NewReader(url.Values{"password": {item.password}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		testRec := httptest.NewRecorder()
+		routers.ServeHTTP(testRec, req)
+
+		if testRec.Code != item.status {
+			t.Errorf("%s: status code should be %d but received %d", item.name, item.status, testRec.Code)
+		}
+
+		cookies = testRec.Result().Cookies()
+	}
+
+	// с cookie выполняется редирект
+	req = httptest.NewRequest(http.MethodGet, path, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	if testRec.Code != http.StatusTemporaryRedirect || testRec.Header().Get("Location") != longURL {
+		t.Errorf("short url should be redirected to %s but received %d", longURL, testRec.Code)
+	}
+}
+
+func TestUnlockDomains(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	appConf.DefineOptionsFlags([]string{"shortener", "-domains", "a.example.com,b.example.com"})
+	logger := logger.NewLogger()
+	store := storage.NewDefault(appConf)
+	testHandler := handlers.New(appConf, logger, &storage.Store{St: store})
+	routers := router.Create(testHandler)
+	longURL := "https://github.com/alaleks/shortener"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// одинаковый UID защищенных ссылок на разных доменах
+	for _, domain := range []string{"a.example.com", "b.example.com"} {
+		_, err := store.AddURL(storage.NewURL{LongURL: longURL, UserID: "1", Alias: "promo", Domain: domain, PasswordHash: string(hash)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://a.example.com/promo",
+		strings.NewReader(url.Values{"password": {"secret"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	testRec := httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	if testRec.Code != http.StatusSeeOther {
+		t.Fatalf("status code should be %d but received %d", http.StatusSeeOther, testRec.Code)
+	}
+
+	cookies := testRec.Result().Cookies()
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+	}{
+		{name: "домен пароля", target: "http://a.example.com/promo", status: http.StatusTemporaryRedirect},
+		{name: "другой домен", target: "http://b.example.com/promo", status: http.StatusOK},
+	}
+
+	for _, item := range tests {
+		req := httptest.NewRequest(http.MethodGet, item.target, nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		testRec := httptest.NewRecorder()
+		routers.ServeHTTP(testRec, req)
+
+		if testRec.Code != item.status {
+			t.Errorf("%s: status code should be %d but received %d", item.name, item.status, testRec.Code)
+		}
+	}
+}
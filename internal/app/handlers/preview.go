@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// previewMaxAge is the time during which the preview page is cached, in seconds,
+// the metadata of the destination page is fetched after the link is created.
+const previewMaxAge = 5 * 60
+
+// previewPage is the preview page of the short URL with Open Graph tags.
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta property="og:type" content="website">
+<meta property="og:url" content="{{.ShortURL}}">
+<meta property="og:title" content="{{.Title}}">
+{{with .Description}}<meta property="og:description" content="{{.}}">
+<meta name="description" content="{{.}}">
+{{end}}{{if .Image}}<meta property="og:image" content="{{.Image}}">
+<meta name="twitter:card" content="summary_large_image">
+{{else}}<meta name="twitter:card" content="summary">
+{{end}}{{with .Favicon}}<link rel="icon" href="{{.}}">
+{{end}}</head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Description}}<p>{{.}}</p>
+{{end}}{{with .Image}}<img src="{{.}}" alt="" style="max-width:100%">
+{{end}}{{if .Destination}}<p>The link leads to {{.Destination}}</p>
+{{else}}<p>The link is protected by a password.</p>
+{{end}}<a href="{{.ShortURL}}">Open the link</a>
+</body>
+</html>
+`))
+
+// preview represents the data of the preview page.
+type preview struct {
+	ShortURL    string
+	Destination string
+	Title       string
+	Description string
+	Image       string
+	Favicon     string
+}
+
+// GetPreview returns the preview page of the short URL.
+//
+// The title, the description and the image of the destination page
+// are rendered in Open Graph tags, so sharing the short URL in chat apps
+// shows the card of the link. The destination of the protected
+// short URL is hidden. The short URLs on the custom domains are found
+// by the Host header.
+// GET /{uid}/preview
+func (h *Handlers) GetPreview(writer http.ResponseWriter, req *http.Request) {
+	stat, err := h.Service.Preview(req.Host + "/" + mux.Vars(req)["uid"])
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	page := preview{ShortURL: stat.ShortURL, Destination: stat.LongURL, Title: stat.LongURL}
+
+	if stat.Metadata != nil {
+		page.Description = stat.Metadata.Description
+		page.Image = stat.Metadata.Image
+		page.Favicon = stat.Metadata.Favicon
+
+		if stat.Metadata.Title != "" {
+			page.Title = stat.Metadata.Title
+		}
+	}
+
+	if page.Title == "" {
+		page.Title = stat.ShortURL
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writer.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(previewMaxAge))
+	writer.WriteHeader(http.StatusOK)
+
+	_ = previewPage.Execute(writer, page)
+}
@@ -0,0 +1,115 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+func TestGetPreview(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	store := storage.NewDefault(appConf)
+	testHandler := handlers.New(appConf, logger, &storage.Store{St: store})
+	routers := router.Create(testHandler)
+
+	shorten := func(body string) string {
+		testRec := httptest.NewRecorder()
+		routers.ServeHTTP(testRec, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body)))
+
+		var output handlers.OutputShorten
+
+		if err := json.NewDecoder(testRec.Body).Decode(&output); err != nil {
+			t.Fatal(err)
+		}
+
+		uid := output.Result[strings.LastIndex(output.Result, "/")+1:]
+
+		err := store.SetMetadata(uid, storage.Metadata{
+			FetchedAt:   time.Now(),
+			Title:       `Shortener <v1>`,
+			Description: "URL shortener in Go",
+			Image:       "https://example.com/card.png",
+			Favicon:     "https://example.com/favicon.ico",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return uid
+	}
+
+	public := shorten(`{"url":"https://github.com/alaleks/shortener"}`)
+	protected := shorten(`{"url":"https://github.com/alaleks/secret","password":"secret"}`)
+
+	tests := []struct {
+		name     string
+		uid      string
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "public",
+			uid:  public,
+			contains: []string{
+				`<meta property="og:title" content="Shortener &lt;v1&gt;">`,
+				`<meta property="og:description" content="URL shortener in Go">`,
+				`<meta property="og:image" content="https://example.com/card.png">`,
+				`<link rel="icon" href="https://example.com/favicon.ico">`,
+				"https://github.com/alaleks/shortener",
+			},
+		},
+		{
+			name:     "protected",
+			uid:      protected,
+			contains: []string{"protected by a password"},
+			excludes: []string{"og:image", "Shortener", "github.com/alaleks/secret"},
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRec := httptest.NewRecorder()
+			routers.ServeHTTP(testRec, httptest.NewRequest(http.MethodGet, "/"+item.uid+"/preview", nil))
+
+			if testRec.Code != http.StatusOK {
+				t.Fatalf("status code should be %d but received %d", http.StatusOK, testRec.Code)
+			}
+
+			body := testRec.Body.String()
+
+			for _, value := range item.contains {
+				if !strings.Contains(body, value) {
+					t.Errorf("preview should contain %q but received %s", value, body)
+				}
+			}
+
+			for _, value := range item.excludes {
+				if strings.Contains(body, value) {
+					t.Errorf("preview should not contain %q but received %s", value, body)
+				}
+			}
+		})
+	}
+
+	testRec := httptest.NewRecorder()
+	routers.ServeHTTP(testRec, httptest.NewRequest(http.MethodGet, "/unknown/preview", nil))
+
+	if testRec.Code != http.StatusNotFound {
+		t.Errorf("status code should be %d but received %d", http.StatusNotFound, testRec.Code)
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+const (
+	contentTypeProblem = "application/problem+json"
+	problemTypePrefix  = "urn:shortener:problem:"
+)
+
+// Stable error codes of the REST API.
+const (
+	CodeInvalidJSON          = "invalid_json"
+	CodeInvalidURL           = "invalid_url"
+	CodeInvalidInput         = "invalid_input"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeShortURLNotFound     = "short_url_not_found"
+	CodeUserURLsNotFound     = "user_urls_not_found"
+	CodeNotFound             = "not_found"
+	CodeShortURLRemoved      = "short_url_removed"
+	CodeShortURLExpired      = "short_url_expired"
+	CodeShortURLExhausted    = "short_url_exhausted"
+	CodeAliasExists          = "alias_exists"
+	CodeAlreadyExists        = "already_exists"
+	CodeBatchRejected        = "batch_rejected"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeWebhookNotFound      = "webhook_not_found"
+	CodeTooManyWebhooks      = "too_many_webhooks"
+	CodeStorageUnavailable   = "storage_unavailable"
+	CodeInternal             = "internal_error"
+)
+
+// Problem represents the error response according to RFC 7807.
+//
+// Code is the extension member with the stable machine-readable error code,
+// the type of the problem is the URN built from the code.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          string         `json:"code"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+	Status        int            `json:"status"`
+}
+
+// InvalidParam represents the invalid part of the request,
+// e.g. the item of the batch identified by its correlation ID.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// writeProblem writes the problem response for the error.
+func writeProblem(writer http.ResponseWriter, req *http.Request, err error) {
+	status, code := classifyError(err)
+	writeProblemCode(writer, req, status, code, err)
+}
+
+// writeBatchRejected writes the problem response for the rejected atomic batch
+// with the invalid items.
+func writeBatchRejected(writer http.ResponseWriter, req *http.Request, results []usecase.BatchResult, err error) {
+	problem := newProblem(req, http.StatusUnprocessableEntity, CodeBatchRejected, err)
+
+	for _, item := range results {
+		if item.Err != nil {
+			problem.InvalidParams = append(problem.InvalidParams, InvalidParam{Name: item.CorID, Reason: item.Err.Error()})
+		}
+	}
+
+	encodeProblem(writer, problem)
+}
+
+// writeProblemCode writes the problem response with the status and the code.
+func writeProblemCode(writer http.ResponseWriter, req *http.Request, status int, code string, err error) {
+	encodeProblem(writer, newProblem(req, status, code, err))
+}
+
+// newProblem returns the problem with the status and the code.
+func newProblem(req *http.Request, status int, code string, err error) Problem {
+	problem := Problem{
+		Type:     problemTypePrefix + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Code:     code,
+		Instance: req.URL.Path,
+	}
+
+	// details of internal errors are not disclosed.
+	if status != http.StatusInternalServerError && err != nil {
+		problem.Detail = err.Error()
+	}
+
+	return problem
+}
+
+// encodeProblem writes the problem response.
+func encodeProblem(writer http.ResponseWriter, problem Problem) {
+	writer.Header().Set("Content-Type", contentTypeProblem)
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(problem.Status)
+
+	_ = json.NewEncoder(writer).Encode(problem)
+}
+
+// classifyError returns the HTTP status code and the error code for the error.
+func classifyError(err error) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidURL):
+		return http.StatusBadRequest, CodeInvalidURL
+	case errors.Is(err, usecase.ErrInvalidInput):
+		return http.StatusBadRequest, CodeInvalidInput
+	case errors.Is(err, usecase.ErrUnauthorized):
+		return http.StatusUnauthorized, CodeUnauthorized
+	case errors.Is(err, usecase.ErrForbidden):
+		return http.StatusForbidden, CodeForbidden
+	case errors.Is(err, storage.ErrUIDNotValid):
+		return http.StatusNotFound, CodeShortURLNotFound
+	case errors.Is(err, storage.ErrUserUrlsEmpty):
+		return http.StatusNotFound, CodeUserURLsNotFound
+	case errors.Is(err, storage.ErrWebhookNotFound):
+		return http.StatusNotFound, CodeWebhookNotFound
+	case errors.Is(err, usecase.ErrNotFound):
+		return http.StatusNotFound, CodeNotFound
+	case errors.Is(err, storage.ErrShortURLExpired):
+		return http.StatusGone, CodeShortURLExpired
+	case errors.Is(err, storage.ErrShortURLExhausted):
+		return http.StatusGone, CodeShortURLExhausted
+	case errors.Is(err, usecase.ErrGone):
+		return http.StatusGone, CodeShortURLRemoved
+	case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+		return http.StatusUnprocessableEntity, CodeIdempotencyKeyReused
+	case errors.Is(err, storage.ErrAliasExists):
+		return http.StatusConflict, CodeAliasExists
+	case errors.Is(err, usecase.ErrTooManyWebhooks):
+		return http.StatusConflict, CodeTooManyWebhooks
+	case errors.Is(err, usecase.ErrConflict):
+		return http.StatusConflict, CodeAlreadyExists
+	default:
+		return http.StatusInternalServerError, CodeInternal
+	}
+}
@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+func TestProblemResponses(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	st := storage.InitStore(appConf, logger)
+	testHandler := handlers.New(appConf, logger, st)
+	routers := router.Create(testHandler)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   string
+		status int
+	}{
+		{
+			name: "невалидный json", method: http.MethodPost, path: "/api/shorten",
+			body: `{"url":`, status: http.StatusBadRequest, code: handlers.CodeInvalidJSON,
+		},
+		{
+			name: "невалидный url", method: http.MethodPost, path: "/api/shorten",
+			body: `{"url":"github.com"}`, status: http.StatusBadRequest, code: handlers.CodeInvalidURL,
+		},
+		{
+			name: "невалидный url в текстовом запросе", method: http.MethodPost, path: "/",
+			body: "github.com", status: http.StatusBadRequest, code: handlers.CodeInvalidURL,
+		},
+		{
+			name: "статистика несуществующей ссылки", method: http.MethodGet, path: "/api/badId/statistics",
+			status: http.StatusNotFound, code: handlers.CodeShortURLNotFound,
+		},
+		{
+			name: "статистика на неизвестном домене", method: http.MethodGet, path: "/api/badId/statistics?domain=go.example.com",
+			status: http.StatusBadRequest, code: handlers.CodeInvalidInput,
+		},
+		{
+			name: "пустой батч", method: http.MethodPost, path: "/api/shorten/batch",
+			body: `[]`, status: http.StatusBadRequest, code: handlers.CodeInvalidInput,
+		},
+		{
+			name: "атомарный батч с невалидной ссылкой", method: http.MethodPost, path: "/api/shorten/batch?atomic=true",
+			body:   `[{"correlation_id":"1","original_url":"https://go.dev"},{"correlation_id":"2","original_url":"go"}]`,
+			status: http.StatusUnprocessableEntity, code: handlers.CodeBatchRejected,
+		},
+		{
+			name: "доступ к внутренней статистике", method: http.MethodGet, path: "/api/internal/stats",
+			status: http.StatusForbidden, code: handlers.CodeForbidden,
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(item.method, item.path, strings.NewReader(item.body))
+			routers.ServeHTTP(w, req)
+
+			if w.Code != item.status {
+				t.Errorf("status code should be %d but received %d", item.status, w.Code)
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("content type should be application/problem+json but received %s", ct)
+			}
+
+			var problem handlers.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("failed decode problem: %s", err)
+			}
+
+			if problem.Code != item.code || problem.Status != item.status {
+				t.Errorf("problem should have code %s and status %d but received %s and %d",
+					item.code, item.status, problem.Code, problem.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alaleks/shortener/internal/app/usecase"
+	"github.com/gorilla/mux"
+)
+
+// qrMaxAge is the time during which the QR code is cached by clients, in seconds.
+const qrMaxAge = 24 * 60 * 60
+
+// GetQRCode returns the QR code of the short URL.
+//
+// The query parameters are format (png or svg), size in pixels,
+// level of error correction (L, M, Q or H) and margin in modules.
+// The short URLs on the custom domains are found by the Host header.
+// GET /{uid}/qr?size=512&format=svg&level=H&margin=2
+func (h *Handlers) GetQRCode(writer http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	opts, err := usecase.QRQuery{
+		Format: query.Get("format"),
+		Level:  query.Get("level"),
+		Size:   query.Get("size"),
+		Margin: query.Get("margin"),
+	}.Options()
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	img, err := h.Service.QRCode(req.Host+"/"+mux.Vars(req)["uid"], opts)
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", img.ContentType)
+	writer.Header().Set("Content-Length", strconv.Itoa(len(img.Data)))
+	writer.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(qrMaxAge))
+	writer.WriteHeader(http.StatusOK)
+
+	_, _ = writer.Write(img.Data)
+}
@@ -0,0 +1,75 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+func TestGetQRCode(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	testHandler := handlers.New(appConf, logger, &storage.Store{St: storage.NewDefault(appConf)})
+	routers := router.Create(testHandler)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten",
+		strings.NewReader(`{"url":"https://github.com/alaleks/shortener","qr_code":true}`))
+	testRec := httptest.NewRecorder()
+	routers.ServeHTTP(testRec, req)
+
+	var output handlers.OutputShorten
+
+	if err := json.NewDecoder(testRec.Body).Decode(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(output.QRCode, "data:image/png;base64,") {
+		t.Errorf("QR code should be returned as data URI but received %q", output.QRCode)
+	}
+
+	path := "/" + output.Result[strings.LastIndex(output.Result, "/")+1:] + "/qr"
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		status      int
+	}{
+		{name: "png", query: "", contentType: "image/png", status: http.StatusOK},
+		{name: "svg", query: "?format=svg&size=512&level=H&margin=0", contentType: "image/svg+xml", status: http.StatusOK},
+		{name: "invalid size", query: "?size=big", contentType: "application/problem+json", status: http.StatusBadRequest},
+		{name: "invalid level", query: "?level=Z", contentType: "application/problem+json", status: http.StatusBadRequest},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRec := httptest.NewRecorder()
+			routers.ServeHTTP(testRec, httptest.NewRequest(http.MethodGet, path+item.query, nil))
+
+			if testRec.Code != item.status || testRec.Header().Get("Content-Type") != item.contentType {
+				t.Errorf("response should be %d %s but received %d %s", item.status, item.contentType,
+					testRec.Code, testRec.Header().Get("Content-Type"))
+			}
+		})
+	}
+
+	testRec = httptest.NewRecorder()
+	routers.ServeHTTP(testRec, httptest.NewRequest(http.MethodGet, "/unknown/qr", nil))
+
+	if testRec.Code != http.StatusNotFound {
+		t.Errorf("status code should be %d but received %d", http.StatusNotFound, testRec.Code)
+	}
+}
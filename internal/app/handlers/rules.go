@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/gorilla/mux"
+)
+
+// SetRules replaces the targeting rules of URL of current user.
+//
+// The rules are evaluated in order on redirect, the first rule
+// whose conditions all match the client wins. The empty list removes the rules.
+// PUT /api/user/urls/{uid}/rules, JSON: [{"device":"android",
+// "url":"https://play.google.com/store/apps/details?id=app"},
+// {"language":"de","url":"https://example.com/de"},
+// {"country":"FR","url":"https://fr.example.com"},
+// {"cidrs":["192.0.2.0/24"],"url":"https://mirror.example.com"}].
+func (h *Handlers) SetRules(writer http.ResponseWriter, req *http.Request) {
+	var rules []storage.Rule
+
+	if err := json.NewDecoder(req.Body).Decode(&rules); err != nil {
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, err)
+
+		return
+	}
+
+	if err := h.Service.SetRules(userID(req), mux.Vars(req)["uid"], rules); err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/gorilla/mux"
+)
+
+// SetVariants replaces the weighted destinations of URL of current user
+// for A/B split redirects.
+//
+// The variant is picked on redirect according to the weights and kept
+// for the client. The counters of redirects to the variants are reset,
+// the empty list removes the variants. The counters are returned
+// by GET /api/{uid}/statistics.
+// PUT /api/user/urls/{uid}/variants, JSON: [{"url":"https://example.com/a","weight":70},
+// {"url":"https://example.com/b","weight":30}].
+func (h *Handlers) SetVariants(writer http.ResponseWriter, req *http.Request) {
+	var variants []storage.Variant
+
+	if err := json.NewDecoder(req.Body).Decode(&variants); err != nil {
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, err)
+
+		return
+	}
+
+	if err := h.Service.SetVariants(userID(req), mux.Vars(req)["uid"], variants); err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
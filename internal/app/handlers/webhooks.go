@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alaleks/shortener/internal/app/usecase"
+	"github.com/gorilla/mux"
+)
+
+// AddWebhook registers the webhook of current user, the response
+// contains the secret signing the deliveries, it isn't returned again.
+//
+// Events: link.created, link.deleted, link.expired and link.clicks,
+// which is sent when the short URL reaches the click threshold (1 by default).
+// POST /api/user/webhooks, JSON: {"url":"https://crm.example.com/hook","events":["link.clicks"],"click_threshold":1}.
+func (h *Handlers) AddWebhook(writer http.ResponseWriter, req *http.Request) {
+	var input InputWebhook
+
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		writeProblemCode(writer, req, http.StatusBadRequest, CodeInvalidJSON, err)
+
+		return
+	}
+
+	hook, err := h.Service.AddWebhook(userID(req), usecase.WebhookInput{
+		URL:            input.URL,
+		Events:         input.Events,
+		ClickThreshold: input.ClickThreshold,
+	})
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writeJSON(writer, req, http.StatusCreated, hook)
+}
+
+// GetWebhooks returns the webhooks of current user without the secrets.
+//
+// GET /api/user/webhooks
+func (h *Handlers) GetWebhooks(writer http.ResponseWriter, req *http.Request) {
+	hooks, err := h.Service.Webhooks(userID(req))
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writeJSON(writer, req, http.StatusOK, hooks)
+}
+
+// DeleteWebhook deletes the webhook of current user with its deliveries.
+//
+// DELETE /api/user/webhooks/{id}
+func (h *Handlers) DeleteWebhook(writer http.ResponseWriter, req *http.Request) {
+	if err := h.Service.DeleteWebhook(userID(req), mux.Vars(req)["id"]); err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries returns the log of the last deliveries
+// of the webhook of current user, the newest first.
+//
+// GET /api/user/webhooks/{id}/deliveries
+func (h *Handlers) GetWebhookDeliveries(writer http.ResponseWriter, req *http.Request) {
+	deliveries, err := h.Service.WebhookDeliveries(userID(req), mux.Vars(req)["id"])
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writeJSON(writer, req, http.StatusOK, deliveries)
+}
+
+// TestWebhook sends the ping event to the webhook of current user
+// and returns the delivery with the result of the attempt.
+//
+// POST /api/user/webhooks/{id}/test
+func (h *Handlers) TestWebhook(writer http.ResponseWriter, req *http.Request) {
+	delivery, err := h.Service.TestWebhook(req.Context(), userID(req), mux.Vars(req)["id"])
+	if err != nil {
+		writeProblem(writer, req, err)
+
+		return
+	}
+
+	writeJSON(writer, req, http.StatusOK, delivery)
+}
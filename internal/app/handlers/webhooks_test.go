@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/serv/middleware"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/webhook"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	var secret string
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+
+		if !webhook.Verify(secret, timestamp, body, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(receiver.Close)
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	st := storage.InitStore(appConf, logger)
+	testHandler := handlers.New(appConf, logger, st)
+	testHandler.Service.SetDispatcher(webhook.New(st.St, webhook.Options{Client: receiver.Client()}))
+	authorization := auth.TurnOn(testHandler.Storage.St, appConf.GetSecretKey())
+	routers := middleware.New(authorization.Authorization).Configure(router.Create(testHandler))
+
+	serve := func(method, target, body, cookie string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Cookie", cookie)
+
+		testRec := httptest.NewRecorder()
+		routers.ServeHTTP(testRec, req)
+
+		return testRec
+	}
+
+	// регистрация вебхука
+	testRec := serve(http.MethodPost, "/api/user/webhooks",
+		`{"url":"`+receiver.URL+`/hook","events":["link.clicks","link.created"]}`, "")
+	if testRec.Code != http.StatusCreated {
+		t.Fatalf("status code should be %d but received %d: %s", http.StatusCreated, testRec.Code, testRec.Body)
+	}
+
+	cookie := testRec.Header().Get("Set-Cookie")
+
+	var hook storage.Webhook
+
+	if err := json.NewDecoder(testRec.Body).Decode(&hook); err != nil || hook.Secret == "" {
+		t.Fatalf("webhook should contain secret but received %+v (%v)", hook, err)
+	}
+
+	secret = hook.Secret
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{name: "неизвестное событие", method: http.MethodPost, target: "/api/user/webhooks",
+			body: `{"url":"https://crm.example.com","events":["link.updated"]}`, status: http.StatusBadRequest},
+		{name: "неверный JSON", method: http.MethodPost, target: "/api/user/webhooks", body: `{`, status: http.StatusBadRequest},
+		{name: "тест вебхука", method: http.MethodPost, target: "/api/user/webhooks/" + hook.ID + "/test", status: http.StatusOK},
+		{name: "тест чужого вебхука", method: http.MethodPost, target: "/api/user/webhooks/unknown/test", status: http.StatusNotFound},
+	}
+
+	for _, item := range tests {
+		if testRec := serve(item.method, item.target, item.body, cookie); testRec.Code != item.status {
+			t.Errorf("%s: status code should be %d but received %d", item.name, item.status, testRec.Code)
+		}
+	}
+
+	// список вебхуков без секретов
+	var hooks []storage.Webhook
+
+	testRec = serve(http.MethodGet, "/api/user/webhooks", "", cookie)
+	if err := json.NewDecoder(testRec.Body).Decode(&hooks); err != nil || len(hooks) != 1 || hooks[0].Secret != "" {
+		t.Errorf("list should contain one webhook without secret but received %+v (%v)", hooks, err)
+	}
+
+	// журнал доставок содержит проверку вебхука
+	var deliveries []storage.Delivery
+
+	testRec = serve(http.MethodGet, "/api/user/webhooks/"+hook.ID+"/deliveries", "", cookie)
+	if err := json.NewDecoder(testRec.Body).Decode(&deliveries); err != nil || len(deliveries) != 1 ||
+		deliveries[0].Event != webhook.EventPing || deliveries[0].Status != storage.DeliveryDelivered {
+		t.Errorf("log should contain delivered ping but received %+v (%v)", deliveries, err)
+	}
+
+	if testRec = serve(http.MethodDelete, "/api/user/webhooks/"+hook.ID, "", cookie); testRec.Code != http.StatusNoContent {
+		t.Errorf("status code should be %d but received %d", http.StatusNoContent, testRec.Code)
+	}
+
+	if testRec = serve(http.MethodDelete, "/api/user/webhooks/"+hook.ID, "", cookie); testRec.Code != http.StatusNotFound {
+		t.Errorf("status code should be %d but received %d", http.StatusNotFound, testRec.Code)
+	}
+}
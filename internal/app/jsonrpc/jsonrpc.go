@@ -0,0 +1,199 @@
+// Package jsonrpc implements the JSON-RPC 2.0 API of the application.
+//
+// The API is served at POST /rpc and is authenticated with the same
+// authorization cookie as the REST API. Methods are called in the dotted
+// notation, e.g. "Shortener.Shorten". Batch requests (an array of requests)
+// are supported according to the JSON-RPC 2.0 specification, the size
+// of the request body and the number of requests in a batch are limited.
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alaleks/shortener/internal/app/usecase"
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+// Server error codes (reserved range from -32000 to -32099).
+const (
+	ErrCodeNotFound     json2.ErrorCode = -32001
+	ErrCodeGone         json2.ErrorCode = -32002
+	ErrCodeUnauthorized json2.ErrorCode = -32003
+	ErrCodeInvalidData  json2.ErrorCode = -32004
+	ErrCodeConflict     json2.ErrorCode = -32005
+)
+
+const (
+	serviceName     = "Shortener"
+	contentTypeJSON = "application/json"
+	batchPrefix     = '['
+	// prefixNoMethod is the prefix of the rpc server errors
+	// when the service or method is not found.
+	prefixNoMethod = "rpc: can't find"
+	// maxBodySize is the maximum size of the request body in bytes.
+	maxBodySize = 1 << 20
+	// maxBatchLen is the maximum number of requests in a batch.
+	maxBatchLen = 100
+)
+
+// List of typical errors.
+var (
+	ErrEmptyBatch    = errors.New("batch request is empty")
+	ErrBodyTooLarge  = errors.New("request body is too large")
+	ErrBatchTooLarge = errors.New("batch request contains too many requests")
+	ErrUserUndefined = errors.New("user is not defined, authorization cookie is required")
+)
+
+// Handler represents the JSON-RPC server with batching support.
+type Handler struct {
+	server *rpc.Server
+}
+
+// New returns a pointer of Handler with the registered Shortener service.
+func New(service *usecase.Service) *Handler {
+	server := rpc.NewServer()
+	server.RegisterCodec(json2.NewCustomCodecWithErrorMapper(rpc.DefaultEncoderSelector, mapError), contentTypeJSON)
+
+	// the error is returned only if the service has no suitable methods.
+	_ = server.RegisterService(&Service{service: service}, serviceName)
+
+	return &Handler{server: server}
+}
+
+// ServeHTTP implements http.Handler.
+//
+// A single request is passed to the rpc server as is. Each request of a batch
+// is processed separately, and the responses are combined into an array,
+// responses to notifications are omitted. The request with the body larger
+// than maxBodySize or the batch of more than maxBatchLen requests is rejected
+// with the invalid request error.
+func (h *Handler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(writer, req.Body, maxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(writer, json2.E_INVALID_REQ, ErrBodyTooLarge)
+
+			return
+		}
+
+		writeError(writer, json2.E_PARSE, err)
+
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if len(body) == 0 || body[0] != batchPrefix {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		h.server.ServeHTTP(writer, req)
+
+		return
+	}
+
+	var batch []json.RawMessage
+
+	if err := json.Unmarshal(body, &batch); err != nil {
+		writeError(writer, json2.E_PARSE, err)
+
+		return
+	}
+
+	if len(batch) == 0 {
+		writeError(writer, json2.E_INVALID_REQ, ErrEmptyBatch)
+
+		return
+	}
+
+	if len(batch) > maxBatchLen {
+		writeError(writer, json2.E_INVALID_REQ, ErrBatchTooLarge)
+
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, len(batch))
+
+	for _, item := range batch {
+		rec := &recorder{header: make(http.Header)}
+		itemReq := req.Clone(req.Context())
+		itemReq.Body = io.NopCloser(bytes.NewReader(item))
+		itemReq.ContentLength = int64(len(item))
+		h.server.ServeHTTP(rec, itemReq)
+
+		if res := bytes.TrimSpace(rec.body.Bytes()); len(res) > 0 {
+			responses = append(responses, res)
+		}
+	}
+
+	// all requests of the batch are notifications.
+	if len(responses) == 0 {
+		writer.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", contentTypeJSON)
+
+	_ = json.NewEncoder(writer).Encode(responses)
+}
+
+// mapError maps the application errors to the JSON-RPC errors.
+func mapError(err error) error {
+	code := json2.E_SERVER
+
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		code = ErrCodeNotFound
+	case errors.Is(err, usecase.ErrGone):
+		code = ErrCodeGone
+	case errors.Is(err, ErrUserUndefined), errors.Is(err, usecase.ErrUnauthorized):
+		code = ErrCodeUnauthorized
+	case errors.Is(err, ErrInvalidData), errors.Is(err, usecase.ErrInvalidInput):
+		code = ErrCodeInvalidData
+	case errors.Is(err, usecase.ErrConflict):
+		code = ErrCodeConflict
+	case strings.HasPrefix(err.Error(), prefixNoMethod):
+		code = json2.E_NO_METHOD
+	}
+
+	return &json2.Error{Code: code, Message: err.Error()}
+}
+
+// writeError writes the JSON-RPC error response with null id.
+func writeError(writer http.ResponseWriter, code json2.ErrorCode, err error) {
+	writer.Header().Set("Content-Type", contentTypeJSON)
+
+	_ = json.NewEncoder(writer).Encode(struct {
+		Error   *json2.Error `json:"error"`
+		ID      *string      `json:"id"`
+		Version string       `json:"jsonrpc"`
+	}{
+		Version: json2.Version,
+		Error:   &json2.Error{Code: code, Message: err.Error()},
+	})
+}
+
+// recorder collects the response of a single request of the batch.
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+// Header implements http.ResponseWriter.
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+// Write implements http.ResponseWriter.
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (r *recorder) WriteHeader(int) {}
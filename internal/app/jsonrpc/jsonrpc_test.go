@@ -0,0 +1,130 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/router"
+	"github.com/alaleks/shortener/internal/app/serv/middleware"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+	ID int `json:"id"`
+}
+
+func TestRPC(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	st := storage.InitStore(appConf, logger)
+	testHandler := handlers.New(appConf, logger, st)
+	auth := auth.TurnOn(st.St, appConf.GetSecretKey())
+	routers := middleware.New(auth.Authorization).Configure(router.Create(testHandler))
+
+	tests := []struct {
+		name      string
+		body      string
+		responses int
+		errCodes  []int
+	}{
+		{
+			name:      "сокращение url",
+			body:      `{"jsonrpc":"2.0","method":"Shortener.Shorten","params":{"url":"https://github.com/alaleks"},"id":1}`,
+			responses: 1, errCodes: []int{0},
+		},
+		{
+			name:      "сокращение невалидного url",
+			body:      `{"jsonrpc":"2.0","method":"Shortener.Shorten","params":{"url":"github.com"},"id":1}`,
+			responses: 1, errCodes: []int{-32004},
+		},
+		{
+			name:      "статистика несуществующей ссылки",
+			body:      `{"jsonrpc":"2.0","method":"Shortener.Stat","params":{"shortuid":"wrong"},"id":1}`,
+			responses: 1, errCodes: []int{-32001},
+		},
+		{
+			name: "пакетный запрос с уведомлением",
+			body: `[
+				{"jsonrpc":"2.0","method":"Shortener.Batch","params":{"urls":[{"correlation_id":"1","original_url":"https://ya.ru"}]},"id":1},
+				{"jsonrpc":"2.0","method":"Shortener.Shorten","params":{"url":"https://github.com"}},
+				{"jsonrpc":"2.0","method":"Shortener.Unknown","id":3}
+			]`,
+			responses: 2, errCodes: []int{0, -32601},
+		},
+		{
+			name:      "пустой пакетный запрос",
+			body:      `[]`,
+			responses: 1, errCodes: []int{-32600},
+		},
+		{
+			name: "слишком большой пакетный запрос",
+			body: "[" + strings.Repeat(`{"jsonrpc":"2.0","method":"Shortener.Ping"},`, 100) +
+				`{"jsonrpc":"2.0","method":"Shortener.Ping"}]`,
+			responses: 1, errCodes: []int{-32600},
+		},
+		{
+			name: "слишком большое тело запроса",
+			body: `{"jsonrpc":"2.0","method":"Shortener.Shorten","params":{"url":"https://github.com/` +
+				strings.Repeat("a", 1<<20) + `"},"id":1}`,
+			responses: 1, errCodes: []int{-32600},
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewBufferString(item.body))
+			req.Header.Set("Content-Type", "application/json")
+			routers.ServeHTTP(w, req)
+			res := w.Result()
+			defer res.Body.Close()
+
+			var responses []response
+
+			if item.body[0] == '[' && item.responses > 1 {
+				if err := json.NewDecoder(res.Body).Decode(&responses); err != nil {
+					t.Fatalf("failed decode batch response: %s", err)
+				}
+			} else {
+				var single response
+				if err := json.NewDecoder(res.Body).Decode(&single); err != nil {
+					t.Fatalf("failed decode response: %s", err)
+				}
+				responses = append(responses, single)
+			}
+
+			if len(responses) != item.responses {
+				t.Fatalf("number of responses should be %d but received %d", item.responses, len(responses))
+			}
+
+			for i, code := range item.errCodes {
+				var errCode int
+				if responses[i].Error != nil {
+					errCode = responses[i].Error.Code
+				}
+
+				if errCode != code {
+					t.Errorf("error code should be %d but received %d", code, errCode)
+				}
+			}
+		})
+	}
+}
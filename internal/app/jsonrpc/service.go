@@ -0,0 +1,211 @@
+package jsonrpc
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+// ErrInvalidData is an indicator that the method params are invalid.
+var ErrInvalidData = errors.New("params are empty or invalid")
+
+// Service implements the methods of the JSON-RPC API.
+type Service struct {
+	service *usecase.Service
+}
+
+// Data types of the method params and results.
+type (
+	// Empty is used for methods without params.
+	Empty struct{}
+
+	// ShortenArgs represents params of the Shorten method.
+	ShortenArgs struct {
+		UTM          storage.UTM       `json:"utm"`
+		URL          string            `json:"url"`
+		Folder       string            `json:"folder,omitempty"`
+		Tags         []string          `json:"tags,omitempty"`
+		Rules        []storage.Rule    `json:"rules,omitempty"`
+		Variants     []storage.Variant `json:"variants,omitempty"`
+		Password     string            `json:"password,omitempty"`
+		Domain       string            `json:"domain,omitempty"`
+		MaxClicks    uint              `json:"max_clicks,omitempty"`
+		ForwardQuery bool              `json:"forward_query,omitempty"`
+	}
+
+	// ShortenReply represents the result of the Shorten method.
+	ShortenReply struct {
+		Result string `json:"result"`
+		Exists bool   `json:"exists,omitempty"`
+	}
+
+	// BatchItem represents the item of params of the Batch method.
+	BatchItem struct {
+		CorID       string `json:"correlation_id"`
+		OriginalURL string `json:"original_url"`
+	}
+
+	// BatchArgs represents params of the Batch method.
+	BatchArgs struct {
+		IdempotencyKey string      `json:"idempotency_key,omitempty"`
+		URLs           []BatchItem `json:"urls"`
+		Atomic         bool        `json:"atomic,omitempty"`
+	}
+
+	// BatchResult represents the item of the result of the Batch method.
+	BatchResult struct {
+		CorID    string `json:"correlation_id"`
+		ShortURL string `json:"short_url,omitempty"`
+		Err      string `json:"error,omitempty"`
+	}
+
+	// BatchReply represents the result of the Batch method.
+	BatchReply struct {
+		URLs []BatchResult `json:"urls"`
+	}
+
+	// StatArgs represents params of the Stat method.
+	StatArgs struct {
+		ShortUID string `json:"shortuid"`
+	}
+
+	// ListReply represents the result of the List method.
+	ListReply struct {
+		URLs []storage.UserURL `json:"urls"`
+	}
+
+	// DeleteArgs represents params of the Delete method.
+	DeleteArgs struct {
+		URLs []string `json:"urls"`
+	}
+
+	// DeleteReply represents the result of the Delete method.
+	DeleteReply struct {
+		Accepted bool `json:"accepted"`
+	}
+)
+
+// Shorten implements URL shortening.
+//
+// {"jsonrpc":"2.0","method":"Shortener.Shorten","params":{"url":"https://github.com"},"id":1}.
+func (s *Service) Shorten(req *http.Request, args *ShortenArgs, reply *ShortenReply) error {
+	shortURL, err := s.service.Shorten(userID(req), args.URL, usecase.ShortenOptions{
+		Tags:         args.Tags,
+		Folder:       args.Folder,
+		UTM:          args.UTM,
+		ForwardQuery: args.ForwardQuery,
+		Rules:        args.Rules,
+		Variants:     args.Variants,
+		Password:     args.Password,
+		MaxClicks:    args.MaxClicks,
+		Domain:       args.Domain,
+	})
+	if err != nil && !errors.Is(err, usecase.ErrConflict) {
+		return err
+	}
+
+	reply.Result = shortURL
+	reply.Exists = errors.Is(err, usecase.ErrConflict)
+
+	return nil
+}
+
+// Batch implements url batch shortening.
+//
+// {"jsonrpc":"2.0","method":"Shortener.Batch",
+// "params":{"urls":[{"correlation_id":"1","original_url":"https://github.com"}]},"id":1}.
+func (s *Service) Batch(req *http.Request, args *BatchArgs, reply *BatchReply) error {
+	items := make([]usecase.BatchItem, 0, len(args.URLs))
+
+	for _, item := range args.URLs {
+		items = append(items, usecase.BatchItem{CorID: item.CorID, OriginalURL: item.OriginalURL})
+	}
+
+	results, err := s.service.ShortenBatch(userID(req), items, usecase.BatchOptions{
+		IdempotencyKey: args.IdempotencyKey,
+		Atomic:         args.Atomic,
+	})
+	if err != nil {
+		return err
+	}
+
+	reply.URLs = make([]BatchResult, 0, len(results))
+
+	for _, item := range results {
+		out := BatchResult{CorID: item.CorID, ShortURL: item.ShortURL}
+		if item.Err != nil {
+			out.Err = item.Err.Error()
+		}
+
+		reply.URLs = append(reply.URLs, out)
+	}
+
+	return nil
+}
+
+// Stat implements getting statistics on the use of a short URL.
+//
+// {"jsonrpc":"2.0","method":"Shortener.Stat","params":{"shortuid":"abcde"},"id":1}.
+func (s *Service) Stat(req *http.Request, args *StatArgs, reply *storage.Statistics) error {
+	if args.ShortUID == "" {
+		return ErrInvalidData
+	}
+
+	stat, err := s.service.Stat(args.ShortUID)
+	if err != nil {
+		return err
+	}
+
+	*reply = stat
+
+	return nil
+}
+
+// List returns all shortened URLs for current user.
+//
+// {"jsonrpc":"2.0","method":"Shortener.List","params":{},"id":1}.
+func (s *Service) List(req *http.Request, args *Empty, reply *ListReply) error {
+	uid := userID(req)
+	if uid == "" {
+		return ErrUserUndefined
+	}
+
+	urls, err := s.service.List(uid)
+	if err != nil {
+		return err
+	}
+
+	reply.URLs = urls
+
+	return nil
+}
+
+// Delete performs deletion of shortened URLs for current user.
+//
+// The deletion is performed asynchronously in the pool.
+// {"jsonrpc":"2.0","method":"Shortener.Delete","params":{"urls":["abcde"]},"id":1}.
+func (s *Service) Delete(req *http.Request, args *DeleteArgs, reply *DeleteReply) error {
+	uid := userID(req)
+	if uid == "" {
+		return ErrUserUndefined
+	}
+
+	if err := s.service.DeleteAsync(uid, args.URLs...); err != nil {
+		return err
+	}
+
+	reply.Accepted = true
+
+	return nil
+}
+
+// userID returns the user ID set by the authorization middleware.
+func userID(req *http.Request) string {
+	if req.URL.User == nil {
+		return ""
+	}
+
+	return req.URL.User.Username()
+}
@@ -0,0 +1,308 @@
+// Package linkcheck checks periodically that the destinations
+// of the short URLs are alive.
+//
+// The destination is requested with HEAD, the servers that don't support
+// HEAD are requested with GET. The status code, the latency and the time
+// of the check are saved in the storage, the destination is broken if it
+// responded with 4xx/5xx status code or could not be reached (DNS failure,
+// refused connection, timeout, etc.).
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/storage/pool"
+)
+
+// Default settings of the checker.
+const (
+	DefaultInterval    = time.Hour
+	DefaultConcurrency = 4
+	DefaultTimeout     = 10 * time.Second
+	DefaultBatchSize   = 100
+)
+
+// userAgent identifies the requests of the checker.
+const userAgent = "shortener-linkcheck/1.0"
+
+// maxBodyRead is the number of bytes of the body of GET response
+// read before closing it, so the connection can be reused.
+const maxBodyRead = 64 << 10
+
+// ErrPrivateAddress is an indicator that the destination resolves to
+// the loopback, private or other non-public address.
+var ErrPrivateAddress = errors.New("destination address is not public")
+
+// Store is the part of the storage used by the checker.
+type Store interface {
+	LinksToCheck(checkedBefore time.Time, limit int) ([]storage.HealthTarget, error)
+	SetHealth(shortUID string, health storage.Health) error
+}
+
+// Options represents the settings of the checker,
+// the zero values are replaced by the defaults.
+type Options struct {
+	// Client sends the requests, NewClient(DefaultTimeout) by default.
+	Client *http.Client
+	// Interval is the period of the checks of each destination.
+	Interval time.Duration
+	// Concurrency is the maximum number of simultaneous requests.
+	Concurrency int
+	// BatchSize is the maximum number of destinations checked in one round.
+	BatchSize int
+}
+
+// Checker checks the destinations of the short URLs in rounds.
+type Checker struct {
+	store       Store
+	client      *http.Client
+	interval    time.Duration
+	concurrency int
+	batchSize   int
+	// running is set while the round is queued or in progress.
+	running atomic.Bool
+}
+
+// New returns a pointer of Checker.
+func New(store Store, opts Options) *Checker {
+	if opts.Client == nil {
+		opts.Client = NewClient(DefaultTimeout)
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	return &Checker{
+		store:       store,
+		client:      opts.Client,
+		interval:    opts.Interval,
+		concurrency: opts.Concurrency,
+		batchSize:   opts.BatchSize,
+	}
+}
+
+// NewClient returns the HTTP client of the checker, which refuses to
+// connect to the non-public addresses, so the checks can't be used to
+// probe the internal network. Redirects are followed.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicOnly}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// Run adds a round of the checks to the pool at the start and then
+// at a fraction of the interval until the context is done.
+//
+// The pool executes the tasks one by one, so the round is limited
+// by the batch size and the next round is added only when the previous
+// one is finished. The round lasts no longer than the tick, the errors
+// of the rounds are logged by the pool. The slow destinations delay
+// the other tasks of the pool, so the pool must be dedicated to the checker.
+func (c *Checker) Run(ctx context.Context, workers *pool.Pool) {
+	tick := c.tick()
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		if c.running.CompareAndSwap(false, true) {
+			workers.AddTask(func() error {
+				defer c.running.Store(false)
+
+				roundCtx, cancel := context.WithTimeout(ctx, tick)
+				defer cancel()
+
+				_, err := c.CheckDue(roundCtx)
+
+				return err
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue checks the destinations that have not been checked during
+// the interval and returns the number of the checked destinations.
+func (c *Checker) CheckDue(ctx context.Context) (int, error) {
+	targets, err := c.store.LinksToCheck(time.Now().Add(-c.interval), c.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errSave error
+		slots   = make(chan struct{}, c.concurrency)
+	)
+
+	for _, target := range targets {
+		target := target
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+
+			return 0, ctx.Err()
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			health := c.Check(ctx, target.LongURL)
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := c.store.SetHealth(target.ShortUID, health); err != nil && !errors.Is(err, storage.ErrUIDNotValid) {
+				mu.Lock()
+				errSave = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return len(targets), errSave
+}
+
+// Check requests the destination and returns the result of the check.
+func (c *Checker) Check(ctx context.Context, longURL string) storage.Health {
+	checkedAt := time.Now()
+
+	statusCode, latency, err := c.request(ctx, http.MethodHead, longURL)
+	// some servers don't support HEAD or respond to it differently.
+	if err == nil && statusCode >= http.StatusBadRequest {
+		statusCode, latency, err = c.request(ctx, http.MethodGet, longURL)
+	}
+
+	health := storage.Health{
+		CheckedAt:  checkedAt,
+		StatusCode: statusCode,
+		LatencyMS:  latency.Milliseconds(),
+		Broken:     err != nil || statusCode >= http.StatusBadRequest,
+	}
+
+	if err != nil {
+		health.Error = describe(err)
+	}
+
+	return health
+}
+
+// request sends the request and returns the status code and the latency of the response.
+func (c *Checker) request(ctx context.Context, method, longURL string) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, method, longURL, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+
+	start := time.Now()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, time.Since(start), err
+	}
+
+	latency := time.Since(start)
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyRead))
+	_ = resp.Body.Close()
+
+	return resp.StatusCode, latency, nil
+}
+
+// tick returns the period of the rounds, the destinations are checked
+// in several rounds during the interval.
+func (c *Checker) tick() time.Duration {
+	const roundsPerInterval = 10
+
+	tick := c.interval / roundsPerInterval
+
+	switch {
+	case tick > time.Minute:
+		return time.Minute
+	case tick < time.Second:
+		return time.Second
+	default:
+		return tick
+	}
+}
+
+// describe returns the reason of the failed request without the URL.
+func describe(err error) string {
+	var (
+		urlErr *url.Error
+		dnsErr *net.DNSError
+	)
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return "dns: " + dnsErr.Err
+	case errors.As(err, &urlErr):
+		return urlErr.Err.Error()
+	default:
+		return err.Error()
+	}
+}
+
+// publicOnly refuses the connections to the non-public addresses.
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return ErrPrivateAddress
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package linkcheck_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/linkcheck"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/storage/pool"
+)
+
+// newServer returns the test server of the destinations:
+// /ok, /missing (404), /error (500) and /nohead (405 on HEAD).
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/nohead", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	server := newServer(t)
+	checker := linkcheck.New(nil, linkcheck.Options{Client: server.Client()})
+
+	tests := []struct {
+		name       string
+		url        string
+		statusCode int
+		broken     bool
+	}{
+		{name: "alive", url: server.URL + "/ok", statusCode: http.StatusOK},
+		{name: "HEAD is not allowed", url: server.URL + "/nohead", statusCode: http.StatusOK},
+		{name: "not found", url: server.URL + "/missing", statusCode: http.StatusNotFound, broken: true},
+		{name: "server error", url: server.URL + "/error", statusCode: http.StatusInternalServerError, broken: true},
+		{name: "DNS failure", url: "http://unknown.invalid/", broken: true},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			health := checker.Check(context.Background(), item.url)
+
+			if health.StatusCode != item.statusCode || health.Broken != item.broken {
+				t.Errorf("status code and broken should be %d and %v but received %d and %v (%s)",
+					item.statusCode, item.broken, health.StatusCode, health.Broken, health.Error)
+			}
+
+			if health.CheckedAt.IsZero() || (item.statusCode == 0) != (health.Error != "") {
+				t.Errorf("check should have time and error only for failed request but received %+v", health)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	server := newServer(t)
+	checker := linkcheck.New(nil, linkcheck.Options{Client: linkcheck.NewClient(time.Second)})
+
+	// адрес тестового сервера не публичный
+	health := checker.Check(context.Background(), server.URL+"/ok")
+	if !health.Broken || !strings.Contains(health.Error, linkcheck.ErrPrivateAddress.Error()) {
+		t.Errorf("error should be %v but received %+v", linkcheck.ErrPrivateAddress, health)
+	}
+}
+
+func TestCheckDue(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	const concurrency = 2
+
+	var (
+		mu           sync.Mutex
+		active, peak int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+
+		if active > peak {
+			peak = active
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		if strings.HasPrefix(r.URL.Path, "/dead") {
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	store := storage.NewDefault(config.New(config.Options{}))
+	userID := "1"
+	store.Create()
+
+	paths := []string{"/a", "/b", "/c", "/dead/d", "/dead/e"}
+	shortUIDs := make(map[string]string, len(paths))
+
+	for _, path := range paths {
+		shortUID, err := store.AddURL(storage.NewURL{LongURL: server.URL + path, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		shortUIDs[path] = shortUID
+	}
+
+	checker := linkcheck.New(store, linkcheck.Options{Client: server.Client(), Concurrency: concurrency})
+
+	checked, err := checker.CheckDue(context.Background())
+	if err != nil || checked != len(paths) {
+		t.Fatalf("number of checked URLs should be %d but received %d (%v)", len(paths), checked, err)
+	}
+
+	if peak > concurrency {
+		t.Errorf("number of simultaneous requests should be at most %d but received %d", concurrency, peak)
+	}
+
+	stat, err := store.Stat(shortUIDs["/dead/d"])
+	if err != nil || stat.Health == nil || !stat.Health.Broken || stat.Health.StatusCode != http.StatusGone {
+		t.Errorf("statistics should contain broken destination but received %+v (%v)", stat.Health, err)
+	}
+
+	page, err := store.ListUrlsUser(userID, storage.ListOptions{Broken: true})
+	if err != nil || page.Total != 2 {
+		t.Errorf("number of broken URLs should be 2 but received %d (%v)", page.Total, err)
+	}
+
+	// проверенные ссылки не проверяются повторно до истечения интервала
+	if checked, err = checker.CheckDue(context.Background()); err != nil || checked != 0 {
+		t.Errorf("number of checked URLs should be 0 but received %d (%v)", checked, err)
+	}
+
+	// удаленные ссылки не проверяются
+	if err := store.DelUrls(userID, shortUIDs["/a"]); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := store.LinksToCheck(time.Now().Add(time.Minute), 10)
+	if err != nil || len(targets) != len(paths)-1 {
+		t.Errorf("number of URLs to check should be %d but received %d (%v)", len(paths)-1, len(targets), err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	server := newServer(t)
+	store := storage.NewDefault(config.New(config.Options{}))
+
+	shortUID, err := store.AddURL(storage.NewURL{LongURL: server.URL + "/missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workers := pool.Init(logger.NewLogger())
+	go workers.Run()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go linkcheck.New(store, linkcheck.Options{Client: server.Client()}).Run(ctx, workers)
+
+	// первая проверка выполняется пулом сразу после запуска
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if stat, err := store.Stat(shortUID); err == nil && stat.Health != nil {
+			if !stat.Health.Broken {
+				t.Errorf("destination should be broken but received %+v", stat.Health)
+			}
+
+			return
+		}
+	}
+
+	t.Error("destination has not been checked")
+}
@@ -0,0 +1,77 @@
+package qr
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+)
+
+// DefaultCacheSize is the number of images kept by the cache by default.
+const DefaultCacheSize = 1024
+
+// Cache renders the QR codes and keeps the recently used images.
+//
+// The data of the returned images is shared and must not be changed.
+type Cache struct {
+	entries map[string]*list.Element
+	// order of the entries from the recently used.
+	order *list.List
+	size  int
+	mu    sync.Mutex
+}
+
+// cacheEntry represents the image with its key.
+type cacheEntry struct {
+	key   string
+	image Image
+}
+
+// NewCache returns a pointer of Cache keeping at most size images.
+func NewCache(size int) *Cache {
+	return &Cache{
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+		size:    size,
+	}
+}
+
+// Render returns the cached QR code of the content
+// or renders it and puts it into the cache.
+func (c *Cache) Render(content string, opts Options) (Image, error) {
+	opts, err := opts.Validate()
+	if err != nil {
+		return Image{}, err
+	}
+
+	key := opts.Format + ":" + opts.Level + ":" + strconv.Itoa(opts.Size) + ":" +
+		strconv.Itoa(opts.Margin) + ":" + content
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+
+		return element.Value.(*cacheEntry).image, nil
+	}
+	c.mu.Unlock()
+
+	img, err := Render(content, opts)
+	if err != nil {
+		return Image{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && c.size > 0 {
+		c.entries[key] = c.order.PushFront(&cacheEntry{key: key, image: img})
+
+		if c.order.Len() > c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).key)
+		}
+	}
+
+	return img, nil
+}
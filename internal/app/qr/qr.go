@@ -0,0 +1,234 @@
+// Package qr renders QR codes of short URLs in PNG and SVG formats.
+//
+// The image is square, the modules are drawn black on white with
+// the quiet zone (margin) measured in modules around the code.
+// The rendered images are kept by Cache, since the QR code of
+// the short URL never changes.
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Formats of the image.
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Error correction levels, the higher level restores more damaged code
+// at the cost of the bigger number of modules.
+const (
+	LevelLow      = "L"
+	LevelMedium   = "M"
+	LevelQuartile = "Q"
+	LevelHigh     = "H"
+)
+
+// Limits of the options.
+const (
+	DefaultSize   = 256
+	MinSize       = 64
+	MaxSize       = 2048
+	DefaultMargin = 4
+	MaxMargin     = 16
+)
+
+// List of typical errors.
+var (
+	ErrInvalidFormat = errors.New("format of QR code must be png or svg")
+	ErrInvalidLevel  = errors.New("error correction level of QR code must be L, M, Q or H")
+	ErrInvalidSize   = fmt.Errorf("size of QR code must be from %d to %d pixels", MinSize, MaxSize)
+	ErrInvalidMargin = fmt.Errorf("margin of QR code must be from 0 to %d modules", MaxMargin)
+)
+
+// Options represents the options of the image.
+type Options struct {
+	Format string
+	Level  string
+	// Size is the width and the height of the image in pixels.
+	Size int
+	// Margin is the width of the quiet zone in modules.
+	Margin int
+}
+
+// Image represents the rendered QR code.
+type Image struct {
+	ContentType string
+	Data        []byte
+}
+
+// DefaultOptions returns the options of the PNG image with medium
+// error correction and the standard quiet zone.
+func DefaultOptions() Options {
+	return Options{
+		Format: FormatPNG,
+		Level:  LevelMedium,
+		Size:   DefaultSize,
+		Margin: DefaultMargin,
+	}
+}
+
+// Validate returns the options with the level in upper case
+// and the format in lower case or the error if the options are invalid.
+func (o Options) Validate() (Options, error) {
+	o.Format = strings.ToLower(o.Format)
+	o.Level = strings.ToUpper(o.Level)
+
+	switch {
+	case o.Format != FormatPNG && o.Format != FormatSVG:
+		return o, ErrInvalidFormat
+	case o.Size < MinSize || o.Size > MaxSize:
+		return o, ErrInvalidSize
+	case o.Margin < 0 || o.Margin > MaxMargin:
+		return o, ErrInvalidMargin
+	}
+
+	if _, err := recoveryLevel(o.Level); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// DataURI returns the image as the data URI, e.g. for the src attribute of img.
+func (img Image) DataURI() string {
+	return "data:" + img.ContentType + ";base64," + base64.StdEncoding.EncodeToString(img.Data)
+}
+
+// Render renders the QR code of the content.
+func Render(content string, opts Options) (Image, error) {
+	opts, err := opts.Validate()
+	if err != nil {
+		return Image{}, err
+	}
+
+	level, _ := recoveryLevel(opts.Level)
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return Image{}, fmt.Errorf("failed encode QR code: %w", err)
+	}
+
+	code.DisableBorder = true
+	modules := withMargin(code.Bitmap(), opts.Margin)
+
+	if opts.Format == FormatSVG {
+		return Image{ContentType: "image/svg+xml", Data: renderSVG(modules, opts.Size)}, nil
+	}
+
+	data, err := renderPNG(modules, opts.Size)
+	if err != nil {
+		return Image{}, err
+	}
+
+	return Image{ContentType: "image/png", Data: data}, nil
+}
+
+// renderPNG draws the modules scaled to the whole number of pixels
+// and centered in the image of the size.
+func renderPNG(modules [][]bool, size int) ([]byte, error) {
+	scale := size / len(modules)
+	if scale == 0 {
+		scale = 1
+		size = len(modules)
+	}
+
+	offset := (size - scale*len(modules)) / 2
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(offset+x*scale+dx, offset+y*scale+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buffer, img); err != nil {
+		return nil, fmt.Errorf("failed encode PNG: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// renderSVG draws the modules as one path, the view box is measured in modules.
+func renderSVG(modules [][]bool, size int) []byte {
+	var buffer bytes.Buffer
+
+	fmt.Fprintf(&buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+		`viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, len(modules), len(modules))
+	fmt.Fprintf(&buffer, `<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="`)
+
+	for y, row := range modules {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+
+			// the adjacent dark modules of the row are joined into one rectangle.
+			width := 1
+			for x+width < len(row) && row[x+width] {
+				width++
+			}
+
+			fmt.Fprintf(&buffer, "M%d %dh%dv1h-%dz", x, y, width, width)
+
+			x += width - 1
+		}
+	}
+
+	buffer.WriteString(`"/></svg>`)
+
+	return buffer.Bytes()
+}
+
+// withMargin returns the modules surrounded by the quiet zone.
+func withMargin(modules [][]bool, margin int) [][]bool {
+	size := len(modules) + 2*margin
+	out := make([][]bool, size)
+
+	for y := range out {
+		out[y] = make([]bool, size)
+
+		if y >= margin && y < size-margin {
+			copy(out[y][margin:], modules[y-margin])
+		}
+	}
+
+	return out
+}
+
+// recoveryLevel returns the error correction level of the encoder.
+func recoveryLevel(level string) (qrcode.RecoveryLevel, error) {
+	switch level {
+	case LevelLow:
+		return qrcode.Low, nil
+	case LevelMedium:
+		return qrcode.Medium, nil
+	case LevelQuartile:
+		return qrcode.High, nil
+	case LevelHigh:
+		return qrcode.Highest, nil
+	default:
+		return 0, ErrInvalidLevel
+	}
+}
@@ -0,0 +1,93 @@
+package qr_test
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/qr"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	content := "http://localhost:8080/AbCdEf"
+
+	tests := []struct {
+		name string
+		opts qr.Options
+		err  error
+	}{
+		{name: "png", opts: qr.DefaultOptions()},
+		{name: "svg", opts: qr.Options{Format: "SVG", Level: "h", Size: 512, Margin: 0}},
+		{name: "png without margin", opts: qr.Options{Format: qr.FormatPNG, Level: qr.LevelLow, Size: 100}},
+		{name: "unknown format", opts: qr.Options{Format: "gif", Level: qr.LevelLow, Size: 256}, err: qr.ErrInvalidFormat},
+		{name: "unknown level", opts: qr.Options{Format: qr.FormatPNG, Level: "X", Size: 256}, err: qr.ErrInvalidLevel},
+		{name: "small size", opts: qr.Options{Format: qr.FormatPNG, Level: qr.LevelLow, Size: 10}, err: qr.ErrInvalidSize},
+		{
+			name: "big margin", opts: qr.Options{Format: qr.FormatPNG, Level: qr.LevelLow, Size: 256, Margin: 100},
+			err: qr.ErrInvalidMargin,
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			img, err := qr.Render(content, item.opts)
+			if !errors.Is(err, item.err) {
+				t.Fatalf("error should be %v but received %v", item.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if strings.EqualFold(item.opts.Format, qr.FormatSVG) {
+				if img.ContentType != "image/svg+xml" || !bytes.HasPrefix(img.Data, []byte("<svg")) {
+					t.Errorf("image should be SVG but received %s", img.ContentType)
+				}
+
+				return
+			}
+
+			decoded, err := png.Decode(bytes.NewReader(img.Data))
+			if err != nil || img.ContentType != "image/png" {
+				t.Fatalf("image should be PNG but received %s (%v)", img.ContentType, err)
+			}
+
+			if size := decoded.Bounds().Dx(); size != item.opts.Size || decoded.Bounds().Dy() != size {
+				t.Errorf("size of image should be %d but received %d", item.opts.Size, size)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	cache := qr.NewCache(1)
+
+	first, err := cache.Render("http://localhost:8080/a", qr.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached, _ := cache.Render("http://localhost:8080/a", qr.DefaultOptions())
+	if &first.Data[0] != &cached.Data[0] {
+		t.Error("image should be returned from the cache")
+	}
+
+	// первое изображение вытесняется вторым
+	if _, err := cache.Render("http://localhost:8080/b", qr.DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	rendered, _ := cache.Render("http://localhost:8080/a", qr.DefaultOptions())
+	if &first.Data[0] == &rendered.Data[0] || !bytes.Equal(first.Data, rendered.Data) {
+		t.Error("image should be rendered again after eviction")
+	}
+}
@@ -5,29 +5,55 @@ import (
 	"net/http"
 
 	"github.com/alaleks/shortener/internal/app/handlers"
-	"github.com/alaleks/shortener/proto"
+	"github.com/alaleks/shortener/internal/app/jsonrpc"
 	"github.com/gorilla/mux"
-	rpc "github.com/gorilla/rpc"
 )
 
+// Mount represents the handler served on all paths with the prefix.
+type Mount struct {
+	Handler http.Handler
+	Prefix  string
+}
+
 // Create registers application routers.
 // This functions returns http.Handler interface.
-func Create(handler *handlers.Handlers) http.Handler {
+func Create(handler *handlers.Handlers, mounts ...Mount) http.Handler {
 	mux := mux.NewRouter()
 	mux.HandleFunc("/", handler.ShortenURL).Methods(http.MethodPost)
 	mux.HandleFunc("/ping", handler.Ping).Methods(http.MethodGet)
 	mux.HandleFunc("/{uid}", handler.ParseShortURL).Methods(http.MethodGet)
+	mux.HandleFunc("/{uid}/qr", handler.GetQRCode).Methods(http.MethodGet)
+	mux.HandleFunc("/{uid}/preview", handler.GetPreview).Methods(http.MethodGet)
 	mux.HandleFunc("/api/shorten", handler.ShortenURLAPI).Methods(http.MethodPost)
 	mux.HandleFunc("/api/{uid}/statistics", handler.GetStatAPI).Methods(http.MethodGet)
 	mux.HandleFunc("/api/user/urls", handler.GetUsersURL).Methods(http.MethodGet)
 	mux.HandleFunc("/api/shorten/batch", handler.ShortenURLBatch).Methods(http.MethodPost)
 	mux.HandleFunc("/api/user/urls", handler.ShortenDeletePool).Methods(http.MethodDelete)
+	mux.HandleFunc("/api/user/urls/import", handler.ImportURLs).Methods(http.MethodPost)
+	mux.HandleFunc("/api/user/urls/export", handler.ExportURLs).Methods(http.MethodGet)
+	mux.HandleFunc("/api/user/urls/{uid}", handler.SetLabels).Methods(http.MethodPatch)
+	mux.HandleFunc("/api/user/urls/{uid}/rules", handler.SetRules).Methods(http.MethodPut)
+	mux.HandleFunc("/api/user/urls/{uid}/variants", handler.SetVariants).Methods(http.MethodPut)
+	mux.HandleFunc("/api/user/tags/{tag}/statistics", handler.GetTagStat).Methods(http.MethodGet)
+	mux.HandleFunc("/api/user/folders/{folder}/statistics", handler.GetFolderStat).Methods(http.MethodGet)
+	mux.HandleFunc("/api/user/webhooks", handler.AddWebhook).Methods(http.MethodPost)
+	mux.HandleFunc("/api/user/webhooks", handler.GetWebhooks).Methods(http.MethodGet)
+	mux.HandleFunc("/api/user/webhooks/{id}", handler.DeleteWebhook).Methods(http.MethodDelete)
+	mux.HandleFunc("/api/user/webhooks/{id}/deliveries", handler.GetWebhookDeliveries).Methods(http.MethodGet)
+	mux.HandleFunc("/api/user/webhooks/{id}/test", handler.TestWebhook).Methods(http.MethodPost)
 	mux.HandleFunc("/api/internal/stats", handler.StatsInternal).Methods(http.MethodGet)
 
-	// mux rpc
-	rpcServer := rpc.NewServer()
-	rpcServer.RegisterService(new(proto.Server), "")
-	mux.Handle("/rpc", rpcServer)
+	// JSON-RPC 2.0 API
+	mux.Handle("/rpc", jsonrpc.New(handler.Service)).Methods(http.MethodPost)
+
+	// the password of the protected short URL, it's registered after
+	// the other POST routes with one path segment.
+	mux.HandleFunc("/{uid}", handler.UnlockShortURL).Methods(http.MethodPost)
+
+	// e.g. the REST/JSON gateway of the grpc service
+	for _, mount := range mounts {
+		mux.PathPrefix(mount.Prefix + "/").Handler(mount.Handler)
+	}
 
 	return mux
 }
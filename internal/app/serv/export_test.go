@@ -0,0 +1,8 @@
+package serv
+
+// The grpc server parts exported for tests.
+var (
+	GRPCCredentials = grpcCredentials
+	NewHealthServer = newHealthServer
+	WatchHealth     = (*healthServer).watch
+)
@@ -0,0 +1,130 @@
+package serv
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	pb "github.com/alaleks/shortener/proto"
+	"golang.org/x/crypto/acme/autocert"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ErrInvalidClientCA is an indicator that the client CA file
+// does not contain valid certificates.
+var ErrInvalidClientCA = errors.New("client CA file does not contain valid PEM certificates")
+
+const healthCheckInterval = 10 * time.Second
+
+// grpcCredentials returns the transport credentials of the grpc server.
+//
+// If the certificate files are set, they are used, otherwise if TLS is enabled
+// the certificate is obtained by autocert, as for the web server.
+// Nil is returned when TLS is disabled.
+func grpcCredentials(cfg config.Recipient) (credentials.TransportCredentials, error) {
+	tlsFiles := cfg.GetGRPCTLS()
+
+	switch {
+	case tlsFiles.Enabled():
+		cert, err := tls.LoadX509KeyPair(tlsFiles.CertFile, tlsFiles.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed load grpc certificate: %w", err)
+		}
+
+		tlsConfig := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+
+		if tlsFiles.ClientCAFile != "" {
+			pem, err := os.ReadFile(tlsFiles.ClientCAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed read client CA: %w", err)
+			}
+
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, ErrInvalidClientCA
+			}
+
+			tlsConfig.ClientCAs = pool
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		return credentials.NewTLS(tlsConfig), nil
+	case cfg.EnableTLS():
+		return credentials.NewTLS(&tls.Config{
+			GetCertificate: newCertManager(cfg.GetServAddr()).GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		}), nil
+	default:
+		return nil, nil
+	}
+}
+
+// newCertManager returns the manager of certificates obtained by ACME.
+func newCertManager(host string) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache("cert"),
+		HostPolicy: autocert.HostWhitelist(host),
+	}
+}
+
+// healthServer implements the grpc.health.v1 service, the status of the server
+// depends on the availability of the storage.
+type healthServer struct {
+	*health.Server
+	ping func() error
+}
+
+// newHealthServer returns a pointer of healthServer.
+func newHealthServer(ping func() error) *healthServer {
+	return &healthServer{Server: health.NewServer(), ping: ping}
+}
+
+// Check implements the Check method of the health service,
+// the storage is checked on each request.
+func (h *healthServer) Check(ctx context.Context,
+	in *healthpb.HealthCheckRequest,
+) (*healthpb.HealthCheckResponse, error) {
+	h.update()
+
+	return h.Server.Check(ctx, in)
+}
+
+// watch updates the status periodically, so that clients of the Watch
+// method are notified of changes. It is stopped when the context is done.
+func (h *healthServer) watch(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		h.update()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update sets the status of all services by the result of the storage ping.
+func (h *healthServer) update() {
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if err := h.ping(); err != nil {
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	// the empty name means the server as a whole.
+	h.SetServingStatus("", servingStatus)
+	h.SetServingStatus(pb.Shortener_ServiceDesc.ServiceName, servingStatus)
+}
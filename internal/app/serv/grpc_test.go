@@ -0,0 +1,300 @@
+package serv_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/serv"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// certificate represents the generated certificate with its key.
+type certificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	tls  tls.Certificate
+}
+
+// newCertificate returns the certificate signed by the parent,
+// the certificate without the parent is the self-signed CA.
+func newCertificate(t *testing.T, parent *certificate, name string) *certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &certificate{
+		cert: cert,
+		key:  key,
+		tls:  tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert},
+	}
+}
+
+// write writes the certificate and its key in PEM format to the directory
+// and returns the paths of the files.
+func (c *certificate) write(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+// serveHealth starts the grpc server with the health service
+// and returns its address.
+func serveHealth(t *testing.T, health healthpb.HealthServer, opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, health)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// check calls the Check method of the health service.
+func check(addr string, creds credentials.TransportCredentials) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return 0, err
+	}
+
+	defer conn.Close()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.GetStatus(), nil
+}
+
+func TestGRPCCredentials(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	dir := t.TempDir()
+	ca := newCertificate(t, nil, "Test CA")
+	server := newCertificate(t, ca, "localhost")
+	client := newCertificate(t, ca, "client")
+	stranger := newCertificate(t, newCertificate(t, nil, "Other CA"), "stranger")
+
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := server.write(t, dir, "server")
+
+	invalidCA := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidCA, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// без сертификатов TLS отключен
+	if creds, err := serv.GRPCCredentials(config.New(config.Options{})); creds != nil || err != nil {
+		t.Errorf("credentials should be nil but received %v (%v)", creds, err)
+	}
+
+	invalid := config.New(config.Options{})
+	invalid.DefineOptionsFlags([]string{"shortener", "-grpc-cert", certFile, "-grpc-key", keyFile, "-grpc-client-ca", invalidCA})
+
+	if _, err := serv.GRPCCredentials(invalid); !errors.Is(err, serv.ErrInvalidClientCA) {
+		t.Errorf("error should be %v but received %v", serv.ErrInvalidClientCA, err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name     string
+		clientCA string
+		client   []tls.Certificate
+		valid    bool
+	}{
+		{name: "TLS", valid: true},
+		{name: "mTLS с сертификатом клиента", clientCA: caFile, client: []tls.Certificate{client.tls}, valid: true},
+		{name: "mTLS без сертификата клиента", clientCA: caFile},
+		{name: "mTLS с сертификатом другого CA", clientCA: caFile, client: []tls.Certificate{stranger.tls}},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			appConf := config.New(config.Options{})
+			appConf.DefineOptionsFlags([]string{"shortener", "-grpc-cert", certFile, "-grpc-key", keyFile,
+				"-grpc-client-ca", item.clientCA})
+
+			creds, err := serv.GRPCCredentials(appConf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			health := serv.NewHealthServer(func() error { return nil })
+			addr := serveHealth(t, health, grpc.Creds(creds))
+
+			status, err := check(addr, credentials.NewTLS(&tls.Config{
+				RootCAs:      roots,
+				Certificates: item.client,
+				ServerName:   "localhost",
+				MinVersion:   tls.VersionTLS12,
+			}))
+			if item.valid && (err != nil || status != healthpb.HealthCheckResponse_SERVING) {
+				t.Errorf("status should be %s but received %s (%v)", healthpb.HealthCheckResponse_SERVING, status, err)
+			}
+
+			if !item.valid && err == nil {
+				t.Error("request without the trusted client certificate should fail")
+			}
+		})
+	}
+}
+
+func TestHealthServer(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	var unavailable atomic.Bool
+
+	health := serv.NewHealthServer(func() error {
+		if unavailable.Load() {
+			return errors.New("storage is unavailable")
+		}
+
+		return nil
+	})
+
+	addr := serveHealth(t, health)
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := healthpb.NewHealthClient(conn)
+
+	// статус меняется при проверке хранилища
+	var stream healthpb.Health_WatchClient
+
+	for _, want := range []healthpb.HealthCheckResponse_ServingStatus{
+		healthpb.HealthCheckResponse_SERVING,
+		healthpb.HealthCheckResponse_NOT_SERVING,
+	} {
+		unavailable.Store(want != healthpb.HealthCheckResponse_SERVING)
+
+		res, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil || res.GetStatus() != want {
+			t.Fatalf("check status should be %s but received %s (%v)", want, res.GetStatus(), err)
+		}
+
+		if stream == nil {
+			if stream, err = client.Watch(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if res, err := stream.Recv(); err != nil || res.GetStatus() != want {
+			t.Errorf("watched status should be %s but received %s (%v)", want, res.GetStatus(), err)
+		}
+	}
+
+	// фоновая проверка уведомляет клиентов Watch
+	unavailable.Store(false)
+
+	watchCtx, stop := context.WithCancel(context.Background())
+	watching := make(chan struct{})
+
+	go func() {
+		serv.WatchHealth(health, watchCtx)
+		close(watching)
+	}()
+
+	if res, err := stream.Recv(); err != nil || res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("watched status should be %s but received %s (%v)", healthpb.HealthCheckResponse_SERVING, res.GetStatus(), err)
+	}
+
+	stop()
+
+	select {
+	case <-watching:
+	case <-time.After(5 * time.Second):
+		t.Error("watch should be stopped when the context is done")
+	}
+}
@@ -1,4 +1,5 @@
-// The auth package implements Cookie-based authorization.
+// The auth package implements Cookie-based authorization
+// and token-based authorization for the grpc server.
 package auth
 
 import (
@@ -26,12 +27,13 @@ const (
 // Auth stores storing a link to storage and a secret key as an array of bytes.
 type Auth struct {
 	store     storage.Storage
+	methods   map[string]Access
 	secretKey []byte
 }
 
 // TurnOn enables on-site authorization.
 func TurnOn(store storage.Storage, secretKey []byte) Auth {
-	return Auth{store: store, secretKey: secretKey}
+	return Auth{store: store, secretKey: secretKey, methods: make(map[string]Access)}
 }
 
 // CreateSigningOld (Deprecated) creates a signature for the cookie.
@@ -91,6 +93,10 @@ func (a *Auth) ReadSigning(cookieVal string) (uint, error) {
 		return 0, fmt.Errorf("cookie decoding error: %w", err)
 	}
 
+	if len(signedVal) != sha256.Size+8 {
+		return 0, ErrInvalidSign
+	}
+
 	signature := signedVal[:sha256.Size]
 	mac := hmac.New(sha256.New, a.secretKey)
 	mac.Write(signedVal[sha256.Size:])
@@ -103,6 +109,19 @@ func (a *Auth) ReadSigning(cookieVal string) (uint, error) {
 	return uint(int64(binary.LittleEndian.Uint64(signedVal[sha256.Size:]))), nil
 }
 
+// Identify returns the user ID from a valid authorization cookie.
+//
+// Unlike Authorization, it does not create a new user if the cookie
+// is missing or invalid.
+func (a *Auth) Identify(req *http.Request) (uint, error) {
+	authCookie, err := getCookie(req, cookieName)
+	if err != nil {
+		return 0, err
+	}
+
+	return a.ReadSigning(authCookie.Value)
+}
+
 // Authorization method middleware, which performs an authorization check
 // or creates a new user if the authorization cookie value is empty or invalid.
 func (a *Auth) Authorization(handler http.Handler) http.Handler {
@@ -113,8 +132,7 @@ func (a *Auth) Authorization(handler http.Handler) http.Handler {
 		if authCookie == nil || err != nil {
 			userID = a.store.Create()
 			http.SetCookie(writer, setCookie(a.CreateSigning(userID), req.TLS != nil))
-			req.URL.User = url.User(strconv.Itoa(int(userID)))
-			handler.ServeHTTP(writer, req)
+			handler.ServeHTTP(writer, setUser(req, userID))
 
 			return
 		}
@@ -123,17 +141,24 @@ func (a *Auth) Authorization(handler http.Handler) http.Handler {
 		if err != nil {
 			userID = a.store.Create()
 			http.SetCookie(writer, setCookie(a.CreateSigning(userID), req.TLS != nil))
-			req.URL.User = url.User(strconv.Itoa(int(userID)))
-			handler.ServeHTTP(writer, req)
+			handler.ServeHTTP(writer, setUser(req, userID))
 
 			return
 		}
 
-		req.URL.User = url.User(strconv.Itoa(int(userID)))
-		handler.ServeHTTP(writer, req)
+		handler.ServeHTTP(writer, setUser(req, userID))
 	})
 }
 
+// setUser sets the user ID in the request URL and in the request context,
+// the context is used by handlers of the grpc gateway.
+func setUser(req *http.Request, userID uint) *http.Request {
+	uid := strconv.Itoa(int(userID))
+	req.URL.User = url.User(uid)
+
+	return req.WithContext(NewContext(req.Context(), uid))
+}
+
 func setCookie(sign string, needSSLCheck bool) *http.Cookie {
 	cookie := http.Cookie{
 		Name:     cookieName,
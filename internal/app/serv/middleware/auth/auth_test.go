@@ -1,9 +1,16 @@
 package auth_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestSigning(t *testing.T) {
@@ -17,6 +24,33 @@ func TestSigning(t *testing.T) {
 	}
 }
 
+func TestLinkSigning(t *testing.T) {
+	a := auth.TurnOn(nil, []byte("SECRET_KEY"))
+	sign := a.CreateLinkSigning("abc", time.Now().Add(time.Minute))
+
+	// данные для теста
+	tests := []struct {
+		name     string
+		sign     string
+		shortUID string
+		err      error
+	}{
+		{name: "valid", sign: sign, shortUID: "abc"},
+		{name: "another short url", sign: sign, shortUID: "abd", err: auth.ErrInvalidSign},
+		{name: "user signing", sign: a.CreateSigning(1), shortUID: "", err: auth.ErrInvalidSign},
+		{
+			name: "expired", sign: a.CreateLinkSigning("abc", time.Now().Add(-time.Minute)),
+			shortUID: "abc", err: auth.ErrSigningExpired,
+		},
+	}
+
+	for _, item := range tests {
+		if err := a.ReadLinkSigning(item.sign, item.shortUID); !errors.Is(err, item.err) {
+			t.Errorf("%s: error should be %v but received %v", item.name, item.err, err)
+		}
+	}
+}
+
 func BenchmarkCreateSigning(b *testing.B) {
 	b.StopTimer()
 	a := auth.TurnOn(nil, []byte("SECRET_KEY"))
@@ -56,3 +90,53 @@ func BenchmarkReadSigning(b *testing.B) {
 		}
 	})
 }
+
+func TestUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	const method = "/test/Method"
+
+	a := auth.TurnOn(nil, []byte("SECRET_KEY"))
+	a.Methods(auth.Required, method)
+
+	// данные для теста
+	tests := []struct {
+		name   string
+		token  string
+		userID string
+		code   codes.Code
+	}{
+		{name: "valid token", token: a.CreateSigning(7), userID: "7", code: codes.OK},
+		{name: "missing token", token: "", code: codes.Unauthenticated},
+		{name: "invalid token", token: "invalid", code: codes.Unauthenticated},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if item.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("Authorization", item.token))
+			}
+
+			var userID string
+
+			_, err := a.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+				func(ctx context.Context, req any) (any, error) {
+					userID, _ = auth.UserFromContext(ctx)
+
+					return nil, nil
+				})
+
+			if code := status.Code(err); code != item.code {
+				t.Errorf("code should be %s but received %s", item.code, code)
+			}
+
+			if userID != item.userID {
+				t.Errorf("user ID should be %q but received %q", item.userID, userID)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidMetadataAuth is an indicator that the authorization token
+// in metadata is missing or invalid.
+var ErrInvalidMetadataAuth = errors.New("invalid authorization token in metadata")
+
+const mdAuthName = "Authorization"
+
+// Access represents the level of authorization required by a grpc method.
+type Access int
+
+// List of access levels.
+const (
+	// Public methods do not require authorization,
+	// but the user is defined if the token is valid.
+	Public Access = iota
+	// Required methods require a valid token.
+	Required
+	// CreateUser methods create a new user if the token is missing.
+	CreateUser
+)
+
+// ctxKey is the key for the user ID in the context.
+type ctxKey struct{}
+
+// holderKey is the key for the holder of the user ID in the context.
+type holderKey struct{}
+
+// holder keeps the user ID placed in the derived contexts.
+type holder struct {
+	userID string
+	mu     sync.Mutex
+}
+
+// NewContext returns a copy of the context with the user ID,
+// the user ID is also kept by the holder of the context if any.
+func NewContext(ctx context.Context, userID string) context.Context {
+	if h, ok := ctx.Value(holderKey{}).(*holder); ok {
+		h.mu.Lock()
+		h.userID = userID
+		h.mu.Unlock()
+	}
+
+	return context.WithValue(ctx, ctxKey{}, userID)
+}
+
+// WithHolder returns a copy of the context with the holder of the user ID.
+//
+// The interceptors placed before the authorization interceptor in the chain
+// read the user ID from such context by UserFromContext after the handler returns.
+func WithHolder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, holderKey{}, &holder{})
+}
+
+// UserFromContext returns the user ID stored in the context
+// or kept by its holder.
+func UserFromContext(ctx context.Context) (string, bool) {
+	if userID, ok := ctx.Value(ctxKey{}).(string); ok && userID != "" {
+		return userID, true
+	}
+
+	h, ok := ctx.Value(holderKey{}).(*holder)
+	if !ok {
+		return "", false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.userID, h.userID != ""
+}
+
+// Methods sets the access level for grpc methods.
+//
+// Methods must be passed by their full name, methods
+// without the access level set are Public.
+func (a *Auth) Methods(access Access, fullMethods ...string) {
+	for _, method := range fullMethods {
+		a.methods[method] = access
+	}
+}
+
+// UnaryInterceptor is a grpc interceptor that performs authorization
+// by the token in the metadata and places the user ID in the context.
+//
+// The token is sent back in the response header, the new token is sent
+// if the user has been created.
+func (a *Auth) UnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := a.authGRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor is a grpc interceptor that performs authorization
+// by the token in the metadata and places the user ID in the stream context.
+func (a *Auth) StreamInterceptor(srv any, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	ctx, err := a.authGRPC(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+}
+
+func (a *Auth) authGRPC(ctx context.Context, fullMethod string) (context.Context, error) {
+	var (
+		token  string
+		access = a.methods[fullMethod]
+	)
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(mdAuthName); len(values) > 0 {
+			token = values[0]
+		}
+	}
+
+	if token == "" {
+		switch access {
+		case CreateUser:
+			userID := a.store.Create()
+			token = a.CreateSigning(userID)
+			_ = grpc.SetHeader(ctx, metadata.Pairs(mdAuthName, token))
+
+			return NewContext(ctx, strconv.Itoa(int(userID))), nil
+		case Required:
+			return ctx, status.Error(codes.Unauthenticated, ErrInvalidMetadataAuth.Error())
+		default:
+			return ctx, nil
+		}
+	}
+
+	userID, err := a.ReadSigning(token)
+	if err != nil {
+		if access == Public {
+			return ctx, nil
+		}
+
+		return ctx, status.Error(codes.Unauthenticated, ErrInvalidMetadataAuth.Error())
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(mdAuthName, token))
+
+	return NewContext(ctx, strconv.Itoa(int(userID))), nil
+}
+
+// serverStream wraps grpc.ServerStream to replace its context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream with the user ID.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSigningExpired is an indicator that the signature is expired.
+var ErrSigningExpired = errors.New("this signing is expired")
+
+// linkSigningPrefix separates the signatures of short URLs
+// from the signatures of users made with the same key.
+const linkSigningPrefix = "link:"
+
+// CreateLinkSigning creates a signature of access to the short URL
+// valid until the expiry time.
+//
+// The signature is made as the signature of the user ID, the signed value
+// is the expiry time in Unix seconds followed by the short URL ID.
+func (a *Auth) CreateLinkSigning(shortUID string, expires time.Time) string {
+	value := make([]byte, 8, 8+len(shortUID))
+	binary.LittleEndian.PutUint64(value, uint64(expires.Unix()))
+	value = append(value, shortUID...)
+
+	signature := append(a.signLink(value), value...)
+
+	return base64.URLEncoding.EncodeToString(signature)
+}
+
+// ReadLinkSigning checks the signature of access to the short URL.
+func (a *Auth) ReadLinkSigning(signing, shortUID string) error {
+	signedVal, err := base64.URLEncoding.DecodeString(signing)
+	if err != nil {
+		return fmt.Errorf("signing decoding error: %w", err)
+	}
+
+	if len(signedVal) < sha256.Size+8 {
+		return ErrInvalidSign
+	}
+
+	value := signedVal[sha256.Size:]
+
+	if !hmac.Equal(signedVal[:sha256.Size], a.signLink(value)) || string(value[8:]) != shortUID {
+		return ErrInvalidSign
+	}
+
+	if time.Now().Unix() > int64(binary.LittleEndian.Uint64(value[:8])) {
+		return ErrSigningExpired
+	}
+
+	return nil
+}
+
+// signLink returns the HMAC-SHA256 of the value of the short URL signature.
+func (a *Auth) signLink(value []byte) []byte {
+	mac := hmac.New(sha256.New, a.secretKey)
+	mac.Write([]byte(linkSigningPrefix))
+	mac.Write(value)
+
+	return mac.Sum(nil)
+}
@@ -26,6 +26,11 @@ func (w writerGzip) Write(b []byte) (int, error) {
 	return n, nil
 }
 
+// Unwrap returns the original writer, it is used by http.ResponseController.
+func (w writerGzip) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 type readerCloserGzip struct {
 	*gzip.Reader
 	io.Closer
@@ -0,0 +1,108 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/interceptor"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const method = "/test/Method"
+
+func TestRecoveryAndMetrics(t *testing.T) {
+	t.Parallel()
+
+	log := logger.NewLogger()
+	recovery := interceptor.NewRecovery(log)
+	metrics := interceptor.NewMetrics()
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	// данные для теста
+	handlers := []grpc.UnaryHandler{
+		func(ctx context.Context, req any) (any, error) { return nil, nil },
+		func(ctx context.Context, req any) (any, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		},
+		func(ctx context.Context, req any) (any, error) { panic(errors.New("test panic")) },
+	}
+
+	for _, handler := range handlers {
+		handler := handler
+
+		// metrics -> recovery -> handler
+		_, err := metrics.UnaryInterceptor(context.Background(), nil, info,
+			func(ctx context.Context, req any) (any, error) {
+				return recovery.UnaryInterceptor(ctx, req, info, handler)
+			})
+
+		if err != nil && status.Code(err) == codes.Unknown {
+			t.Errorf("error should have grpc status but received %v", err)
+		}
+	}
+
+	stats, ok := metrics.Snapshot()[method]
+	if !ok {
+		t.Fatalf("metrics for %s should be collected", method)
+	}
+
+	if stats.Count != 3 {
+		t.Errorf("count should be %d but received %d", 3, stats.Count)
+	}
+
+	for _, code := range []codes.Code{codes.OK, codes.NotFound, codes.Internal} {
+		if stats.Codes[code.String()] != 1 {
+			t.Errorf("count of code %s should be %d but received %d", code, 1, stats.Codes[code.String()])
+		}
+	}
+}
+
+func TestLoggerUser(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	core, logs := observer.New(zap.InfoLevel)
+	logging := interceptor.NewLogger(&logger.AppLogger{LZ: zap.New(core).Sugar()})
+	authorization := auth.TurnOn(nil, []byte("SECRET_KEY"))
+	authorization.Methods(auth.Required, method)
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		user string
+	}{
+		{
+			name: "авторизованный пользователь",
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs("Authorization", authorization.CreateSigning(7))),
+			user: "7",
+		},
+		{name: "без токена", ctx: context.Background()},
+	}
+
+	for _, item := range tests {
+		// logging -> auth -> handler
+		_, _ = logging.UnaryInterceptor(item.ctx, nil, info,
+			func(ctx context.Context, req any) (any, error) {
+				return authorization.UnaryInterceptor(ctx, req, info,
+					func(ctx context.Context, req any) (any, error) { return nil, nil })
+			})
+
+		entries := logs.TakeAll()
+		if len(entries) != 1 {
+			t.Fatalf("%s: number of log entries should be 1 but received %d", item.name, len(entries))
+		}
+
+		if user, _ := entries[0].ContextMap()["user"].(string); user != item.user {
+			t.Errorf("%s: user should be %q but received %q", item.name, item.user, user)
+		}
+	}
+}
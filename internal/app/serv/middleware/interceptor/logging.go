@@ -0,0 +1,96 @@
+// Package interceptor implements common interceptors of the grpc server:
+// request logging, panic recovery and collecting per-method metrics.
+//
+// The recovery interceptor should be placed after the logging and metrics
+// interceptors in the chain, so that they receive the status of the request
+// that ended in a panic.
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logger logs grpc requests through the application logger.
+type Logger struct {
+	log *logger.AppLogger
+}
+
+// NewLogger returns a pointer of Logger.
+func NewLogger(log *logger.AppLogger) *Logger {
+	return &Logger{log: log}
+}
+
+// UnaryInterceptor is a grpc interceptor that logs the unary request.
+//
+// The user defined by the authorization interceptor placed later
+// in the chain is logged through the holder of the user ID.
+func (l *Logger) UnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+	ctx = auth.WithHolder(ctx)
+	res, err := handler(ctx, req)
+	l.write(ctx, info.FullMethod, start, err)
+
+	return res, err
+}
+
+// StreamInterceptor is a grpc interceptor that logs the stream
+// after it is closed.
+func (l *Logger) StreamInterceptor(srv any, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	ctx := auth.WithHolder(stream.Context())
+	err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	l.write(ctx, info.FullMethod, start, err)
+
+	return err
+}
+
+// serverStream wraps grpc.ServerStream to replace its context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream with the holder of the user ID.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// write writes the log entry, server errors are logged
+// with the error level, client errors with the warn level.
+func (l *Logger) write(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []any{
+		"method", method,
+		"code", code.String(),
+		"duration", time.Since(start),
+	}
+
+	if addr, ok := realip.FromPeer(ctx); ok {
+		fields = append(fields, "ip", addr.String())
+	}
+
+	if userID, ok := auth.UserFromContext(ctx); ok {
+		fields = append(fields, "user", userID)
+	}
+
+	switch code {
+	case codes.OK:
+		l.log.LZ.Infow("grpc request", fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		l.log.LZ.Errorw("grpc request", append(fields, "error", err.Error())...)
+	default:
+		l.log.LZ.Warnw("grpc request", append(fields, "error", err.Error())...)
+	}
+}
@@ -0,0 +1,102 @@
+package interceptor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics collects the number of requests by status codes
+// and the latency of each grpc method.
+type Metrics struct {
+	methods map[string]*MethodStats
+	mu      sync.Mutex
+}
+
+// MethodStats represents the metrics of the grpc method.
+type MethodStats struct {
+	Codes        map[string]uint64 `json:"codes"`
+	Count        uint64            `json:"count"`
+	TotalLatency time.Duration     `json:"total_latency_ns"`
+	MaxLatency   time.Duration     `json:"max_latency_ns"`
+}
+
+// NewMetrics returns a pointer of Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{methods: make(map[string]*MethodStats)}
+}
+
+// UnaryInterceptor is a grpc interceptor that counts the request.
+func (m *Metrics) UnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	m.observe(info.FullMethod, time.Since(start), err)
+
+	return res, err
+}
+
+// StreamInterceptor is a grpc interceptor that counts the stream
+// after it is closed.
+func (m *Metrics) StreamInterceptor(srv any, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	m.observe(info.FullMethod, time.Since(start), err)
+
+	return err
+}
+
+// Snapshot returns a copy of the collected metrics by method names.
+func (m *Metrics) Snapshot() map[string]MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make(map[string]MethodStats, len(m.methods))
+
+	for method, stats := range m.methods {
+		item := *stats
+		item.Codes = make(map[string]uint64, len(stats.Codes))
+
+		for code, count := range stats.Codes {
+			item.Codes[code] = count
+		}
+
+		out[method] = item
+	}
+
+	return out
+}
+
+// ServeHTTP implements http.Handler and writes the metrics in JSON.
+func (m *Metrics) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(writer).Encode(m.Snapshot())
+}
+
+func (m *Metrics) observe(method string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.methods[method]
+	if !ok {
+		stats = &MethodStats{Codes: make(map[string]uint64)}
+		m.methods[method] = stats
+	}
+
+	stats.Count++
+	stats.Codes[status.Code(err).String()]++
+	stats.TotalLatency += latency
+
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+}
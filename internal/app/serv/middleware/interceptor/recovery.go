@@ -0,0 +1,62 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+
+	"github.com/alaleks/shortener/internal/app/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInternal is returned to the client instead of the panic.
+var ErrInternal = errors.New("internal server error")
+
+// Recovery recovers the grpc server from panics in handlers.
+type Recovery struct {
+	log *logger.AppLogger
+}
+
+// NewRecovery returns a pointer of Recovery.
+func NewRecovery(log *logger.AppLogger) *Recovery {
+	return &Recovery{log: log}
+}
+
+// UnaryInterceptor is a grpc interceptor that recovers from the panic
+// and returns the error with code Internal.
+func (r *Recovery) UnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (res any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = r.recovered(info.FullMethod, rec)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor is a grpc interceptor that recovers from the panic
+// and returns the error with code Internal.
+func (r *Recovery) StreamInterceptor(srv any, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = r.recovered(info.FullMethod, rec)
+		}
+	}()
+
+	return handler(srv, stream)
+}
+
+func (r *Recovery) recovered(method string, rec any) error {
+	r.log.LZ.Errorw("grpc panic recovered",
+		"method", method,
+		"panic", rec,
+		"stack", string(debug.Stack()))
+
+	return status.Error(codes.Internal, ErrInternal.Error())
+}
@@ -0,0 +1,441 @@
+// Package ratelimit implements limiting the rate of requests
+// for the web server and the grpc server.
+//
+// The limits are implemented by the token bucket algorithm and are counted
+// separately for each client. The client is identified by the client IP address
+// resolved by the realip package and, if the authorization token is valid,
+// also by the user ID from the token. The request is allowed only if both
+// the limit of the IP address and the limit of the user are not exceeded,
+// so new tokens don't bypass the limit. The IP address has its own larger
+// limit, since it's shared by all users behind it.
+//
+// The JSON-RPC requests are limited by the called method,
+// each call of the JSON-RPC batch takes a token of the batch limit.
+// The password attempts of the protected short URLs are limited
+// for the IP address and the short URL instead of the user.
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrTooManyRequests is an indicator that the client has exceeded the rate limit.
+var ErrTooManyRequests = errors.New("too many requests, please try again later")
+
+const (
+	mdAuthName      = "Authorization"
+	retryAfterName  = "Retry-After"
+	prefixUserKey   = "user:"
+	prefixIPKey     = "ip:"
+	prefixLinkKey   = "link:"
+	prefixAPI       = "/api/"
+	pathPing        = "/ping"
+	pathShortenAPI  = "/api/shorten"
+	pathShortenRoot = "/"
+	pathBatch       = "/api/shorten/batch"
+	pathImport      = "/api/user/urls/import"
+	pathRPC         = "/rpc"
+	// maxRPCBodySize is the maximum size of the JSON-RPC request body
+	// read to classify the request, the larger bodies are rejected
+	// by the JSON-RPC handler.
+	maxRPCBodySize = 1 << 20
+)
+
+// rpcMethods are the groups of the limited JSON-RPC methods.
+var rpcMethods = map[string]Group{
+	"Shortener.Shorten": Shorten,
+	"Shortener.Batch":   Batch,
+}
+
+// Group represents a group of routes with a common rate limit.
+type Group int
+
+// List of route groups.
+const (
+	None Group = iota
+	Shorten
+	Batch
+	Redirect
+	Unlock
+)
+
+// Authenticator is used to identify the user by the authorization data.
+type Authenticator interface {
+	Identify(req *http.Request) (uint, error)
+	ReadSigning(token string) (uint, error)
+}
+
+// Limiter represents an instance of the rate limiter.
+type Limiter struct {
+	auth    Authenticator
+	groups  map[Group]*buckets
+	methods map[string]Group
+	routes  map[string]Group
+	now     func() time.Time
+}
+
+// buckets stores token buckets of clients for one group of routes.
+type buckets struct {
+	items     map[string]*bucket
+	lastSweep time.Time
+	// limit is the limit of the user, ipLimit is the limit of the IP address.
+	limit   config.RateLimit
+	ipLimit config.RateLimit
+	mu      sync.Mutex
+}
+
+// bucket represents the token bucket of a specific client.
+type bucket struct {
+	updatedAt time.Time
+	tokens    float64
+}
+
+// New returns a pointer of the rate limiter with the limits passed.
+//
+// Disabled limits are not checked.
+func New(limits config.RateLimits, auth Authenticator) *Limiter {
+	limiter := Limiter{
+		auth:    auth,
+		groups:  make(map[Group]*buckets),
+		methods: make(map[string]Group),
+		routes:  make(map[string]Group),
+		now:     time.Now,
+	}
+
+	for group, limit := range map[Group][2]config.RateLimit{
+		Shorten:  {limits.Shorten, limits.ShortenIP},
+		Batch:    {limits.Batch, limits.BatchIP},
+		Redirect: {limits.Redirect, limits.RedirectIP},
+		Unlock:   {limits.Unlock, limits.UnlockIP},
+	} {
+		if limit[0].Enabled() || limit[1].Enabled() {
+			limiter.groups[group] = &buckets{
+				items:   make(map[string]*bucket),
+				limit:   limit[0],
+				ipLimit: limit[1],
+			}
+		}
+	}
+
+	return &limiter
+}
+
+// Methods assigns grpc methods to the group of routes.
+//
+// Methods must be passed by their full name, e.g.
+// "/github.com.alaleks.shortener.Shortener/ShortenURL".
+func (l *Limiter) Methods(group Group, fullMethods ...string) {
+	for _, method := range fullMethods {
+		l.methods[method] = group
+	}
+}
+
+// Routes assigns http routes to the group of routes
+// in addition to the built-in routes of the web server.
+//
+// Routes are matched by the method and the exact path.
+func (l *Limiter) Routes(group Group, method string, paths ...string) {
+	for _, path := range paths {
+		l.routes[method+" "+path] = group
+	}
+}
+
+// Limit is a middleware that checks the rate limit of the request.
+//
+// If the limit is exceeded, the response code 429 is returned with
+// the Retry-After header. The middleware must be placed before
+// the authorization middleware, since authorization creates a new user
+// for each request without a cookie.
+func (l *Limiter) Limit(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		group, cost := None, 1
+
+		if route, ok := l.routes[req.Method+" "+req.URL.Path]; ok {
+			group = route
+		} else if req.Method == http.MethodPost && req.URL.Path == pathRPC {
+			group, cost = classifyRPC(req)
+		} else {
+			group = classify(req)
+		}
+
+		keys := l.keysHTTP(req)
+
+		// the password attempts are limited for the IP address and the short URL.
+		if group == Unlock {
+			keys = append(keys[:1], prefixLinkKey+req.Host+req.URL.Path)
+		}
+
+		if ok, retryAfter := l.allow(group, cost, keys...); !ok {
+			writer.Header().Set(retryAfterName, formatRetryAfter(retryAfter))
+			http.Error(writer, ErrTooManyRequests.Error(), http.StatusTooManyRequests)
+
+			return
+		}
+
+		handler.ServeHTTP(writer, req)
+	})
+}
+
+// UnaryInterceptor is a grpc interceptor that checks the rate limit of the request.
+//
+// If the limit is exceeded, the error with code ResourceExhausted is returned
+// and the retry-after header is sent.
+func (l *Limiter) UnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	if err := l.checkGRPC(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor is a grpc interceptor that checks the rate limit
+// when the stream is opened.
+func (l *Limiter) StreamInterceptor(srv any, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	if err := l.checkGRPC(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
+
+func (l *Limiter) checkGRPC(ctx context.Context, fullMethod string) error {
+	group, ok := l.methods[fullMethod]
+	if !ok {
+		return nil
+	}
+
+	if ok, retryAfter := l.allow(group, 1, l.keysGRPC(ctx)...); !ok {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterName, formatRetryAfter(retryAfter)))
+
+		return status.Error(codes.ResourceExhausted, ErrTooManyRequests.Error())
+	}
+
+	return nil
+}
+
+// allow checks the rate limits for the client keys in the group and returns
+// false and the time after which the request can be repeated
+// if any limit is exceeded. The request takes cost tokens.
+func (l *Limiter) allow(group Group, cost int, keys ...string) (bool, time.Duration) {
+	bkts, ok := l.groups[group]
+	if !ok {
+		return true, 0
+	}
+
+	return bkts.take(l.now(), float64(cost), keys...)
+}
+
+// take takes cost tokens from the buckets of all client keys,
+// no token is taken if any bucket doesn't have enough tokens.
+// The keys whose limits are disabled are skipped.
+func (b *buckets) take(now time.Time, cost float64, keys ...string) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sweep(now)
+
+	var retryAfter time.Duration
+
+	items := make([]*bucket, 0, len(keys))
+
+	for _, key := range keys {
+		limit := b.limitOf(key)
+		if !limit.Enabled() {
+			continue
+		}
+
+		// tokens per second.
+		rate := float64(limit.Requests) / limit.Period.Seconds()
+		capacity := float64(limit.Requests)
+
+		item, ok := b.items[key]
+		if !ok {
+			item = &bucket{tokens: capacity, updatedAt: now}
+			b.items[key] = item
+		}
+
+		item.tokens = math.Min(capacity, item.tokens+now.Sub(item.updatedAt).Seconds()*rate)
+		item.updatedAt = now
+
+		if wait := time.Duration((cost - item.tokens) / rate * float64(time.Second)); item.tokens < cost && wait > retryAfter {
+			retryAfter = wait
+		}
+
+		items = append(items, item)
+	}
+
+	if retryAfter > 0 {
+		return false, retryAfter
+	}
+
+	for _, item := range items {
+		item.tokens -= cost
+	}
+
+	return true, 0
+}
+
+// limitOf returns the limit of the client key.
+func (b *buckets) limitOf(key string) config.RateLimit {
+	if strings.HasPrefix(key, prefixIPKey) {
+		return b.ipLimit
+	}
+
+	return b.limit
+}
+
+// sweep removes buckets that have been completely refilled,
+// since they are equal to new ones.
+func (b *buckets) sweep(now time.Time) {
+	period := b.limit.Period
+	if b.ipLimit.Period > period {
+		period = b.ipLimit.Period
+	}
+
+	if now.Sub(b.lastSweep) < period {
+		return
+	}
+
+	for key, item := range b.items {
+		if now.Sub(item.updatedAt) >= b.limitOf(key).Period {
+			delete(b.items, key)
+		}
+	}
+
+	b.lastSweep = now
+}
+
+// keysHTTP returns the client keys for the http request:
+// the IP address and the user if the request is authorized.
+func (l *Limiter) keysHTTP(req *http.Request) []string {
+	keys := []string{prefixIPKey + req.RemoteAddr}
+
+	if addr, ok := realip.FromRequest(req); ok {
+		keys[0] = prefixIPKey + addr.String()
+	}
+
+	if l.auth != nil {
+		if userID, err := l.auth.Identify(req); err == nil {
+			keys = append(keys, prefixUserKey+strconv.Itoa(int(userID)))
+		}
+	}
+
+	return keys
+}
+
+// keysGRPC returns the client keys for the grpc request:
+// the IP address and the user if the request is authorized.
+func (l *Limiter) keysGRPC(ctx context.Context) []string {
+	keys := []string{prefixIPKey}
+
+	if addr, ok := realip.FromPeer(ctx); ok {
+		keys[0] = prefixIPKey + addr.String()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok && l.auth != nil {
+		if values := md.Get(mdAuthName); len(values) > 0 {
+			if userID, err := l.auth.ReadSigning(values[0]); err == nil {
+				keys = append(keys, prefixUserKey+strconv.Itoa(int(userID)))
+			}
+		}
+	}
+
+	return keys
+}
+
+// classify returns the group of routes for the http request.
+func classify(req *http.Request) Group {
+	path := req.URL.Path
+
+	switch req.Method {
+	case http.MethodPost:
+		switch path {
+		case pathShortenRoot, pathShortenAPI:
+			return Shorten
+		case pathBatch, pathImport:
+			return Batch
+		}
+
+		// POST /{uid}, the password attempts.
+		if isShortURLPath(path) {
+			return Unlock
+		}
+	case http.MethodGet:
+		// GET /{uid}
+		if isShortURLPath(path) && path != pathPing {
+			return Redirect
+		}
+	}
+
+	return None
+}
+
+// classifyRPC returns the group of routes for the JSON-RPC request
+// by the called method and the number of tokens taken by the request.
+//
+// Each call of the batch takes a token of the batch limit, so the batch
+// of more calls than the limit is always rejected. The request which
+// can't be parsed takes a token of the batch limit too. The body
+// is restored for the JSON-RPC handler.
+func classifyRPC(req *http.Request) (Group, int) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRPCBodySize+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+
+	if err != nil || len(body) > maxRPCBodySize {
+		return Batch, 1
+	}
+
+	type call struct {
+		Method string `json:"method"`
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var calls []call
+		if err := json.Unmarshal(trimmed, &calls); err != nil || len(calls) == 0 {
+			return Batch, 1
+		}
+
+		return Batch, len(calls)
+	}
+
+	var single call
+	if err := json.Unmarshal(body, &single); err != nil {
+		return Batch, 1
+	}
+
+	return rpcMethods[single.Method], 1
+}
+
+// isShortURLPath returns true if the path can be the short URL.
+func isShortURLPath(path string) bool {
+	return strings.Count(path, "/") == 1 && path != pathShortenRoot && !strings.HasPrefix(path+"/", prefixAPI)
+}
+
+// formatRetryAfter returns the value for the Retry-After header in seconds.
+func formatRetryAfter(retryAfter time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+}
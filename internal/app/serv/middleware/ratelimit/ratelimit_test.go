@@ -0,0 +1,279 @@
+package ratelimit_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const methodShorten = "/github.com.alaleks.shortener.Shortener/ShortenURL"
+
+func TestLimit(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	auth := auth.TurnOn(nil, []byte("SECRET_KEY"))
+	limits := config.RateLimits{
+		Shorten:   config.RateLimit{Requests: 2, Period: time.Hour},
+		Batch:     config.RateLimit{Requests: 1, Period: time.Hour},
+		Redirect:  config.RateLimit{},
+		ShortenIP: config.RateLimit{Requests: 3, Period: time.Hour},
+		BatchIP:   config.RateLimit{Requests: 1, Period: time.Hour},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		addr   string
+		cookie string
+		codes  []int
+	}{
+		{
+			name: "сокращение: превышение лимита ip", method: http.MethodPost, path: "/",
+			codes: []int{200, 200, 200, 429},
+		},
+		{
+			name: "сокращение через api и корень учитываются вместе", method: http.MethodPost, path: "/api/shorten",
+			codes: []int{429},
+		},
+		{
+			name: "пакетное сокращение: превышение лимита", method: http.MethodPost, path: "/api/shorten/batch",
+			codes: []int{200, 429},
+		},
+		{
+			name: "импорт учитывается в пакетном лимите", method: http.MethodPost, path: "/api/user/urls/import",
+			codes: []int{429},
+		},
+		{
+			name: "редирект: лимит отключен", method: http.MethodGet, path: "/abcde",
+			codes: []int{200, 200, 200, 200},
+		},
+		{
+			name: "ping не ограничивается", method: http.MethodGet, path: "/ping",
+			codes: []int{200, 200, 200, 200},
+		},
+		{
+			name: "лимит считается отдельно для пользователя", method: http.MethodPost, path: "/", addr: "192.0.2.20:1234",
+			cookie: auth.CreateSigning(1), codes: []int{200, 200, 429},
+		},
+		{
+			name: "пользователи за одним ip не делят лимит пользователя", method: http.MethodPost, path: "/",
+			addr: "192.0.2.20:1234", cookie: auth.CreateSigning(2), codes: []int{200},
+		},
+		{
+			name: "новый пользователь не обходит лимит ip", method: http.MethodPost, path: "/", addr: "192.0.2.20:1234",
+			cookie: auth.CreateSigning(3), codes: []int{429},
+		},
+		{
+			name: "лимит пользователя с другого ip", method: http.MethodPost, path: "/", addr: "192.0.2.30:1234",
+			cookie: auth.CreateSigning(1), codes: []int{429},
+		},
+	}
+
+	limiter := ratelimit.New(limits, &auth)
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// тестируем последовательно, т.к. лимиты общие
+	for _, item := range tests {
+		for i, code := range item.codes {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(item.method, item.path, nil)
+			req.RemoteAddr = "192.0.2.10:1234"
+
+			if item.addr != "" {
+				req.RemoteAddr = item.addr
+			}
+
+			if item.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "Authorization", Value: item.cookie})
+			}
+
+			handler.ServeHTTP(w, req)
+			res := w.Result()
+			res.Body.Close()
+
+			if res.StatusCode != code {
+				t.Errorf("%s: request #%d status code should be %d but received %d",
+					item.name, i+1, code, res.StatusCode)
+			}
+
+			if res.StatusCode == http.StatusTooManyRequests && res.Header.Get("Retry-After") == "" {
+				t.Errorf("%s: header Retry-After should be set", item.name)
+			}
+		}
+	}
+}
+
+func TestLimitRPC(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	limiter := ratelimit.New(config.RateLimits{
+		Shorten:   config.RateLimit{Requests: 1, Period: time.Hour},
+		Batch:     config.RateLimit{Requests: 3, Period: time.Hour},
+		ShortenIP: config.RateLimit{Requests: 1, Period: time.Hour},
+		BatchIP:   config.RateLimit{Requests: 3, Period: time.Hour},
+	}, nil)
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// тело запроса доступно обработчику JSON-RPC
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+
+	var (
+		stat  = `{"jsonrpc":"2.0","method":"Shortener.Stat","params":{"shortuid":"abcde"},"id":1}`
+		batch = `{"jsonrpc":"2.0","method":"Shortener.Batch","params":{"urls":[]},"id":1}`
+	)
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{name: "метод без лимита", body: stat, code: http.StatusOK},
+		{name: "метод без лимита повторно", body: stat, code: http.StatusOK},
+		{
+			name: "сокращение", code: http.StatusOK,
+			body: `{"jsonrpc":"2.0","method":"Shortener.Shorten","params":{"url":"https://github.com"},"id":1}`,
+		},
+		{
+			name: "сокращение: превышение лимита", code: http.StatusTooManyRequests,
+			body: `{"jsonrpc":"2.0","method":"Shortener.Shorten","params":{"url":"https://github.com"},"id":1}`,
+		},
+		{name: "каждый вызов пакета учитывается", body: "[" + stat + "," + stat + "]", code: http.StatusOK},
+		{name: "пакет больше остатка лимита", body: "[" + stat + "," + stat + "]", code: http.StatusTooManyRequests},
+		{name: "пакетное сокращение", body: batch, code: http.StatusOK},
+		{name: "пакетное сокращение: превышение лимита", body: batch, code: http.StatusTooManyRequests},
+	}
+
+	// тестируем последовательно, т.к. лимиты общие
+	for _, item := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(item.body))
+		req.RemoteAddr = "192.0.2.10:1234"
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != item.code {
+			t.Errorf("%s: status code should be %d but received %d", item.name, item.code, w.Code)
+		}
+
+		if w.Code == http.StatusOK && w.Body.String() != item.body {
+			t.Errorf("%s: body should be %s but received %s", item.name, item.body, w.Body)
+		}
+	}
+}
+
+func TestLimitUnlock(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	limiter := ratelimit.New(config.RateLimits{
+		Unlock:   config.RateLimit{Requests: 2, Period: time.Hour},
+		UnlockIP: config.RateLimit{Requests: 3, Period: time.Hour},
+	}, nil)
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name   string
+		method string
+		addr   string
+		path   string
+		code   int
+	}{
+		{name: "первая попытка", addr: "192.0.2.10:1234", path: "/abcde", code: http.StatusOK},
+		{name: "вторая попытка", addr: "192.0.2.10:1234", path: "/abcde", code: http.StatusOK},
+		{name: "превышение лимита ссылки", addr: "192.0.2.10:1234", path: "/abcde", code: http.StatusTooManyRequests},
+		{name: "лимит ссылки с другого ip", addr: "192.0.2.20:1234", path: "/abcde", code: http.StatusTooManyRequests},
+		{name: "другая ссылка", addr: "192.0.2.10:1234", path: "/fghij", code: http.StatusOK},
+		{name: "превышение лимита ip", addr: "192.0.2.10:1234", path: "/klmno", code: http.StatusTooManyRequests},
+		{name: "переход не ограничивается", method: http.MethodGet, addr: "192.0.2.10:1234", path: "/abcde", code: http.StatusOK},
+	}
+
+	// тестируем последовательно, т.к. лимиты общие
+	for _, item := range tests {
+		method := http.MethodPost
+		if item.method != "" {
+			method = item.method
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, item.path, strings.NewReader("password=secret"))
+		req.RemoteAddr = item.addr
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != item.code {
+			t.Errorf("%s: status code should be %d but received %d", item.name, item.code, w.Code)
+		}
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	auth := auth.TurnOn(nil, []byte("SECRET_KEY"))
+	limiter := ratelimit.New(config.RateLimits{
+		Shorten:   config.RateLimit{Requests: 1, Period: time.Hour},
+		ShortenIP: config.RateLimit{Requests: 2, Period: time.Hour},
+	}, &auth)
+	limiter.Methods(ratelimit.Shorten, methodShorten)
+
+	ctxIP := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 1234},
+	})
+	ctxUserIP := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.20"), Port: 1234},
+	})
+	ctxUser := metadata.NewIncomingContext(ctxUserIP,
+		metadata.Pairs("Authorization", auth.CreateSigning(1)))
+	ctxOtherUser := metadata.NewIncomingContext(ctxUserIP,
+		metadata.Pairs("Authorization", auth.CreateSigning(2)))
+	ctxNewUser := metadata.NewIncomingContext(ctxUserIP,
+		metadata.Pairs("Authorization", auth.CreateSigning(3)))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{name: "первый запрос с ip", ctx: ctxIP, method: methodShorten, code: codes.OK},
+		{name: "второй запрос с ip", ctx: ctxIP, method: methodShorten, code: codes.OK},
+		{name: "третий запрос с ip", ctx: ctxIP, method: methodShorten, code: codes.ResourceExhausted},
+		{name: "первый запрос пользователя", ctx: ctxUser, method: methodShorten, code: codes.OK},
+		{name: "повторный запрос пользователя", ctx: ctxUser, method: methodShorten, code: codes.ResourceExhausted},
+		{name: "другой пользователь с того же ip", ctx: ctxOtherUser, method: methodShorten, code: codes.OK},
+		{name: "новый пользователь с того же ip", ctx: ctxNewUser, method: methodShorten, code: codes.ResourceExhausted},
+		{name: "метод без лимита", ctx: ctxIP, method: "/unknown", code: codes.OK},
+	}
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return req, nil
+	}
+
+	for _, item := range tests {
+		_, err := limiter.UnaryInterceptor(item.ctx, nil,
+			&grpc.UnaryServerInfo{FullMethod: item.method}, handler)
+
+		if code := status.Code(err); code != item.code {
+			t.Errorf("%s: code should be %s but received %s", item.name, item.code, code)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Package realip implements resolving the real client IP address
+// for the web server and the grpc server.
+//
+// The client IP address is taken from the address of the direct peer.
+// The X-Forwarded-For, Forwarded and X-Real-IP headers are honored only
+// if the direct peer is a trusted proxy, otherwise they can be forged
+// by any client.
+package realip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	headerForwarded     = "Forwarded"
+	headerForwardedFor  = "X-Forwarded-For"
+	headerRealIP        = "X-Real-IP"
+	forwardedForParam   = "for"
+	separatorSubnets    = ","
+	separatorForwarded  = ","
+	separatorParameters = ";"
+)
+
+// ctxKey is the key for the client IP address in the context.
+type ctxKey struct{}
+
+// Subnets represents a list of networks (IPv4 and IPv6).
+type Subnets []netip.Prefix
+
+// ParseSubnets parses comma-separated networks in CIDR notation,
+// e.g. "172.17.0.0/24,fd00::/8". Invalid networks are skipped.
+func ParseSubnets(value string) Subnets {
+	var subnets Subnets
+
+	for _, item := range strings.Split(value, separatorSubnets) {
+		network, err := netip.ParsePrefix(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+
+		subnets = append(subnets, network.Masked())
+	}
+
+	return subnets
+}
+
+// Contains returns true if the IP address belongs to one of the networks.
+func (s Subnets) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	for _, network := range s {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolver resolves the real client IP address.
+type Resolver struct {
+	trustedProxies Subnets
+}
+
+// New returns a pointer of Resolver that honors the forwarding headers
+// only from trusted proxies.
+func New(trustedProxies Subnets) *Resolver {
+	return &Resolver{trustedProxies: trustedProxies}
+}
+
+// NewContext returns a copy of the context with the client IP address.
+func NewContext(ctx context.Context, addr netip.Addr) context.Context {
+	return context.WithValue(ctx, ctxKey{}, addr)
+}
+
+// FromContext returns the client IP address stored in the context.
+func FromContext(ctx context.Context) (netip.Addr, bool) {
+	addr, ok := ctx.Value(ctxKey{}).(netip.Addr)
+
+	return addr, ok
+}
+
+// FromRequest returns the client IP address resolved by the middleware,
+// and if the middleware is not used, the address of the direct peer.
+func FromRequest(req *http.Request) (netip.Addr, bool) {
+	if addr, ok := FromContext(req.Context()); ok {
+		return addr, true
+	}
+
+	return parseAddr(req.RemoteAddr)
+}
+
+// FromPeer returns the client IP address resolved by the interceptor,
+// and if the interceptor is not used, the address of the direct peer.
+func FromPeer(ctx context.Context) (netip.Addr, bool) {
+	if addr, ok := FromContext(ctx); ok {
+		return addr, true
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return parseAddr(p.Addr.String())
+	}
+
+	return netip.Addr{}, false
+}
+
+// RealIP is a middleware that resolves the client IP address
+// and stores it in the request context.
+func (r *Resolver) RealIP(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if addr, ok := r.Resolve(req.RemoteAddr, req.Header); ok {
+			req = req.WithContext(NewContext(req.Context(), addr))
+		}
+
+		handler.ServeHTTP(writer, req)
+	})
+}
+
+// UnaryInterceptor is a grpc interceptor that resolves the client IP address
+// and stores it in the context.
+func (r *Resolver) UnaryInterceptor(ctx context.Context, req any,
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	return handler(r.resolveGRPC(ctx), req)
+}
+
+// StreamInterceptor is a grpc interceptor that resolves the client IP address
+// and stores it in the stream context.
+func (r *Resolver) StreamInterceptor(srv any, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	return handler(srv, &serverStream{
+		ServerStream: stream,
+		ctx:          r.resolveGRPC(stream.Context()),
+	})
+}
+
+// Resolve returns the client IP address by the address of the direct peer
+// and the request headers.
+//
+// The headers are checked in the order Forwarded, X-Forwarded-For, X-Real-IP.
+// In the lists of proxies the rightmost address that is not a trusted proxy
+// is used, since the addresses to the left of it can be forged by the client.
+func (r *Resolver) Resolve(remoteAddr string, header http.Header) (netip.Addr, bool) {
+	remote, ok := parseAddr(remoteAddr)
+	if !ok {
+		return remote, false
+	}
+
+	if !r.trustedProxies.Contains(remote) {
+		return remote, true
+	}
+
+	if addr, ok := r.rightmostUntrusted(parseForwarded(header.Values(headerForwarded))); ok {
+		return addr, true
+	}
+
+	if addr, ok := r.rightmostUntrusted(parseForwardedFor(header.Values(headerForwardedFor))); ok {
+		return addr, true
+	}
+
+	if addr, ok := parseAddr(strings.TrimSpace(header.Get(headerRealIP))); ok {
+		return addr, true
+	}
+
+	return remote, true
+}
+
+func (r *Resolver) resolveGRPC(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ctx
+	}
+
+	header := make(http.Header)
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, name := range [...]string{headerForwarded, headerForwardedFor, headerRealIP} {
+			for _, value := range md.Get(name) {
+				header.Add(name, value)
+			}
+		}
+	}
+
+	if addr, ok := r.Resolve(p.Addr.String(), header); ok {
+		return NewContext(ctx, addr)
+	}
+
+	return ctx
+}
+
+// rightmostUntrusted returns the rightmost address that is not a trusted proxy.
+// If all addresses are trusted, the leftmost address is returned.
+func (r *Resolver) rightmostUntrusted(addrs []netip.Addr) (netip.Addr, bool) {
+	if len(addrs) == 0 {
+		return netip.Addr{}, false
+	}
+
+	for i := len(addrs) - 1; i >= 0; i-- {
+		if !r.trustedProxies.Contains(addrs[i]) {
+			return addrs[i], true
+		}
+	}
+
+	return addrs[0], true
+}
+
+// parseForwardedFor parses the values of the X-Forwarded-For header,
+// e.g. "203.0.113.195, 70.41.3.18".
+func parseForwardedFor(values []string) []netip.Addr {
+	var addrs []netip.Addr
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, separatorForwarded) {
+			addr, ok := parseAddr(strings.TrimSpace(item))
+			if !ok {
+				// the chain is broken, the addresses to the left cannot be trusted.
+				addrs = addrs[:0]
+
+				continue
+			}
+
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs
+}
+
+// parseForwarded parses the values of the Forwarded header (RFC 7239),
+// e.g. `for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"`.
+func parseForwarded(values []string) []netip.Addr {
+	var addrs []netip.Addr
+
+	for _, value := range values {
+		for _, element := range strings.Split(value, separatorForwarded) {
+			for _, pair := range strings.Split(element, separatorParameters) {
+				key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok || !strings.EqualFold(key, forwardedForParam) {
+					continue
+				}
+
+				addr, ok := parseAddr(strings.Trim(val, `"`))
+				if !ok {
+					addrs = addrs[:0]
+
+					continue
+				}
+
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	return addrs
+}
+
+// parseAddr parses the IP address with or without port.
+func parseAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+
+	addr, err := netip.ParseAddr(strings.Trim(value, "[]"))
+	if err != nil {
+		return addr, false
+	}
+
+	return addr.Unmap(), true
+}
+
+// serverStream wraps grpc.ServerStream to replace its context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream with the client IP address.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
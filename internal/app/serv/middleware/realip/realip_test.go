@@ -0,0 +1,94 @@
+package realip_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	resolver := realip.New(realip.ParseSubnets("10.0.0.0/8, 2001:db8::/32"))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     http.Header
+		ip         string
+	}{
+		{
+			name: "прямой клиент без заголовков", remoteAddr: "203.0.113.5:4000",
+			header: http.Header{}, ip: "203.0.113.5",
+		},
+		{
+			name: "прямой клиент подделал X-Real-IP", remoteAddr: "203.0.113.5:4000",
+			header: http.Header{"X-Real-Ip": {"172.17.0.2"}}, ip: "203.0.113.5",
+		},
+		{
+			name: "доверенный прокси передал X-Real-IP", remoteAddr: "10.0.0.1:4000",
+			header: http.Header{"X-Real-Ip": {"172.17.0.2"}}, ip: "172.17.0.2",
+		},
+		{
+			name: "X-Forwarded-For: берется крайний правый недоверенный", remoteAddr: "10.0.0.1:4000",
+			header: http.Header{"X-Forwarded-For": {"172.17.0.2, 198.51.100.7, 10.0.0.2"}}, ip: "198.51.100.7",
+		},
+		{
+			name: "X-Forwarded-For приоритетнее X-Real-IP", remoteAddr: "10.0.0.1:4000",
+			header: http.Header{
+				"X-Forwarded-For": {"198.51.100.7"},
+				"X-Real-Ip":       {"172.17.0.2"},
+			}, ip: "198.51.100.7",
+		},
+		{
+			name: "Forwarded с ipv6 и портом", remoteAddr: "[2001:db8::1]:4000",
+			header: http.Header{"Forwarded": {`for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"`}},
+			ip:     "192.0.2.60",
+		},
+		{
+			name: "невалидный заголовок от прокси", remoteAddr: "10.0.0.1:4000",
+			header: http.Header{"X-Real-Ip": {"wrong"}}, ip: "10.0.0.1",
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, ok := resolver.Resolve(item.remoteAddr, item.header)
+			if !ok || addr.String() != item.ip {
+				t.Errorf("ip should be %s but received %s", item.ip, addr)
+			}
+		})
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// данные для теста
+	resolver := realip.New(realip.ParseSubnets("10.0.0.0/8"))
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4000},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("X-Real-IP", "172.17.0.2"))
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		addr, _ := realip.FromContext(ctx)
+
+		return addr.String(), nil
+	}
+
+	res, _ := resolver.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	if res != "172.17.0.2" {
+		t.Errorf("ip should be 172.17.0.2 but received %s", res)
+	}
+}
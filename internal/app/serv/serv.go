@@ -16,15 +16,23 @@ import (
 
 	"github.com/alaleks/shortener/internal/app/config"
 	"github.com/alaleks/shortener/internal/app/handlers"
+	"github.com/alaleks/shortener/internal/app/linkcheck"
 	"github.com/alaleks/shortener/internal/app/logger"
 	"github.com/alaleks/shortener/internal/app/router"
 	"github.com/alaleks/shortener/internal/app/serv/middleware"
 	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
 	"github.com/alaleks/shortener/internal/app/serv/middleware/compress"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/interceptor"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/ratelimit"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
 	"github.com/alaleks/shortener/internal/app/storage"
-	"golang.org/x/crypto/acme/autocert"
+	"github.com/alaleks/shortener/internal/app/storage/pool"
+	"github.com/alaleks/shortener/internal/app/unfurl"
+	"github.com/alaleks/shortener/internal/app/webhook"
 	"golang.org/x/net/http2"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 const (
@@ -36,26 +44,72 @@ const (
 
 // AppServer represents an application server instance.
 type AppServer struct {
-	server   *http.Server
-	grpc     *grpc.Server
-	handlers *handlers.Handlers
-	Logger   *logger.AppLogger
-	cfg      config.Configurator
+	server  *http.Server
+	grpc    *grpc.Server
+	health  *healthServer
+	checker *linkcheck.Checker
+	// checkPool runs the rounds of the checker, which send the requests
+	// to the destinations and must not block the storage pool.
+	checkPool *pool.Pool
+	webhooks  *webhook.Dispatcher
+	// webhookPool runs the rounds of the dispatcher, they are long
+	// and must not block the tasks of the requests in the storage pool.
+	webhookPool *pool.Pool
+	fetcher     *unfurl.Fetcher
+	// fetchPool runs the fetches of the metadata of the destination pages.
+	fetchPool *pool.Pool
+	handlers  *handlers.Handlers
+	Logger    *logger.AppLogger
+	Metrics   *interceptor.Metrics
+	cfg       config.Configurator
 }
 
 // New creates a new server.
 func New() *AppServer {
 	var (
-		cfg        config.Configurator = config.New(config.Options{Env: true, Flag: true})
-		logger                         = logger.NewLogger()
-		st                             = storage.InitStore(cfg, logger)
-		appHandler                     = handlers.New(cfg, logger, st)
-		auth                           = auth.TurnOn(appHandler.Storage.St, cfg.GetSecretKey())
-		routers                        = router.Create(appHandler)
+		cfg           config.Configurator = config.New(config.Options{Env: true, Flag: true})
+		logger                            = logger.NewLogger()
+		st                                = storage.InitStore(cfg, logger)
+		appHandler                        = handlers.New(cfg, logger, st)
+		authorization                     = auth.TurnOn(appHandler.Storage.St, cfg.GetSecretKey())
+		pbSrv                             = pb.New(appHandler.Service, logger)
+		limiter                           = ratelimit.New(cfg.GetRateLimits(), &authorization)
+		resolverIP                        = realip.New(realip.ParseSubnets(cfg.GetTrustedProxies()))
+		logging                           = interceptor.NewLogger(logger)
+		recovery                          = interceptor.NewRecovery(logger)
+		metrics                           = interceptor.NewMetrics()
 	)
 
+	if err := cfg.Err(); err != nil {
+		logger.LZ.Warn(err)
+	}
+
+	limiter.Methods(ratelimit.Shorten, pb.Shortener_ShortenURL_FullMethodName)
+	limiter.Methods(ratelimit.Batch, pb.Shortener_ShortenURLBatch_FullMethodName,
+		pb.Shortener_ShortenStream_FullMethodName)
+
+	authorization.Methods(auth.CreateUser, pb.Shortener_ShortenURL_FullMethodName)
+	authorization.Methods(auth.Required, pb.Shortener_GetUsersURL_FullMethodName,
+		pb.Shortener_ShortenURLBatch_FullMethodName, pb.Shortener_ShortenDelete_FullMethodName,
+		pb.Shortener_ShortenStream_FullMethodName, pb.Shortener_ExportUserURLs_FullMethodName,
+		pb.Shortener_SetRules_FullMethodName, pb.Shortener_SetVariants_FullMethodName)
+
+	// the REST/JSON gateway of the grpc service, the client IP address,
+	// the rate limits and the user are resolved by the middleware of the web server.
+	gateway, err := pb.NewGateway(context.Background(), pbSrv, cfg.GetGatewayPrefix(),
+		logging.UnaryInterceptor, metrics.UnaryInterceptor, recovery.UnaryInterceptor)
+	if err != nil {
+		logger.LZ.Fatal(err)
+	}
+
+	limiter.Routes(ratelimit.Shorten, http.MethodPost, cfg.GetGatewayPrefix()+"/shorten")
+	limiter.Routes(ratelimit.Batch, http.MethodPost, cfg.GetGatewayPrefix()+"/shorten/batch")
+
+	routers := router.Create(appHandler, router.Mount{Prefix: cfg.GetGatewayPrefix(), Handler: gateway})
+
 	server := &http.Server{
-		Handler: middleware.New(compress.Compression, compress.Decompression, auth.Authorization).
+		Handler: middleware.New(resolverIP.RealIP, limiter.Limit,
+			compress.Compression, compress.Decompression, authorization.Authorization).
 			Configure(routers),
 		ReadTimeout:       defaultTimeout,
 		WriteTimeout:      defaultTimeout,
@@ -71,23 +125,87 @@ func New() *AppServer {
 		ConnContext:       nil,
 	}
 
+	creds, err := grpcCredentials(cfg)
+	if err != nil {
+		logger.LZ.Fatal(err)
+	}
+
 	// register grpc server.
-	grpc := grpc.NewServer()
-	pbSrv := pb.New(st, logger, cfg.GetSecretKey(), cfg.GetTrustedSubnet())
+	grpc := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(resolverIP.UnaryInterceptor, logging.UnaryInterceptor,
+			metrics.UnaryInterceptor, recovery.UnaryInterceptor,
+			limiter.UnaryInterceptor, authorization.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(resolverIP.StreamInterceptor, logging.StreamInterceptor,
+			metrics.StreamInterceptor, recovery.StreamInterceptor,
+			limiter.StreamInterceptor, authorization.StreamInterceptor),
+	)
 	pb.RegisterShortenerServer(grpc, pbSrv)
 
+	health := newHealthServer(st.St.Ping)
+	healthpb.RegisterHealthServer(grpc, health)
+
+	if cfg.EnableGRPCReflection() {
+		reflection.Register(grpc)
+	}
+
+	// the metadata of the destination pages of new short URLs is fetched
+	// in the background, the errors are logged by the pool of the fetcher.
+	fetcher := unfurl.New(unfurl.Options{})
+	appHandler.Service.SetFetcher(fetcher)
+
+	// the destinations of short URLs are checked in the background.
+	var checker *linkcheck.Checker
+
+	if linkCheck := cfg.GetLinkCheck(); linkCheck.Enabled() {
+		checker = linkcheck.New(st.St, linkcheck.Options{
+			Interval:    linkCheck.Interval,
+			Concurrency: linkCheck.Concurrency,
+		})
+	}
+
+	// the events of short URLs are delivered to the webhooks of users
+	// from the outbox, the expired short URLs are swept by the rounds.
+	dispatcher := webhook.New(st.St, webhook.Options{Sweep: appHandler.Service.SweepExpired})
+	appHandler.Service.SetDispatcher(dispatcher)
+
 	return &AppServer{
-		server:   server,
-		handlers: appHandler,
-		cfg:      cfg,
-		grpc:     grpc,
-		Logger:   logger,
+		server:      server,
+		checker:     checker,
+		checkPool:   pool.Init(logger),
+		webhooks:    dispatcher,
+		webhookPool: pool.Init(logger),
+		fetcher:     fetcher,
+		fetchPool:   pool.Init(logger),
+		handlers:    appHandler,
+		cfg:         cfg,
+		grpc:        grpc,
+		health:      health,
+		Logger:      logger,
+		Metrics:     metrics,
 	}
 }
 
 // Run starts the server.
+//
+// The background tasks are stopped by the signal handler
+// before the server is shut down.
 func Run(appServer *AppServer) error {
-	go catchSignal(appServer)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go catchSignal(appServer, cancel)
+	go appServer.health.watch(ctx)
+
+	if appServer.checker != nil {
+		go appServer.checkPool.Run()
+		go appServer.checker.Run(ctx, appServer.checkPool)
+	}
+
+	go appServer.webhookPool.Run()
+	go appServer.webhooks.Run(ctx, appServer.webhookPool)
+
+	go appServer.fetchPool.Run()
+	go appServer.fetcher.Run(ctx, appServer.handlers.Storage.St, appServer.fetchPool)
 
 	// run grpc server
 	go func() {
@@ -115,11 +233,7 @@ func Run(appServer *AppServer) error {
 }
 
 func (a *AppServer) turnOnTLS() {
-	certManager := autocert.Manager{
-		Prompt:     autocert.AcceptTOS,
-		Cache:      autocert.DirCache("cert"),
-		HostPolicy: autocert.HostWhitelist(a.server.Addr),
-	}
+	certManager := newCertManager(a.server.Addr)
 
 	a.server.TLSConfig = &tls.Config{
 		GetCertificate:           certManager.GetCertificate,
@@ -139,8 +253,9 @@ func (a *AppServer) turnOnTLS() {
 	_ = http2.ConfigureServer(a.server, &http2.Server{})
 }
 
-// catchSignal will catch SIGINT, SIGHUP, SIGQUIT and SIGTERM and close the server.
-func catchSignal(appServer *AppServer) {
+// catchSignal will catch SIGINT, SIGHUP, SIGQUIT and SIGTERM, stop
+// the background tasks by the cancel function and close the server.
+func catchSignal(appServer *AppServer, cancel context.CancelFunc) {
 	termSignals := make(chan os.Signal, 1)
 	reloadSignals := make(chan os.Signal, 1)
 
@@ -155,7 +270,12 @@ func catchSignal(appServer *AppServer) {
 	for {
 		select {
 		case <-termSignals:
+			cancel()
 			appServer.handlers.Storage.Pool.Stop()
+			appServer.webhookPool.Stop()
+			appServer.checkPool.Stop()
+			appServer.fetchPool.Stop()
+			appServer.health.Shutdown()
 			appServer.grpc.GracefulStop()
 
 			if err := appServer.handlers.Storage.St.Close(); err != nil {
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"github.com/alaleks/shortener/internal/app/storage/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Click counts the redirect by the short URL and to its variant
+// (negative if the variant isn't served) in default storage
+// and returns the number of clicks of the short URL after it.
+//
+// The limit of clicks is checked under the lock, ErrShortURLExhausted
+// is returned if the limit has been reached.
+func (ds *DefaultStorage) Click(uid string, variant int) (uint, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	element, check := ds.urls[uid]
+	if !check {
+		return 0, ErrUIDNotValid
+	}
+
+	if exhausted(element.Statistics, element.MaxClicks) {
+		return 0, ErrShortURLExhausted
+	}
+
+	element.Statistics++
+
+	if variant >= 0 && variant < len(element.Variants) {
+		element.Variants[variant].Clicks++
+	}
+
+	return element.Statistics, nil
+}
+
+// Click counts the redirect by the short URL and to its variant
+// (negative if the variant isn't served) in DB and returns the number
+// of clicks of the short URL after it.
+//
+// The limit of clicks is checked by the condition of the update,
+// so concurrent redirects never exceed it. ErrShortURLExhausted
+// is returned if the limit has been reached.
+func (d *DB) Click(uid string, variant int) (uint, error) {
+	var url models.Urls
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&url).Clauses(clause.Returning{Columns: []clause.Column{{Name: "statistics"}}}).
+			Where("short_uid = ? AND (max_clicks = 0 OR statistics < max_clicks)", uid).
+			UpdateColumn("statistics", gorm.Expr("statistics + ?", 1))
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrShortURLExhausted
+		}
+
+		if variant < 0 {
+			return nil
+		}
+
+		return tx.Model(&models.Variants{}).Where("short_uid = ? AND position = ?", uid, variant).
+			UpdateColumn("clicks", gorm.Expr("clicks + ?", 1)).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return url.Statistics, nil
+}
+
+// exhausted returns true if the limit of clicks has been reached,
+// zero limit means no limit.
+func exhausted(clicks, maxClicks uint) bool {
+	return maxClicks > 0 && clicks >= maxClicks
+}
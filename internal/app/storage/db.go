@@ -2,6 +2,7 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -17,10 +18,13 @@ import (
 )
 
 const (
-	maxIdleConns           = 100
-	maxOpenConns           = 200
-	maxLifetime            = (15 * time.Minute)
-	batchSize              = 1000
+	maxIdleConns = 100
+	maxOpenConns = 200
+	maxLifetime  = (15 * time.Minute)
+	batchSize    = 1000
+	// maxUIDAttempts is the number of attempts to insert URL
+	// with the new short UID on the collision of the generated ones.
+	maxUIDAttempts         = 10
 	skipDefaultTransaction = true
 )
 
@@ -125,15 +129,16 @@ func (d *DB) AddOld(longURL, userID string) (string, error) {
 
 	if err == nil {
 		uri.UID = uint(userIDtoInt)
+		uri.Plain = true
 	}
 
 	rowsAffected := writeURL(d.db, uri)
 
 	if rowsAffected == 0 {
-		return getShortUID(d.db, longURL, d.conf.GetBaseURL()), ErrAlreadyExists
+		return getShortUID(d.db, uri.UID, longURL), ErrAlreadyExists
 	}
 
-	return d.conf.GetBaseURL() + uri.ShortUID, nil
+	return uri.ShortUID, nil
 }
 
 // Delete performs removing data from shortens URLs by UID.
@@ -153,26 +158,37 @@ func (d *DB) DeleteByLongURL(longURL string) error {
 }
 
 // Add performs adding URL to the DB.
+//
+// If the user has already shortened URL without options,
+// the existing short UID is returned with ErrAlreadyExists.
 func (d *DB) Add(longURL, userID string) (string, error) {
-	var uri models.Urls
-	res := d.db.Where("long_url = ?", longURL).Limit(1).Find(&uri)
-	if res.RowsAffected > 0 {
-		return d.conf.GetBaseURL() + uri.ShortUID, ErrAlreadyExists
+	uri := models.Urls{
+		ShortUID: service.GenUID(d.conf.GetSizeUID()),
+		LongURL:  longURL,
 	}
 
-	uri.LongURL = longURL
-	uri.ShortUID = service.GenUID(d.conf.GetSizeUID())
 	userIDtoInt, err := strconv.Atoi(userID)
 	if err == nil {
 		uri.UID = uint(userIDtoInt)
+		uri.Plain = true
+	}
+
+	res := d.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&uri)
+	if res.Error != nil {
+		return "", res.Error
 	}
 
-	d.db.Create(&uri)
+	if res.RowsAffected == 0 {
+		return getShortUID(d.db, uri.UID, longURL), ErrAlreadyExists
+	}
 
-	return d.conf.GetBaseURL() + uri.ShortUID, nil
+	return uri.ShortUID, nil
 }
 
 // AddBatch performs adding URL to the DB (batch insert).
+//
+// If the user has already shortened URL without options,
+// the existing short UID is returned.
 func (d *DB) AddBatch(longURL, userID, corID string) string {
 	userIDtoInt, err := strconv.Atoi(userID)
 
@@ -183,15 +199,182 @@ func (d *DB) AddBatch(longURL, userID, corID string) string {
 
 	if err == nil {
 		uri.UID = uint(userIDtoInt)
+		uri.Plain = true
 	}
 
 	rowsAffected := writeURLBatch(d.db, uri)
 
 	if rowsAffected == 0 {
-		return getShortUID(d.db, longURL, d.conf.GetBaseURL())
+		return getShortUID(d.db, uri.UID, longURL)
+	}
+
+	return uri.ShortUID
+}
+
+// AddMany performs adding the batch of URLs to the DB.
+//
+// The URLs are inserted with multi-row INSERT ... ON CONFLICT DO NOTHING
+// and then the short UIDs of all URLs are read with one query, so the URLs
+// shortened by the user earlier without options get their existing short UIDs.
+// The URLs not inserted due to the collision of the generated short UIDs
+// are inserted again with new ones. The items are processed in chunks
+// of batchSize in one transaction, the results are returned in the order
+// of the items.
+func (d *DB) AddMany(userID string, items []BatchItem) ([]BatchResult, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, ErrUserIDNotValid
+	}
+
+	shortUIDs := make(map[string]string, len(items))
+
+	err = d.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(items); start += batchSize {
+			end := start + batchSize
+			if end > len(items) {
+				end = len(items)
+			}
+
+			pending := items[start:end]
+
+			for attempt := 0; len(pending) > 0 && attempt < maxUIDAttempts; attempt++ {
+				if err := addChunk(tx, uint(uid), d.conf.GetSizeUID(), pending, shortUIDs); err != nil {
+					return err
+				}
+
+				rest := pending[:0:0]
+
+				for _, item := range pending {
+					if _, ok := shortUIDs[item.LongURL]; !ok {
+						rest = append(rest, item)
+					}
+				}
+
+				pending = rest
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("batch insert error: %w", err)
 	}
 
-	return d.conf.GetBaseURL() + uri.ShortUID
+	results := make([]BatchResult, 0, len(items))
+
+	for _, item := range items {
+		result := BatchResult{CorrelationID: item.CorrelationID, Err: ErrInvalidData}
+
+		if shortUID, ok := shortUIDs[item.LongURL]; ok {
+			result.ShortUID, result.Err = shortUID, nil
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// addChunk inserts the chunk of URLs of the user in the transaction
+// and maps the original URLs to the short UIDs of the inserted
+// or existing URLs.
+func addChunk(tx *gorm.DB, uid uint, sizeUID int, chunk []BatchItem, shortUIDs map[string]string) error {
+	urls := make([]models.Urls, 0, len(chunk))
+	longURLs := make([]string, 0, len(chunk))
+
+	for _, item := range chunk {
+		urls = append(urls, models.Urls{
+			ShortUID:      service.GenUID(sizeUID),
+			LongURL:       item.LongURL,
+			CorrelationID: item.CorrelationID,
+			UID:           uid,
+			Plain:         true,
+		})
+		longURLs = append(longURLs, item.LongURL)
+	}
+
+	if res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&urls); res.Error != nil {
+		return res.Error
+	}
+
+	var existing []models.Urls
+
+	res := tx.Select("short_uid", "long_url").
+		Where("uid = ? AND plain AND NOT removed AND long_url IN ?", uid, longURLs).Find(&existing)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	for _, url := range existing {
+		shortUIDs[url.LongURL] = url.ShortUID
+	}
+
+	return nil
+}
+
+// AddURL performs adding URL with the optional alias and expiry to the DB.
+//
+// The alias is checked by the primary key on insert, so the concurrent
+// requests with the same alias get ErrAliasExists.
+//
+// If the user has already shortened URL without options and the options
+// are not set, the existing short UID is returned with ErrAlreadyExists.
+func (d *DB) AddURL(url NewURL) (string, error) {
+	uri := models.Urls{
+		ShortUID:      url.Alias,
+		LongURL:       url.LongURL,
+		CorrelationID: url.CorrelationID,
+		ExpiresAt:     url.ExpiresAt,
+		UTM:           models.UTM(url.UTM),
+		ForwardQuery:  url.ForwardQuery,
+		Rules:         marshalRules(url.Rules),
+		PasswordHash:  url.PasswordHash,
+		MaxClicks:     url.MaxClicks,
+	}
+
+	if uri.ShortUID == "" {
+		uri.ShortUID = service.GenUID(d.conf.GetSizeUID())
+	}
+
+	uri.ShortUID = DomainUID(url.Domain, uri.ShortUID)
+
+	if userIDtoInt, err := strconv.Atoi(url.UserID); err == nil {
+		uri.UID = uint(userIDtoInt)
+		uri.Plain = url.plain()
+	}
+
+	if uri.Plain {
+		if writeURL(d.db, uri) == 0 {
+			return getShortUID(d.db, uri.UID, uri.LongURL), ErrAlreadyExists
+		}
+
+		return uri.ShortUID, nil
+	}
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Create(&uri); res.Error != nil {
+			return res.Error
+		}
+
+		if err := setVariants(tx, uri.ShortUID, url.Variants); err != nil {
+			return err
+		}
+
+		if len(url.Tags) == 0 && url.Folder == "" {
+			return nil
+		}
+
+		return setLabels(tx, uri.UID, uri.ShortUID, LabelsUpdate{Tags: &url.Tags, Folder: &url.Folder})
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) && url.Alias != "" {
+		return "", ErrAliasExists
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return uri.ShortUID, nil
 }
 
 // UpdateOld changes short link usage statistics.
@@ -220,19 +403,49 @@ func (d *DB) Update(uid string) {
 
 // GetURL returns the original url by its id.
 func (d *DB) GetURL(uid string) (string, error) {
+	link, err := d.GetLink(uid)
+
+	return link.LongURL, err
+}
+
+// GetLink returns the original url with the settings of the redirect by its id.
+func (d *DB) GetLink(uid string) (Link, error) {
 	var url models.Urls
 
 	res := d.db.Where("short_uid = ?", uid).First(&url)
 
 	if res.RowsAffected == 0 {
-		return url.LongURL, ErrUIDNotValid
+		return Link{}, ErrUIDNotValid
+	}
+
+	variants, err := d.variants(uid)
+	if err != nil {
+		return Link{}, err
+	}
+
+	link := Link{
+		LongURL:      url.LongURL,
+		UserID:       owner(url.UID),
+		UTM:          UTM(url.UTM),
+		Rules:        unmarshalRules(url.Rules),
+		Variants:     variants,
+		PasswordHash: url.PasswordHash,
+		ForwardQuery: url.ForwardQuery,
 	}
 
 	if url.Removed {
-		return url.LongURL, ErrShortURLRemoved
+		return link, ErrShortURLRemoved
+	}
+
+	if url.ExpiresAt != nil && time.Now().After(*url.ExpiresAt) {
+		return link, ErrShortURLExpired
+	}
+
+	if exhausted(url.Statistics, url.MaxClicks) {
+		return link, ErrShortURLExhausted
 	}
 
-	return url.LongURL, nil
+	return link, nil
 }
 
 // Stat returns short link uses statistics by its short UID.
@@ -242,17 +455,22 @@ func (d *DB) Stat(uid string) (Statistics, error) {
 	res := d.db.Where("short_uid = ?", uid).First(&uri)
 
 	stat := Statistics{
-		ShortURL:  d.conf.GetBaseURL() + uid,
 		LongURL:   uri.LongURL,
 		CreatedAt: uri.CreatedAt.Format("02.01.2006 15:04:05"),
 		Usage:     uri.Statistics,
+		Protected: uri.PasswordHash != "",
+		Health:    health(uri.Health),
+		Metadata:  metadata(uri.Metadata),
 	}
 
 	if res.RowsAffected == 0 {
 		return stat, ErrUIDNotValid
 	}
 
-	return stat, nil
+	variants, err := d.variants(uid)
+	stat.Variants = variants
+
+	return stat, err
 }
 
 // Create performs adding new user in DB.
@@ -272,64 +490,250 @@ func getUrlsUser(db *gorm.DB, uid uint) []models.Urls {
 }
 
 // GetUrlsUser performs getting shorts URLs from DB for current user.
-func (d *DB) GetUrlsUser(userID string) ([]struct {
-	ShortUID string `json:"short_url"`
-	LongURL  string `json:"original_url"`
-}, error,
-) {
+//
+// Removed URLs are also returned with the Removed flag.
+func (d *DB) GetUrlsUser(userID string) ([]UserURL, error) {
 	uid, err := strconv.Atoi(userID)
 	if err != nil {
-		return []struct {
-			ShortUID string `json:"short_url"`
-			LongURL  string `json:"original_url"`
-		}{}, ErrUserIDNotValid
+		return nil, ErrUserIDNotValid
 	}
 
-	var urls []struct {
-		ShortUID string `json:"short_url"`
-		LongURL  string `json:"original_url"`
-	}
+	var urls []models.Urls
 
-	d.db.Model(models.Urls{}).
-		Where("uid = ?", uid).Find(&urls)
+	if res := d.db.Order("created_at, short_uid").Where("uid = ?", uid).Find(&urls); res.Error != nil {
+		return nil, res.Error
+	}
 
 	if len(urls) == 0 {
 		return nil, ErrUserUrlsEmpty
 	}
 
-	return urls, nil
+	return d.userURLs(urls)
+}
+
+// userURLs converts the stored URLs to the data models of the user URLs
+// and attaches their tags with one query.
+func (d *DB) userURLs(urls []models.Urls) ([]UserURL, error) {
+	var (
+		tags []struct {
+			ShortUID string
+			Name     string
+		}
+		folders    []models.Folders
+		out        = make([]UserURL, 0, len(urls))
+		shortUIDs  = make([]string, 0, len(urls))
+		folderIDs  = make([]uint, 0)
+		tagsByUID  = make(map[string][]string)
+		folderByID = make(map[uint]string)
+		variants   []models.Variants
+		byUID      = make(map[string][]Variant)
+	)
+
+	for _, item := range urls {
+		shortUIDs = append(shortUIDs, item.ShortUID)
+
+		if item.FolderID != nil {
+			folderIDs = append(folderIDs, *item.FolderID)
+		}
+	}
+
+	if len(shortUIDs) > 0 {
+		res := d.db.Table("url_tags").Select("url_tags.short_uid, tags.name").
+			Joins("JOIN tags ON tags.id = url_tags.tag_id").
+			Where("url_tags.short_uid IN ?", shortUIDs).Order("tags.name").Scan(&tags)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+	}
+
+	if len(folderIDs) > 0 {
+		if res := d.db.Where("id IN ?", folderIDs).Find(&folders); res.Error != nil {
+			return nil, res.Error
+		}
+	}
+
+	if len(shortUIDs) > 0 {
+		if res := d.db.Where("short_uid IN ?", shortUIDs).Order("position").Find(&variants); res.Error != nil {
+			return nil, res.Error
+		}
+	}
+
+	for _, tag := range tags {
+		tagsByUID[tag.ShortUID] = append(tagsByUID[tag.ShortUID], tag.Name)
+	}
+
+	for _, item := range variants {
+		byUID[item.ShortUID] = append(byUID[item.ShortUID], Variant{URL: item.URL, Weight: item.Weight, Clicks: item.Clicks})
+	}
+
+	for _, folder := range folders {
+		folderByID[folder.ID] = folder.Name
+	}
+
+	for _, item := range urls {
+		url := UserURL{
+			CreatedAt:     item.CreatedAt,
+			ExpiresAt:     item.ExpiresAt,
+			ShortUID:      item.ShortUID,
+			LongURL:       item.LongURL,
+			CorrelationID: item.CorrelationID,
+			Tags:          tagsByUID[item.ShortUID],
+			UTM:           userUTM(UTM(item.UTM)),
+			ForwardQuery:  item.ForwardQuery,
+			Rules:         unmarshalRules(item.Rules),
+			Variants:      byUID[item.ShortUID],
+			Protected:     item.PasswordHash != "",
+			MaxClicks:     item.MaxClicks,
+			Health:        health(item.Health),
+			Metadata:      metadata(item.Metadata),
+			Clicks:        item.Statistics,
+			Removed:       item.Removed,
+		}
+
+		if item.FolderID != nil {
+			url.Folder = folderByID[*item.FolderID]
+		}
+
+		out = append(out, url)
+	}
+
+	return out, nil
+}
+
+// ListUrlsUser performs getting shorts URLs from DB
+// for current user page by page.
+//
+// URLs are filtered and sorted according to the options,
+// by default removed URLs are skipped and URLs are ordered by creation time.
+func (d *DB) ListUrlsUser(userID string, opts ListOptions) (URLsPage, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return URLsPage{}, ErrUserIDNotValid
+	}
+
+	opts, err = opts.normalize()
+	if err != nil {
+		return URLsPage{}, err
+	}
+
+	after, err := decodeCursor(opts.Cursor, opts.Sort)
+	if err != nil {
+		return URLsPage{}, err
+	}
+
+	var (
+		urls   []models.Urls
+		total  int64
+		limit  = pageLimit(opts.Limit)
+		filter = listFilter(uint(uid), opts)
+	)
+
+	if res := d.db.Model(&models.Urls{}).Scopes(filter).Count(&total); res.Error != nil {
+		return URLsPage{}, res.Error
+	}
+
+	column, order, compare := "created_at", "ASC", ">"
+	if opts.Sort == SortClicks {
+		column = "statistics"
+	}
+
+	if opts.Desc {
+		order, compare = "DESC", "<"
+	}
+
+	query := d.db.Scopes(filter)
+
+	if !after.isZero() {
+		var key any = after.key
+		if opts.Sort == SortCreatedAt {
+			key = after.createdAt()
+		}
+
+		query = query.Where("("+column+", short_uid) "+compare+" (?, ?)", key, after.shortUID)
+	}
+
+	// one more URL is requested to check if there is a next page.
+	res := query.Order(column + " " + order + ", short_uid " + order).Limit(limit + 1).Find(&urls)
+	if res.Error != nil {
+		return URLsPage{}, res.Error
+	}
+
+	page := URLsPage{Total: int(total)}
+
+	if len(urls) > limit {
+		last := urls[limit-1]
+		key := int64(last.Statistics)
+
+		if opts.Sort == SortCreatedAt {
+			key = last.CreatedAt.UnixNano()
+		}
+
+		page.NextCursor = encodeCursor(opts.Sort, key, last.ShortUID)
+		urls = urls[:limit]
+	}
+
+	page.URLs, err = d.userURLs(urls)
+
+	return page, err
+}
+
+// listFilter returns the scope filtering the URLs of the user by the options.
+func listFilter(uid uint, opts ListOptions) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		query = query.Where("uid = ?", uid)
+
+		switch opts.Status {
+		case StatusActive:
+			query = query.Where("removed = ?", false)
+		case StatusRemoved:
+			query = query.Where("removed = ?", true)
+		}
+
+		if !opts.CreatedFrom.IsZero() {
+			query = query.Where("created_at >= ?", opts.CreatedFrom)
+		}
+
+		if !opts.CreatedTo.IsZero() {
+			query = query.Where("created_at < ?", opts.CreatedTo)
+		}
+
+		if opts.Domain != "" {
+			query = query.Where("long_url ~* ?", domainPattern(opts.Domain))
+		}
+
+		if opts.Tag != "" {
+			query = query.Where("short_uid IN (SELECT url_tags.short_uid FROM url_tags "+
+				"JOIN tags ON tags.id = url_tags.tag_id WHERE tags.uid = ? AND tags.name = ?)", uid, opts.Tag)
+		}
+
+		if opts.Folder != "" {
+			query = query.Where("folder_id IN (SELECT id FROM folders WHERE uid = ? AND name = ?)", uid, opts.Folder)
+		}
+
+		if opts.Broken {
+			query = query.Where("health_broken = ?", true)
+		}
+
+		return query
+	}
 }
 
 // GetUrlsUserOld (Deprecated) performs getting shorts URLs from DB for current user.
-func (d *DB) GetUrlsUserOld(userID string) ([]struct {
-	ShortUID string `json:"short_url"`
-	LongURL  string `json:"original_url"`
-}, error,
-) {
+func (d *DB) GetUrlsUserOld(userID string) ([]UserURL, error) {
 	uid, err := strconv.Atoi(userID)
 	if err != nil {
-		return []struct {
-			ShortUID string `json:"short_url"`
-			LongURL  string `json:"original_url"`
-		}{}, ErrUserIDNotValid
+		return nil, ErrUserIDNotValid
 	}
 
 	urls := getUrlsUser(d.db, uint(uid))
-
-	usersURL := make([]struct {
-		ShortUID string `json:"short_url"`
-		LongURL  string `json:"original_url"`
-	}, 0, len(urls))
+	usersURL := make([]UserURL, 0, len(urls))
 
 	if len(urls) == 0 {
 		return usersURL, ErrUserUrlsEmpty
 	}
 
 	for _, item := range urls {
-		usersURL = append(usersURL, struct {
-			ShortUID string `json:"short_url"`
-			LongURL  string `json:"original_url"`
-		}{
+		usersURL = append(usersURL, UserURL{
 			ShortUID: item.ShortUID,
 			LongURL:  item.LongURL,
 		})
@@ -388,12 +792,12 @@ func writeURL(db *gorm.DB, uri models.Urls) int {
 	return int(res.RowsAffected)
 }
 
-func getShortUID(db *gorm.DB, longURL, baseURL string) string {
+func getShortUID(db *gorm.DB, uid uint, longURL string) string {
 	var uri models.Urls
 
-	db.Where("long_url = ?", longURL).Find(&uri)
+	db.Where("uid = ? AND plain AND NOT removed AND long_url = ?", uid, longURL).Find(&uri)
 
-	return baseURL + uri.ShortUID
+	return uri.ShortUID
 }
 
 func writeURLBatch(db *gorm.DB, uri models.Urls) int {
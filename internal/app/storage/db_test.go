@@ -2,7 +2,6 @@ package storage_test
 
 import (
 	"strconv"
-	"strings"
 	"testing"
 
 	"github.com/alaleks/shortener/internal/app/config"
@@ -24,8 +23,7 @@ func BenchmarkUpdate(b *testing.B) {
 		userID   = "1"
 	)
 
-	short, _ := db.Add(shortURL, userID)
-	shortUID := strings.Split(short, "/")[3]
+	shortUID, _ := db.Add(shortURL, userID)
 
 	b.ResetTimer()
 
@@ -43,7 +41,7 @@ func BenchmarkUpdate(b *testing.B) {
 
 	b.StopTimer()
 
-	_ = db.Delete(strings.Split(short, "/")[3])
+	_ = db.Delete(shortUID)
 }
 
 func BenchmarkAdd(b *testing.B) {
@@ -117,6 +115,82 @@ func BenchmarkGetUrlsUser(b *testing.B) {
 	b.StopTimer()
 
 	for _, v := range uids {
-		_ = db.Delete(strings.Split(v, "/")[3])
+		_ = db.Delete(v)
 	}
 }
+
+func BenchmarkAddMany(b *testing.B) {
+	b.Setenv("DATABASE_DSN", "host=localhost user=shortener password=3BJ2zWGPbQps dbname=shortener port=5432")
+	conf := config.New(config.Options{Env: true})
+	db := storage.NewDB(conf)
+	err := db.Init()
+	if err != nil {
+		return
+	}
+
+	var (
+		urls   []string
+		items  []storage.BatchItem
+		userID = "1"
+	)
+
+	for i := 0; i < 2000; i++ {
+		urls = append(urls, "http://example.com/batch/"+strconv.Itoa(i))
+	}
+
+	for i, v := range urls[1000:] {
+		items = append(items, storage.BatchItem{LongURL: v, CorrelationID: strconv.Itoa(i)})
+	}
+
+	b.ResetTimer()
+
+	b.Run("one by one", func(b *testing.B) {
+		for i, v := range urls[0:1000] {
+			_ = db.AddBatch(v, userID, strconv.Itoa(i))
+		}
+	})
+
+	b.Run("one round trip", func(b *testing.B) {
+		_, _ = db.AddMany(userID, items)
+	})
+
+	b.StopTimer()
+
+	for _, v := range urls {
+		_ = db.DeleteByLongURL(v)
+	}
+}
+
+func TestDBAddDuplicates(t *testing.T) {
+	t.Setenv("DATABASE_DSN", "host=localhost user=shortener password=3BJ2zWGPbQps dbname=shortener port=5432")
+	conf := config.New(config.Options{Env: true})
+	db := storage.NewDB(conf)
+	if err := db.Init(); err != nil {
+		t.Skip(err)
+	}
+
+	testDuplicates(t, db)
+}
+
+func TestDBAddAlias(t *testing.T) {
+	t.Setenv("DATABASE_DSN", "host=localhost user=shortener password=3BJ2zWGPbQps dbname=shortener port=5432")
+	conf := config.New(config.Options{Env: true})
+	db := storage.NewDB(conf)
+	if err := db.Init(); err != nil {
+		t.Skip(err)
+	}
+
+	testAlias(t, db)
+}
+
+func TestDBAddMany(t *testing.T) {
+	t.Setenv("DATABASE_DSN", "host=localhost user=shortener password=3BJ2zWGPbQps dbname=shortener port=5432")
+	t.Setenv("SIZE_UID", "2")
+	conf := config.New(config.Options{Env: true})
+	db := storage.NewDB(conf)
+	if err := db.Init(); err != nil {
+		t.Skip(err)
+	}
+
+	testAddMany(t, db)
+}
@@ -13,10 +13,12 @@ import (
 // Data Structures
 type (
 	DefaultStorage struct {
-		conf  config.Configurator
-		urls  map[string]*URLElement // where key uid short url
-		users map[uint][]string      // where key uid user, value a UID of short URL
-		mu    sync.RWMutex
+		conf       config.Configurator
+		urls       map[string]*URLElement // where key uid short url
+		users      map[uint][]string      // where key uid user, value a UID of short URL
+		webhooks   map[string]*Webhook    // where key ID of webhook
+		deliveries map[string]*Delivery   // where key ID of delivery
+		mu         sync.RWMutex
 	}
 
 	URLElement struct {
@@ -24,28 +26,61 @@ type (
 		LongURL       string
 		CorrelationID string
 		Statistics    uint // short URL usage statistics (actually this is the number of redirects)
+		ExpiresAt     *time.Time
 		Removed       bool
+		Folder        string
+		Tags          []string
+		UTM           UTM
+		ForwardQuery  bool
+		Rules         []Rule
+		Variants      []Variant
+		PasswordHash  string
+		MaxClicks     uint
+		Health        *Health   // nil until the destination is checked
+		Metadata      *Metadata // nil until the destination page is fetched
+		UserID        string    // empty for anonymous short URL
+		// Plain is set for URL of the user shortened without options,
+		// such URLs are not duplicated.
+		Plain bool
+		// ExpiryNotified is set when the expired URL is taken for the event.
+		ExpiryNotified bool
 	}
 )
 
 // NewDefault creates a pointer of DefaultStorage.
 func NewDefault(conf config.Configurator) *DefaultStorage {
 	return &DefaultStorage{
-		urls:  make(map[string]*URLElement),
-		users: make(map[uint][]string),
-		mu:    sync.RWMutex{},
-		conf:  conf,
+		urls:       make(map[string]*URLElement),
+		users:      make(map[uint][]string),
+		webhooks:   make(map[string]*Webhook),
+		deliveries: make(map[string]*Delivery),
+		mu:         sync.RWMutex{},
+		conf:       conf,
 	}
 }
 
 // Add performs adding URL to the default storage.
+//
+// If the user has already shortened URL without options,
+// the existing short UID is returned with ErrAlreadyExists.
 func (ds *DefaultStorage) Add(longURL, userID string) (string, error) {
 	if !strings.HasPrefix(longURL, "http") {
 		longURL = "http://" + longURL
 	}
 
+	uidToInt, err := strconv.Atoi(userID)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if err == nil {
+		if uid, ok := ds.plainURL(uint(uidToInt), longURL); ok {
+			return uid, ErrAlreadyExists
+		}
+	}
+
 	// генерируем id
-	uid := service.GenUID(ds.conf.GetSizeUID())
+	uid := ds.newUID("")
 
 	element := &URLElement{
 		LongURL:    longURL,
@@ -53,27 +88,38 @@ func (ds *DefaultStorage) Add(longURL, userID string) (string, error) {
 		Statistics: 0,
 	}
 
-	uidToInt, err := strconv.Atoi(userID)
-
-	ds.mu.Lock()
 	ds.urls[uid] = element
 
 	if err == nil {
+		element.UserID = userID
+		element.Plain = true
 		ds.users[uint(uidToInt)] = append(ds.users[uint(uidToInt)], uid)
 	}
 
-	ds.mu.Unlock()
-
-	return ds.conf.GetBaseURL() + uid, nil
+	return uid, nil
 }
 
 // AddBatch performs adding data to storage when batch processing.
+//
+// If the user has already shortened URL without options,
+// the existing short UID is returned.
 func (ds *DefaultStorage) AddBatch(longURL, userID, corID string) string {
 	if !strings.HasPrefix(longURL, "http") {
 		longURL = "http://" + longURL
 	}
 
-	uid := service.GenUID(ds.conf.GetSizeUID())
+	uidToInt, err := strconv.Atoi(userID)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if err == nil {
+		if uid, ok := ds.plainURL(uint(uidToInt), longURL); ok {
+			return uid
+		}
+	}
+
+	uid := ds.newUID("")
 
 	element := &URLElement{
 		LongURL:       longURL,
@@ -82,35 +128,187 @@ func (ds *DefaultStorage) AddBatch(longURL, userID, corID string) string {
 		CorrelationID: corID,
 	}
 
+	ds.urls[uid] = element
+
+	if err == nil {
+		element.UserID = userID
+		element.Plain = true
+		ds.users[uint(uidToInt)] = append(ds.users[uint(uidToInt)], uid)
+	}
+
+	return uid
+}
+
+// AddMany performs adding the batch of URLs to the default storage.
+//
+// URLs shortened by the user earlier without options get their existing
+// short UIDs. The results are returned in the order of the items.
+func (ds *DefaultStorage) AddMany(userID string, items []BatchItem) ([]BatchResult, error) {
+	results := make([]BatchResult, 0, len(items))
+	now := time.Now()
+
 	uidToInt, err := strconv.Atoi(userID)
 
 	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	for _, item := range items {
+		longURL := item.LongURL
+		if !strings.HasPrefix(longURL, "http") {
+			longURL = "http://" + longURL
+		}
+
+		result := BatchResult{CorrelationID: item.CorrelationID}
+
+		if err == nil {
+			if uid, ok := ds.plainURL(uint(uidToInt), longURL); ok {
+				result.ShortUID = uid
+				results = append(results, result)
+
+				continue
+			}
+		}
+
+		result.ShortUID = ds.newUID("")
+		element := &URLElement{
+			LongURL:       longURL,
+			CreatedAt:     now,
+			CorrelationID: item.CorrelationID,
+		}
+
+		ds.urls[result.ShortUID] = element
+
+		if err == nil {
+			element.UserID = userID
+			element.Plain = true
+			ds.users[uint(uidToInt)] = append(ds.users[uint(uidToInt)], result.ShortUID)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// plainURL returns the short UID of URL shortened by the user without options,
+// the lock must be held by the caller.
+func (ds *DefaultStorage) plainURL(userID uint, longURL string) (string, bool) {
+	for _, uid := range ds.users[userID] {
+		if element, ok := ds.urls[uid]; ok && element.Plain && !element.Removed && element.LongURL == longURL {
+			return uid, true
+		}
+	}
+
+	return "", false
+}
+
+// newUID returns the generated short UID of the domain which is not taken yet,
+// the lock must be held by the caller.
+func (ds *DefaultStorage) newUID(domain string) string {
+	for {
+		uid := DomainUID(domain, service.GenUID(ds.conf.GetSizeUID()))
+		if _, ok := ds.urls[uid]; !ok {
+			return uid
+		}
+	}
+}
+
+// AddURL performs adding URL with the optional alias and expiry to the default storage.
+//
+// If the user has already shortened URL without options and the options
+// are not set, the existing short UID is returned with ErrAlreadyExists.
+func (ds *DefaultStorage) AddURL(url NewURL) (string, error) {
+	longURL := url.LongURL
+	if !strings.HasPrefix(longURL, "http") {
+		longURL = "http://" + longURL
+	}
+
+	element := &URLElement{
+		LongURL:       longURL,
+		CreatedAt:     time.Now(),
+		CorrelationID: url.CorrelationID,
+		ExpiresAt:     url.ExpiresAt,
+		Folder:        url.Folder,
+		Tags:          url.Tags,
+		UTM:           url.UTM,
+		ForwardQuery:  url.ForwardQuery,
+		Rules:         url.Rules,
+		Variants:      resetVariants(url.Variants),
+		PasswordHash:  url.PasswordHash,
+		MaxClicks:     url.MaxClicks,
+	}
+
+	uidToInt, err := strconv.Atoi(url.UserID)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if err == nil && url.plain() {
+		if existing, ok := ds.plainURL(uint(uidToInt), longURL); ok {
+			return existing, ErrAlreadyExists
+		}
+	}
+
+	uid := DomainUID(url.Domain, url.Alias)
+
+	switch _, ok := ds.urls[uid]; {
+	case url.Alias == "":
+		uid = ds.newUID(url.Domain)
+	case ok:
+		return "", ErrAliasExists
+	}
+
 	ds.urls[uid] = element
 
 	if err == nil {
+		element.UserID = url.UserID
+		element.Plain = url.plain()
 		ds.users[uint(uidToInt)] = append(ds.users[uint(uidToInt)], uid)
 	}
 
-	ds.mu.Unlock()
-
-	return ds.conf.GetBaseURL() + uid
+	return uid, nil
 }
 
 // GetURL returns the original url by its short UID.
 func (ds *DefaultStorage) GetURL(uid string) (string, error) {
+	link, err := ds.GetLink(uid)
+
+	return link.LongURL, err
+}
+
+// GetLink returns the original url with the settings of the redirect by its short UID.
+func (ds *DefaultStorage) GetLink(uid string) (Link, error) {
 	ds.mu.RLock()
 	uri, check := ds.urls[uid]
 	ds.mu.RUnlock()
 
 	if !check {
-		return "", ErrUIDNotValid
+		return Link{}, ErrUIDNotValid
+	}
+
+	link := Link{
+		LongURL:      uri.LongURL,
+		UserID:       uri.UserID,
+		UTM:          uri.UTM,
+		Rules:        uri.Rules,
+		Variants:     append([]Variant(nil), uri.Variants...),
+		PasswordHash: uri.PasswordHash,
+		ForwardQuery: uri.ForwardQuery,
 	}
 
 	if uri.Removed {
-		return uri.LongURL, ErrShortURLRemoved
+		return link, ErrShortURLRemoved
+	}
+
+	if uri.ExpiresAt != nil && time.Now().After(*uri.ExpiresAt) {
+		return link, ErrShortURLExpired
 	}
 
-	return uri.LongURL, nil
+	if exhausted(uri.Statistics, uri.MaxClicks) {
+		return link, ErrShortURLExhausted
+	}
+
+	return link, nil
 }
 
 // Update perfoms changing short link usage statistics.
@@ -127,18 +325,21 @@ func (ds *DefaultStorage) Update(uid string) {
 // Stat returns short link statistics by its id.
 func (ds *DefaultStorage) Stat(uid string) (Statistics, error) {
 	ds.mu.RLock()
-	uri, check := ds.urls[uid]
-	ds.mu.RUnlock()
+	defer ds.mu.RUnlock()
 
+	uri, check := ds.urls[uid]
 	if !check {
 		return Statistics{}, ErrUIDNotValid
 	}
 
 	stat := Statistics{
-		ShortURL:  ds.conf.GetBaseURL() + uid,
 		LongURL:   uri.LongURL,
 		CreatedAt: uri.CreatedAt.Format("02.01.2006 15:04:05"),
+		Variants:  append([]Variant(nil), uri.Variants...),
 		Usage:     uri.Statistics,
+		Protected: uri.PasswordHash != "",
+		Health:    copyHealth(uri.Health),
+		Metadata:  copyMetadata(uri.Metadata),
 	}
 
 	return stat, nil
@@ -1,11 +1,13 @@
 package storage_test
 
 import (
+	"errors"
 	"strconv"
-	"strings"
+	"sync"
 	"testing"
 
 	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/service"
 	"github.com/alaleks/shortener/internal/app/storage"
 )
 
@@ -19,8 +21,8 @@ func BenchmarkDelUrls(b *testing.B) {
 	)
 
 	for i := 0; i < 2000; i++ {
-		shortURL, _ := storeDefault.Add("http://example.com/"+strconv.Itoa(i), userID)
-		shortUIDs = append(shortUIDs, strings.Split(shortURL, "/")[3])
+		shortUID, _ := storeDefault.Add("http://example.com/"+strconv.Itoa(i), userID)
+		shortUIDs = append(shortUIDs, shortUID)
 	}
 
 	b.ResetTimer()
@@ -38,3 +40,208 @@ func BenchmarkDelUrls(b *testing.B) {
 	})
 
 }
+
+func TestAddAlias(t *testing.T) {
+	t.Parallel()
+
+	testAlias(t, storage.NewDefault(config.New(config.Options{})))
+}
+
+// testAlias checks that only one of the concurrent requests
+// with the same alias adds URL.
+func testAlias(t *testing.T, st storage.Storage) {
+	t.Helper()
+	// данные для теста
+	var (
+		alias  = "alias" + service.GenUID(8)
+		user   = strconv.Itoa(int(st.Create()))
+		errs   = make(chan error, 10)
+		wg     sync.WaitGroup
+		added  int
+		exists int
+	)
+
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			_, err := st.AddURL(storage.NewURL{LongURL: "https://example.com/" + strconv.Itoa(i), UserID: user, Alias: alias})
+			errs <- err
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	t.Cleanup(func() {
+		if db, ok := st.(*storage.DB); ok {
+			_ = db.Delete(alias)
+		}
+	})
+
+	for err := range errs {
+		switch {
+		case err == nil:
+			added++
+		case errors.Is(err, storage.ErrAliasExists):
+			exists++
+		default:
+			t.Errorf("error should be %v but received %v", storage.ErrAliasExists, err)
+		}
+	}
+
+	if added != 1 || exists != cap(errs)-1 {
+		t.Errorf("alias should be added once but added %d times", added)
+	}
+}
+
+func TestAddMany(t *testing.T) {
+	t.Setenv("SIZE_UID", "2")
+
+	testAddMany(t, storage.NewDefault(config.New(config.Options{Env: true})))
+}
+
+// testAddMany checks that all URLs of the batch are added with the distinct
+// short UIDs when the generated short UIDs collide.
+func testAddMany(t *testing.T, st storage.Storage) {
+	t.Helper()
+	// данные для теста
+	var (
+		user  = strconv.Itoa(int(st.Create()))
+		items = make([]storage.BatchItem, 0, 300)
+	)
+
+	for i := 0; i < cap(items); i++ {
+		items = append(items, storage.BatchItem{
+			CorrelationID: strconv.Itoa(i),
+			LongURL:       "https://example.com/" + service.GenUID(10),
+		})
+	}
+
+	results, err := st.AddMany(user, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if db, ok := st.(*storage.DB); ok {
+			for _, result := range results {
+				_ = db.Delete(result.ShortUID)
+			}
+		}
+	})
+
+	shortUIDs := make(map[string]bool, len(results))
+
+	for i, result := range results {
+		if result.Err != nil || result.CorrelationID != items[i].CorrelationID || shortUIDs[result.ShortUID] {
+			t.Fatalf("URL %s should be added with the new short UID but received %+v", items[i].LongURL, result)
+		}
+
+		shortUIDs[result.ShortUID] = true
+
+		if longURL, err := st.GetURL(result.ShortUID); err != nil || longURL != items[i].LongURL {
+			t.Errorf("short UID %s should lead to %s but received %s (%v)", result.ShortUID, items[i].LongURL, longURL, err)
+		}
+	}
+}
+
+func TestAddDuplicates(t *testing.T) {
+	t.Parallel()
+
+	testDuplicates(t, storage.NewDefault(config.New(config.Options{})))
+}
+
+// testDuplicates checks that only URL of the same user without options
+// is not duplicated, the options of the new URLs are kept.
+func testDuplicates(t *testing.T, st storage.Storage) {
+	t.Helper()
+	// данные для теста
+	var (
+		longURL = "https://example.com/" + service.GenUID(10)
+		user    = strconv.Itoa(int(st.Create()))
+		other   = strconv.Itoa(int(st.Create()))
+		added   []string
+	)
+
+	t.Cleanup(func() {
+		if db, ok := st.(*storage.DB); ok {
+			for _, shortUID := range added {
+				_ = db.Delete(shortUID)
+			}
+		}
+	})
+
+	plain, err := st.Add(longURL, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added = append(added, plain)
+
+	tests := []struct {
+		name string
+		url  storage.NewURL
+		err  error
+	}{
+		{name: "тот же пользователь", url: storage.NewURL{LongURL: longURL, UserID: user}, err: storage.ErrAlreadyExists},
+		{name: "другой пользователь", url: storage.NewURL{LongURL: longURL, UserID: other}},
+		{name: "другой пользователь с паролем", url: storage.NewURL{LongURL: longURL, UserID: other, PasswordHash: "hash"}},
+		{name: "лимит переходов", url: storage.NewURL{LongURL: longURL, UserID: user, MaxClicks: 1}},
+		{name: "первый домен", url: storage.NewURL{LongURL: longURL, UserID: user, Domain: "a.example.com"}},
+		{name: "второй домен", url: storage.NewURL{LongURL: longURL, UserID: user, Domain: "b.example.com"}},
+		{name: "UTM", url: storage.NewURL{LongURL: longURL, UserID: user, UTM: storage.UTM{Source: "newsletter"}}},
+		{name: "правила", url: storage.NewURL{LongURL: longURL, UserID: user, Rules: []storage.Rule{{Country: "DE", URL: longURL + "/de"}}}},
+	}
+
+	for _, item := range tests {
+		shortUID, err := st.AddURL(item.url)
+		if !errors.Is(err, item.err) {
+			t.Fatalf("%s: error should be %v but received %v", item.name, item.err, err)
+		}
+
+		if err != nil {
+			if shortUID != plain {
+				t.Errorf("%s: short UID should be %s but received %s", item.name, plain, shortUID)
+			}
+
+			continue
+		}
+
+		added = append(added, shortUID)
+
+		link, err := st.GetLink(shortUID)
+		if err != nil {
+			t.Fatalf("%s: %v", item.name, err)
+		}
+
+		if shortUID == plain || link.PasswordHash != item.url.PasswordHash || link.UTM != item.url.UTM ||
+			len(link.Rules) != len(item.url.Rules) {
+			t.Errorf("%s: new short URL should keep options %+v but received %s %+v", item.name, item.url, shortUID, link)
+		}
+
+		if item.url.MaxClicks > 0 {
+			if _, err := st.Click(shortUID, -1); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := st.GetLink(shortUID); !errors.Is(err, storage.ErrShortURLExhausted) {
+				t.Errorf("%s: error should be %v but received %v", item.name, storage.ErrShortURLExhausted, err)
+			}
+		}
+	}
+
+	// после удаления URL сокращается заново
+	if err := st.DelUrls(user, plain); err != nil {
+		t.Fatal(err)
+	}
+
+	shortUID, err := st.Add(longURL, user)
+	if err != nil || shortUID == plain {
+		t.Errorf("removed short URL %s should not be returned but received %s (%v)", plain, shortUID, err)
+	}
+
+	added = append(added, shortUID)
+}
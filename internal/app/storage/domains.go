@@ -0,0 +1,26 @@
+package storage
+
+import "strings"
+
+// DomainUID returns the short UID of the link on the domain.
+//
+// The links on the custom domains are stored by the UID prefixed
+// with the domain, so the same UID can be used on each domain.
+// The links on the default (empty) domain are stored by the UID.
+func DomainUID(domain, uid string) string {
+	if domain == "" {
+		return uid
+	}
+
+	return domain + "/" + uid
+}
+
+// SplitUID returns the domain and the UID of the link by its short UID,
+// the domain is empty for the links on the default domain.
+func SplitUID(shortUID string) (string, string) {
+	if i := strings.LastIndexByte(shortUID, '/'); i >= 0 {
+		return shortUID[:i], shortUID[i+1:]
+	}
+
+	return "", shortUID
+}
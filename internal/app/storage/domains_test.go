@@ -0,0 +1,48 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+func TestAddURLDomains(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	storeDefault := storage.NewDefault(config.New(config.Options{}))
+
+	tests := []struct {
+		domain   string
+		longURL  string
+		shortUID string
+	}{
+		{domain: "", longURL: "http://example.com/default", shortUID: "promo"},
+		{domain: "a.example.com", longURL: "http://example.com/a", shortUID: "a.example.com/promo"},
+		{domain: "b.example.com", longURL: "http://example.com/b", shortUID: "b.example.com/promo"},
+	}
+
+	for _, item := range tests {
+		shortUID, err := storeDefault.AddURL(storage.NewURL{LongURL: item.longURL, Alias: "promo", Domain: item.domain})
+		if err != nil || shortUID != item.shortUID {
+			t.Fatalf("short UID should be %s but received %s (%v)", item.shortUID, shortUID, err)
+		}
+
+		if domain, uid := storage.SplitUID(shortUID); domain != item.domain || uid != "promo" {
+			t.Errorf("domain and UID should be %q and promo but received %q and %q", item.domain, domain, uid)
+		}
+	}
+
+	// ссылки с одинаковым UID на разных доменах различаются
+	for _, item := range tests {
+		if longURL, err := storeDefault.GetURL(item.shortUID); err != nil || longURL != item.longURL {
+			t.Errorf("URL should be %s but received %s (%v)", item.longURL, longURL, err)
+		}
+	}
+
+	_, err := storeDefault.AddURL(storage.NewURL{LongURL: "http://example.com/c", Alias: "promo", Domain: "a.example.com"})
+	if !errors.Is(err, storage.ErrAliasExists) {
+		t.Errorf("error should be %v but received %v", storage.ErrAliasExists, err)
+	}
+}
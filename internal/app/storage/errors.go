@@ -4,12 +4,18 @@ import "errors"
 
 // Typical errors
 var (
-	ErrShortURLRemoved = errors.New("short URL has been removed")
-	ErrAlreadyExists   = errors.New("such an entry exists in the database")
-	ErrDBConnection    = errors.New("failed to check database connection")
-	ErrInvalidData     = errors.New("data invalid")
-	ErrUIDNotValid     = errors.New("short URL does not exist")
-	ErrUserIDNotValid  = errors.New("invalid user id")
-	ErrUserNotExists   = errors.New("user with current id does not exist")
-	ErrUserUrlsEmpty   = errors.New("shortened URLs for current user is empty")
+	ErrShortURLRemoved    = errors.New("short URL has been removed")
+	ErrShortURLExpired    = errors.New("short URL has expired")
+	ErrShortURLExhausted  = errors.New("short URL has reached the limit of clicks")
+	ErrAliasExists        = errors.New("short URL with such alias already exists")
+	ErrAlreadyExists      = errors.New("such an entry exists in the database")
+	ErrDBConnection       = errors.New("failed to check database connection")
+	ErrInvalidData        = errors.New("data invalid")
+	ErrUIDNotValid        = errors.New("short URL does not exist")
+	ErrUserIDNotValid     = errors.New("invalid user id")
+	ErrUserNotExists      = errors.New("user with current id does not exist")
+	ErrUserUrlsEmpty      = errors.New("shortened URLs for current user is empty")
+	ErrInvalidCursor      = errors.New("cursor of the page is invalid")
+	ErrInvalidListOptions = errors.New("options of the list are invalid")
+	ErrWebhookNotFound    = errors.New("webhook does not exist")
 )
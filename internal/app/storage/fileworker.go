@@ -6,6 +6,8 @@ import (
 	"encoding/gob"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -73,9 +75,45 @@ func (ds *DefaultStorage) Init() error {
 		return fmt.Errorf("failed decode data: %w", err)
 	}
 
+	ds.restoreUsers()
+
 	return nil
 }
 
+// restoreUsers restores the short URLs of the users from the loaded URLs.
+// The users without URLs are restored too, so the IDs of new users
+// don't repeat the loaded ones.
+func (ds *DefaultStorage) restoreUsers() {
+	var lastID uint
+
+	for shortUID, element := range ds.urls {
+		userID, err := strconv.Atoi(element.UserID)
+		if err != nil || userID <= 0 {
+			continue
+		}
+
+		ds.users[uint(userID)] = append(ds.users[uint(userID)], shortUID)
+
+		if uint(userID) > lastID {
+			lastID = uint(userID)
+		}
+	}
+
+	for userID := uint(1); userID <= lastID; userID++ {
+		shortUIDs, ok := ds.users[userID]
+		if !ok {
+			ds.users[userID] = make([]string, 0)
+
+			continue
+		}
+
+		// the URLs of the user are kept in the order of creation.
+		sort.Slice(shortUIDs, func(i, j int) bool {
+			return ds.urls[shortUIDs[i]].CreatedAt.Before(ds.urls[shortUIDs[j]].CreatedAt)
+		})
+	}
+}
+
 // Ping is a stub method for interface implementation Storage.
 func (ds *DefaultStorage) Ping() error {
 	return nil
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/storage/models"
+)
+
+// SetHealth performs saving the result of the check
+// of the destination of the short URL in default storage.
+func (ds *DefaultStorage) SetHealth(shortUID string, health Health) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	element, ok := ds.urls[shortUID]
+	if !ok {
+		return ErrUIDNotValid
+	}
+
+	element.Health = &health
+
+	return nil
+}
+
+// LinksToCheck returns at most limit short URLs from default storage
+// whose destination has not been checked since checkedBefore.
+//
+// Only the resolvable short URLs are returned, the unchecked ones first
+// and then the ones checked long ago.
+func (ds *DefaultStorage) LinksToCheck(checkedBefore time.Time, limit int) ([]HealthTarget, error) {
+	type item struct {
+		target    HealthTarget
+		checkedAt time.Time
+	}
+
+	now := time.Now()
+	items := make([]item, 0)
+
+	ds.mu.RLock()
+	for shortUID, element := range ds.urls {
+		if !resolvable(element, now) {
+			continue
+		}
+
+		var checkedAt time.Time
+
+		if element.Health != nil {
+			if !element.Health.CheckedAt.Before(checkedBefore) {
+				continue
+			}
+
+			checkedAt = element.Health.CheckedAt
+		}
+
+		items = append(items, item{
+			target:    HealthTarget{ShortUID: shortUID, LongURL: element.LongURL},
+			checkedAt: checkedAt,
+		})
+	}
+	ds.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].checkedAt.Equal(items[j].checkedAt) {
+			return items[i].target.ShortUID < items[j].target.ShortUID
+		}
+
+		return items[i].checkedAt.Before(items[j].checkedAt)
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	targets := make([]HealthTarget, 0, len(items))
+
+	for _, item := range items {
+		targets = append(targets, item.target)
+	}
+
+	return targets, nil
+}
+
+// SetHealth performs saving the result of the check
+// of the destination of the short URL in DB.
+func (d *DB) SetHealth(shortUID string, health Health) error {
+	checkedAt := health.CheckedAt
+
+	res := d.db.Model(&models.Urls{}).Where("short_uid = ?", shortUID).
+		Select("health_checked_at", "health_status_code", "health_latency_ms", "health_error", "health_broken").
+		Updates(models.Urls{Health: models.Health{
+			CheckedAt:  &checkedAt,
+			StatusCode: health.StatusCode,
+			LatencyMS:  health.LatencyMS,
+			Error:      health.Error,
+			Broken:     health.Broken,
+		}})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrUIDNotValid
+	}
+
+	return nil
+}
+
+// LinksToCheck returns at most limit short URLs from DB
+// whose destination has not been checked since checkedBefore.
+//
+// Only the resolvable short URLs are returned, the unchecked ones first
+// and then the ones checked long ago.
+func (d *DB) LinksToCheck(checkedBefore time.Time, limit int) ([]HealthTarget, error) {
+	var urls []models.Urls
+
+	res := d.db.Select("short_uid", "long_url").
+		Where("removed = ? AND (expires_at IS NULL OR expires_at > ?)", false, time.Now()).
+		Where("max_clicks = 0 OR statistics < max_clicks").
+		Where("health_checked_at IS NULL OR health_checked_at < ?", checkedBefore).
+		Order("health_checked_at ASC NULLS FIRST, short_uid").Limit(limit).Find(&urls)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	targets := make([]HealthTarget, 0, len(urls))
+
+	for _, url := range urls {
+		targets = append(targets, HealthTarget{ShortUID: url.ShortUID, LongURL: url.LongURL})
+	}
+
+	return targets, nil
+}
+
+// resolvable returns true if the short URL is redirected at the moment.
+func resolvable(element *URLElement, now time.Time) bool {
+	return !element.Removed && (element.ExpiresAt == nil || now.Before(*element.ExpiresAt)) &&
+		!exhausted(element.Statistics, element.MaxClicks)
+}
+
+// copyHealth returns a copy of the result of the check, nil for the unchecked URL.
+func copyHealth(health *Health) *Health {
+	if health == nil {
+		return nil
+	}
+
+	out := *health
+
+	return &out
+}
+
+// health converts the stored result of the check, nil for the unchecked URL.
+func health(item models.Health) *Health {
+	if item.CheckedAt == nil {
+		return nil
+	}
+
+	return &Health{
+		CheckedAt:  *item.CheckedAt,
+		Error:      item.Error,
+		StatusCode: item.StatusCode,
+		LatencyMS:  item.LatencyMS,
+		Broken:     item.Broken,
+	}
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"strconv"
+
+	"github.com/alaleks/shortener/internal/app/storage/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetLabels performs changing the tags and the folder of URL of the user
+// in default storage.
+func (ds *DefaultStorage) SetLabels(userID, shortUID string, update LabelsUpdate) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	element, ok := ds.urls[shortUID]
+	if !ok || !ds.owns(userID, shortUID) {
+		return ErrUIDNotValid
+	}
+
+	if update.Tags != nil {
+		element.Tags = append([]string(nil), (*update.Tags)...)
+	}
+
+	if update.Folder != nil {
+		element.Folder = *update.Folder
+	}
+
+	return nil
+}
+
+// StatUrlsUser returns the number of URLs of the user matching
+// the filters of the options and the sum of their clicks.
+func (ds *DefaultStorage) StatUrlsUser(userID string, opts ListOptions) (URLsStatistics, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return URLsStatistics{}, ErrUserIDNotValid
+	}
+
+	opts, err = opts.normalize()
+	if err != nil {
+		return URLsStatistics{}, err
+	}
+
+	var stat URLsStatistics
+
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	for _, shortUID := range ds.users[uint(uid)] {
+		if element, ok := ds.urls[shortUID]; ok && opts.match(element) {
+			stat.URLs++
+			stat.Clicks += element.Statistics
+		}
+	}
+
+	return stat, nil
+}
+
+// owns returns true if URL is added by the user, it's called under the lock.
+func (ds *DefaultStorage) owns(userID, shortUID string) bool {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return false
+	}
+
+	for _, v := range ds.users[uint(uid)] {
+		if v == shortUID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetLabels performs changing the tags and the folder of URL of the user in DB.
+//
+// The tags and the folder are created if they don't exist.
+func (d *DB) SetLabels(userID, shortUID string, update LabelsUpdate) error {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return ErrUserIDNotValid
+	}
+
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("short_uid = ? AND uid = ?", shortUID, uid).Limit(1).Find(&models.Urls{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrUIDNotValid
+		}
+
+		return setLabels(tx, uint(uid), shortUID, update)
+	})
+}
+
+// StatUrlsUser returns the number of URLs of the user matching
+// the filters of the options and the sum of their clicks.
+func (d *DB) StatUrlsUser(userID string, opts ListOptions) (URLsStatistics, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return URLsStatistics{}, ErrUserIDNotValid
+	}
+
+	opts, err = opts.normalize()
+	if err != nil {
+		return URLsStatistics{}, err
+	}
+
+	var stat URLsStatistics
+
+	res := d.db.Model(&models.Urls{}).Scopes(listFilter(uint(uid), opts)).
+		Select("COUNT(*) AS urls, COALESCE(SUM(statistics), 0) AS clicks").Scan(&stat)
+
+	return stat, res.Error
+}
+
+// setLabels replaces the tags of URL and sets its folder in the transaction.
+func setLabels(tx *gorm.DB, uid uint, shortUID string, update LabelsUpdate) error {
+	if update.Tags != nil {
+		if res := tx.Where("short_uid = ?", shortUID).Delete(&models.URLTags{}); res.Error != nil {
+			return res.Error
+		}
+
+		if len(*update.Tags) > 0 {
+			tags := make([]models.Tags, 0, len(*update.Tags))
+
+			for _, name := range *update.Tags {
+				tags = append(tags, models.Tags{UID: uid, Name: name})
+			}
+
+			if res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags); res.Error != nil {
+				return res.Error
+			}
+
+			res := tx.Exec("INSERT INTO url_tags (short_uid, tag_id) SELECT ?, id FROM tags WHERE uid = ? AND name IN ?",
+				shortUID, uid, *update.Tags)
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+	}
+
+	if update.Folder == nil {
+		return nil
+	}
+
+	var folderID *uint
+
+	if *update.Folder != "" {
+		folder := models.Folders{UID: uid, Name: *update.Folder}
+
+		res := tx.Where(folder).FirstOrCreate(&folder)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		folderID = &folder.ID
+	}
+
+	return tx.Model(&models.Urls{}).Where("short_uid = ?", shortUID).Update("folder_id", folderID).Error
+}
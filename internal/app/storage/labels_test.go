@@ -0,0 +1,72 @@
+package storage_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	conf := config.New(config.Options{})
+	storeDefault := storage.NewDefault(conf)
+	userID := "1"
+
+	shortURL, err := storeDefault.AddURL(storage.NewURL{
+		LongURL: "http://github.com/alaleks/shortener",
+		UserID:  userID,
+		Tags:    []string{"go"},
+		Folder:  "spring",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shortUID := shortURL[strings.LastIndex(shortURL, "/")+1:]
+	storeDefault.Update(shortUID)
+
+	otherURL, _ := storeDefault.Add("http://go.dev/doc", userID)
+	otherUID := otherURL[strings.LastIndex(otherURL, "/")+1:]
+	tags := []string{"go", "docs"}
+
+	if err := storeDefault.SetLabels(userID, otherUID, storage.LabelsUpdate{Tags: &tags}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := storeDefault.SetLabels("2", otherUID, storage.LabelsUpdate{Tags: &tags}); !errors.Is(err, storage.ErrUIDNotValid) {
+		t.Errorf("error should be %v but received %v", storage.ErrUIDNotValid, err)
+	}
+
+	tests := []struct {
+		name   string
+		opts   storage.ListOptions
+		urls   int
+		clicks uint
+	}{
+		{name: "тег go", opts: storage.ListOptions{Tag: "go"}, urls: 2, clicks: 1},
+		{name: "тег docs", opts: storage.ListOptions{Tag: "docs"}, urls: 1, clicks: 0},
+		{name: "папка", opts: storage.ListOptions{Folder: "spring"}, urls: 1, clicks: 1},
+		{name: "неизвестная папка", opts: storage.ListOptions{Folder: "autumn"}, urls: 0, clicks: 0},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			stat, err := storeDefault.StatUrlsUser(userID, item.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if stat.URLs != item.urls || stat.Clicks != item.clicks {
+				t.Errorf("statistics should be %d URLs and %d clicks but received %+v",
+					item.urls, item.clicks, stat)
+			}
+		})
+	}
+}
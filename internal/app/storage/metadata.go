@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/storage/models"
+)
+
+// SetMetadata performs saving the metadata of the destination page
+// of the short URL in default storage.
+func (ds *DefaultStorage) SetMetadata(shortUID string, metadata Metadata) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	element, ok := ds.urls[shortUID]
+	if !ok {
+		return ErrUIDNotValid
+	}
+
+	element.Metadata = &metadata
+
+	return nil
+}
+
+// SetMetadata performs saving the metadata of the destination page
+// of the short URL in DB.
+func (d *DB) SetMetadata(shortUID string, metadata Metadata) error {
+	fetchedAt := metadata.FetchedAt
+
+	res := d.db.Model(&models.Urls{}).Where("short_uid = ?", shortUID).
+		Select("meta_fetched_at", "meta_title", "meta_description", "meta_image", "meta_favicon").
+		Updates(models.Urls{Metadata: models.Metadata{
+			FetchedAt:   &fetchedAt,
+			Title:       metadata.Title,
+			Description: metadata.Description,
+			Image:       metadata.Image,
+			Favicon:     metadata.Favicon,
+		}})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrUIDNotValid
+	}
+
+	return nil
+}
+
+// LinksToFetch returns at most limit short URLs from default storage
+// created after createdAfter whose metadata has not been fetched.
+//
+// Only the resolvable short URLs are returned, the oldest ones first.
+func (ds *DefaultStorage) LinksToFetch(createdAfter time.Time, limit int) ([]HealthTarget, error) {
+	type item struct {
+		target    HealthTarget
+		createdAt time.Time
+	}
+
+	now := time.Now()
+	items := make([]item, 0)
+
+	ds.mu.RLock()
+	for shortUID, element := range ds.urls {
+		if element.Metadata != nil || !element.CreatedAt.After(createdAfter) || !resolvable(element, now) {
+			continue
+		}
+
+		items = append(items, item{
+			target:    HealthTarget{ShortUID: shortUID, LongURL: element.LongURL},
+			createdAt: element.CreatedAt,
+		})
+	}
+	ds.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].createdAt.Equal(items[j].createdAt) {
+			return items[i].target.ShortUID < items[j].target.ShortUID
+		}
+
+		return items[i].createdAt.Before(items[j].createdAt)
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	targets := make([]HealthTarget, 0, len(items))
+
+	for _, item := range items {
+		targets = append(targets, item.target)
+	}
+
+	return targets, nil
+}
+
+// LinksToFetch returns at most limit short URLs from DB
+// created after createdAfter whose metadata has not been fetched.
+//
+// Only the resolvable short URLs are returned, the oldest ones first.
+func (d *DB) LinksToFetch(createdAfter time.Time, limit int) ([]HealthTarget, error) {
+	var urls []models.Urls
+
+	res := d.db.Select("short_uid", "long_url").
+		Where("removed = ? AND (expires_at IS NULL OR expires_at > ?)", false, time.Now()).
+		Where("max_clicks = 0 OR statistics < max_clicks").
+		Where("meta_fetched_at IS NULL AND created_at > ?", createdAfter).
+		Order("created_at, short_uid").Limit(limit).Find(&urls)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	targets := make([]HealthTarget, 0, len(urls))
+
+	for _, url := range urls {
+		targets = append(targets, HealthTarget{ShortUID: url.ShortUID, LongURL: url.LongURL})
+	}
+
+	return targets, nil
+}
+
+// copyMetadata returns a copy of the metadata, nil if it's not fetched.
+func copyMetadata(metadata *Metadata) *Metadata {
+	if metadata == nil {
+		return nil
+	}
+
+	out := *metadata
+
+	return &out
+}
+
+// metadata converts the stored metadata, nil if it's not fetched.
+func metadata(item models.Metadata) *Metadata {
+	if item.FetchedAt == nil {
+		return nil
+	}
+
+	return &Metadata{
+		FetchedAt:   *item.FetchedAt,
+		Title:       item.Title,
+		Description: item.Description,
+		Image:       item.Image,
+		Favicon:     item.Favicon,
+	}
+}
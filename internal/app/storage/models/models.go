@@ -16,23 +16,190 @@ type Users struct {
 
 // Urls represents the data model of a specific shortened URL.
 type Urls struct {
-	CreatedAt     time.Time `gorm:"default:NOW()"`
-	ShortUID      string    `gorm:"primaryKey"`
+	CreatedAt     time.Time `gorm:"default:NOW();index:idx_urls_user_created,priority:2"`
+	ShortUID      string    `gorm:"primaryKey;index:idx_urls_user_created,priority:3;index:idx_urls_user_clicks,priority:3"`
 	CorrelationID string
-	LongURL       string `gorm:"unique;index"`
-	Statistics    uint
-	UID           uint
-	Removed       bool
+	LongURL       string     `gorm:"index;uniqueIndex:idx_urls_user_plain,priority:2,where:plain AND NOT removed"`
+	Statistics    uint       `gorm:"index:idx_urls_user_clicks,priority:2"`
+	UID           uint       `gorm:"index:idx_urls_user_created,priority:1;index:idx_urls_user_clicks,priority:1;uniqueIndex:idx_urls_user_plain,priority:1"`
+	ExpiresAt     *time.Time `gorm:"index:idx_urls_expiry,priority:2"`
+	// ExpiryNotified is set when the event of the expiry has been emitted.
+	ExpiryNotified bool  `gorm:"index:idx_urls_expiry,priority:1"`
+	FolderID       *uint `gorm:"index"`
+	UTM            UTM   `gorm:"embedded;embeddedPrefix:utm_"`
+	ForwardQuery   bool
+	// Rules are the targeting rules of the redirect in JSON.
+	Rules *string `gorm:"type:jsonb"`
+	// PasswordHash is the bcrypt hash of the password of the protected URL.
+	PasswordHash string
+	// MaxClicks is the limit of redirects, zero means no limit.
+	MaxClicks uint
+	// Plain is set for the URL shortened without options, such URLs
+	// are unique for the user while they aren't removed.
+	Plain bool
+	// Health is the result of the last check of the destination.
+	Health Health `gorm:"embedded;embeddedPrefix:health_"`
+	// Metadata is the metadata of the destination page.
+	Metadata Metadata `gorm:"embedded;embeddedPrefix:meta_"`
+	Removed  bool
+}
+
+// Metadata represents the metadata of the destination page
+// of a shortened URL, FetchedAt is nil until it's fetched.
+type Metadata struct {
+	FetchedAt   *time.Time
+	Title       string
+	Description string
+	Image       string
+	Favicon     string
+}
+
+// Health represents the result of the check of the destination
+// of a shortened URL, CheckedAt is nil for the unchecked URL.
+type Health struct {
+	CheckedAt  *time.Time `gorm:"index"`
+	StatusCode int
+	LatencyMS  int64
+	Error      string
+	Broken     bool `gorm:"index"`
+}
+
+// Variants represents the data model of a weighted destination
+// of a shortened URL for A/B split redirects.
+type Variants struct {
+	ShortUID string `gorm:"primaryKey"`
+	Position int    `gorm:"primaryKey;autoIncrement:false"`
+	URL      string
+	Weight   int
+	Clicks   uint
+}
+
+// UTM represents the UTM parameters of a shortened URL.
+type UTM struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Term     string
+	Content  string
+}
+
+// Tags represents the data model of a tag of a user.
+type Tags struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement"`
+	UID  uint   `gorm:"uniqueIndex:idx_tags_user_name"`
+	Name string `gorm:"uniqueIndex:idx_tags_user_name"`
+}
+
+// URLTags represents the many-to-many relation of shortened URLs and tags.
+type URLTags struct {
+	ShortUID string `gorm:"primaryKey"`
+	TagID    uint   `gorm:"primaryKey;index"`
+}
+
+// Folders represents the data model of a folder (campaign) of a user.
+type Folders struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement"`
+	UID  uint   `gorm:"uniqueIndex:idx_folders_user_name"`
+	Name string `gorm:"uniqueIndex:idx_folders_user_name"`
+}
+
+// Webhooks represents the data model of a webhook endpoint of a user.
+type Webhooks struct {
+	CreatedAt time.Time `gorm:"default:NOW()"`
+	ID        string    `gorm:"primaryKey"`
+	UID       uint      `gorm:"index"`
+	URL       string
+	Secret    string
+	// Events are the subscribed events separated by commas.
+	Events         string
+	ClickThreshold uint
+}
+
+// WebhookDeliveries represents the data model of a delivery of the event
+// to a webhook, the pending deliveries are the outbox.
+type WebhookDeliveries struct {
+	CreatedAt     time.Time `gorm:"index:idx_deliveries_webhook,priority:2"`
+	NextAttemptAt time.Time `gorm:"index:idx_deliveries_due,priority:2"`
+	DeliveredAt   *time.Time
+	ID            string `gorm:"primaryKey"`
+	WebhookID     string `gorm:"index:idx_deliveries_webhook,priority:1"`
+	Event         string
+	// Payload is the signed body of the delivery, it's stored as text
+	// because jsonb doesn't keep the bytes.
+	Payload      string
+	Status       string `gorm:"index:idx_deliveries_due,priority:1"`
+	Attempts     int
+	ResponseCode int
+	Error        string
+}
+
+// uniqueLongURL is the constraint of the previous schema
+// which made the original URLs unique for all users.
+const uniqueLongURL = "urls_long_url_key"
+
+// foreignKeys are the foreign keys of the tables added after the auto-migration.
+var foreignKeys = []struct {
+	model      any
+	table      string
+	name       string
+	definition string
+}{
+	{model: &Urls{}, table: "urls", name: "urls_uid_fkey", definition: "FOREIGN KEY(uid) REFERENCES users(uid)"},
+	{
+		model: &URLTags{}, table: "url_tags", name: "url_tags_short_uid_fkey",
+		definition: "FOREIGN KEY(short_uid) REFERENCES urls(short_uid) ON DELETE CASCADE",
+	},
+	{
+		model: &URLTags{}, table: "url_tags", name: "url_tags_tag_id_fkey",
+		definition: "FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE",
+	},
+	{
+		model: &Urls{}, table: "urls", name: "urls_folder_id_fkey",
+		definition: "FOREIGN KEY(folder_id) REFERENCES folders(id) ON DELETE SET NULL",
+	},
+	{
+		model: &Variants{}, table: "variants", name: "variants_short_uid_fkey",
+		definition: "FOREIGN KEY(short_uid) REFERENCES urls(short_uid) ON DELETE CASCADE",
+	},
+	{
+		model: &Webhooks{}, table: "webhooks", name: "webhooks_uid_fkey",
+		definition: "FOREIGN KEY(uid) REFERENCES users(uid) ON DELETE CASCADE",
+	},
+	{
+		model: &WebhookDeliveries{}, table: "webhook_deliveries", name: "webhook_deliveries_webhook_id_fkey",
+		definition: "FOREIGN KEY(webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE",
+	},
 }
 
 // Migrate starts auto-migration of models in database.
 func Migrate(sqlDB *gorm.DB) error {
-	err := sqlDB.AutoMigrate(&Users{}, &Urls{})
+	// the URLs were unique for all users, the constraint is replaced
+	// with the unique index of the plain URLs of the user.
+	legacy := sqlDB.Migrator().HasConstraint(&Urls{}, uniqueLongURL)
+	if legacy {
+		sqlDB.Exec("ALTER TABLE urls DROP CONSTRAINT IF EXISTS " + uniqueLongURL + ";")
+	}
+
+	err := sqlDB.AutoMigrate(&Users{}, &Urls{}, &Tags{}, &URLTags{}, &Folders{}, &Variants{},
+		&Webhooks{}, &WebhookDeliveries{})
 	if err != nil {
 		err = fmt.Errorf("error automigrate: %w", err)
 	}
 
-	sqlDB.Exec("ALTER TABLE urls ADD FOREIGN KEY(uid) REFERENCES users(uid);")
+	// the foreign keys are named as postgres names them by default,
+	// so the keys added by the previous versions are not added again.
+	for _, key := range foreignKeys {
+		if !sqlDB.Migrator().HasConstraint(key.model, key.name) {
+			sqlDB.Exec("ALTER TABLE " + key.table + " ADD CONSTRAINT " + key.name + " " + key.definition + ";")
+		}
+	}
+
+	if legacy && err == nil {
+		sqlDB.Exec(`UPDATE urls SET plain = true WHERE password_hash = '' AND max_clicks = 0
+			AND expires_at IS NULL AND rules IS NULL AND NOT forward_query AND short_uid NOT LIKE '%/%'
+			AND utm_source = '' AND utm_medium = '' AND utm_campaign = '' AND utm_term = '' AND utm_content = ''
+			AND NOT EXISTS (SELECT 1 FROM variants WHERE variants.short_uid = urls.short_uid);`)
+	}
 
 	return err
 }
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+	cursorSeparator  = ":"
+)
+
+// Fields for sorting the list of the shortened URLs.
+const (
+	SortCreatedAt = "created_at"
+	SortClicks    = "clicks"
+)
+
+// Statuses for filtering the list of the shortened URLs.
+const (
+	StatusActive  = "active"
+	StatusRemoved = "removed"
+	StatusAll     = "all"
+)
+
+// cursor represents the position in the list of the shortened URLs
+// ordered by the sort key and short UID.
+type cursor struct {
+	sort     string
+	shortUID string
+	key      int64
+}
+
+// encodeCursor returns the opaque cursor string.
+func encodeCursor(sort string, key int64, shortUID string) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(sort + cursorSeparator + strconv.FormatInt(key, 10) + cursorSeparator + shortUID))
+}
+
+// decodeCursor parses the opaque cursor string,
+// the empty string is decoded into a zero cursor.
+//
+// The cursor is valid only for the sort field with which it was created.
+func decodeCursor(value, sort string) (cursor, error) {
+	if value == "" {
+		return cursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), cursorSeparator, 3)
+	if len(parts) != 3 || parts[0] != sort || parts[2] == "" {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	key, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	return cursor{sort: sort, key: key, shortUID: parts[2]}, nil
+}
+
+// isZero returns true if the cursor points to the beginning of the list.
+func (c cursor) isZero() bool {
+	return c.shortUID == ""
+}
+
+// after returns true if the position is after the cursor
+// in the list sorted in the given direction.
+func (c cursor) after(key int64, shortUID string, desc bool) bool {
+	if c.isZero() {
+		return true
+	}
+
+	if key == c.key {
+		return shortUID > c.shortUID != desc
+	}
+
+	return key > c.key != desc
+}
+
+// createdAt returns the creation time stored in the cursor.
+func (c cursor) createdAt() time.Time {
+	return time.Unix(0, c.key)
+}
+
+// pageLimit returns the correct limit of the page.
+func pageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return defaultPageLimit
+	case limit > maxPageLimit:
+		return maxPageLimit
+	default:
+		return limit
+	}
+}
+
+// normalize checks the options and sets the default values.
+func (opts ListOptions) normalize() (ListOptions, error) {
+	switch opts.Sort {
+	case "":
+		opts.Sort = SortCreatedAt
+	case SortCreatedAt, SortClicks:
+	default:
+		return opts, ErrInvalidListOptions
+	}
+
+	switch opts.Status {
+	case "":
+		opts.Status = StatusActive
+	case StatusActive, StatusRemoved, StatusAll:
+	default:
+		return opts, ErrInvalidListOptions
+	}
+
+	if !opts.CreatedFrom.IsZero() && !opts.CreatedTo.IsZero() && !opts.CreatedFrom.Before(opts.CreatedTo) {
+		return opts, ErrInvalidListOptions
+	}
+
+	opts.Domain = strings.ToLower(strings.TrimPrefix(opts.Domain, "."))
+
+	return opts, nil
+}
+
+// match returns true if the URL satisfies the filters of the options,
+// the cursor is not taken into account.
+func (opts ListOptions) match(element *URLElement) bool {
+	switch {
+	case opts.Status == StatusActive && element.Removed,
+		opts.Status == StatusRemoved && !element.Removed,
+		!opts.CreatedFrom.IsZero() && element.CreatedAt.Before(opts.CreatedFrom),
+		!opts.CreatedTo.IsZero() && !element.CreatedAt.Before(opts.CreatedTo),
+		opts.Domain != "" && !matchDomain(element.LongURL, opts.Domain),
+		opts.Folder != "" && element.Folder != opts.Folder,
+		opts.Broken && (element.Health == nil || !element.Health.Broken):
+		return false
+	case opts.Tag == "":
+		return true
+	}
+
+	for _, tag := range element.Tags {
+		if tag == opts.Tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortKey returns the value of the sort field of the URL.
+func (opts ListOptions) sortKey(element *URLElement) int64 {
+	if opts.Sort == SortClicks {
+		return int64(element.Statistics)
+	}
+
+	return element.CreatedAt.UnixNano()
+}
+
+// matchDomain returns true if the host of the URL is the domain or its subdomain.
+func matchDomain(longURL, domain string) bool {
+	uri, err := url.Parse(longURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(uri.Hostname())
+
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// domainPattern returns the POSIX regular expression matching the URLs
+// whose host is the domain or its subdomain.
+func domainPattern(domain string) string {
+	return `^[a-z][a-z0-9+.-]*://([^/?#@]*@)?([^/?#@]*\.)?` +
+		regexp.QuoteMeta(domain) + `(:[0-9]+)?([/?#]|$)`
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/alaleks/shortener/internal/app/storage/models"
+)
+
+// SetRules performs replacing the targeting rules of URL of the user
+// in default storage, URL with the rules is no longer plain.
+func (ds *DefaultStorage) SetRules(userID, shortUID string, rules []Rule) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	element, ok := ds.urls[shortUID]
+	if !ok || !ds.owns(userID, shortUID) {
+		return ErrUIDNotValid
+	}
+
+	element.Rules = append([]Rule(nil), rules...)
+	element.Plain = element.Plain && len(rules) == 0
+
+	return nil
+}
+
+// SetRules performs replacing the targeting rules of URL of the user in DB,
+// URL with the rules is no longer plain.
+func (d *DB) SetRules(userID, shortUID string, rules []Rule) error {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return ErrUserIDNotValid
+	}
+
+	update := map[string]any{"rules": marshalRules(rules)}
+	if len(rules) > 0 {
+		update["plain"] = false
+	}
+
+	res := d.db.Model(&models.Urls{}).Where("short_uid = ? AND uid = ?", shortUID, uid).Updates(update)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrUIDNotValid
+	}
+
+	return nil
+}
+
+// marshalRules returns the rules in JSON or nil if there are no rules.
+func marshalRules(rules []Rule) *string {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil
+	}
+
+	out := string(data)
+
+	return &out
+}
+
+// unmarshalRules returns the rules stored in JSON.
+func unmarshalRules(data *string) []Rule {
+	if data == nil {
+		return nil
+	}
+
+	var rules []Rule
+
+	if err := json.Unmarshal([]byte(*data), &rules); err != nil {
+		return nil
+	}
+
+	return rules
+}
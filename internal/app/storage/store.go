@@ -2,6 +2,9 @@
 package storage
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/alaleks/shortener/internal/app/config"
 	"github.com/alaleks/shortener/internal/app/logger"
 	"github.com/alaleks/shortener/internal/app/storage/pool"
@@ -18,11 +21,264 @@ type (
 
 	// Statistics represents a data model for getting statistics
 	// for a specific short link.
+	//
+	// ShortURL is built by the service from the domain of the link.
 	Statistics struct {
-		ShortURL  string `json:"shorturl"`
-		LongURL   string `json:"longurl"`
-		CreatedAt string `json:"createdAt"`
-		Usage     uint   `json:"usage"`
+		ShortURL  string    `json:"shorturl"`
+		LongURL   string    `json:"longurl"`
+		CreatedAt string    `json:"createdAt"`
+		Variants  []Variant `json:"variants,omitempty"`
+		Usage     uint      `json:"usage"`
+		Protected bool      `json:"protected,omitempty"`
+		Health    *Health   `json:"health,omitempty"`
+		Metadata  *Metadata `json:"metadata,omitempty"`
+	}
+
+	// Metadata represents the metadata of the destination page
+	// of the short URL: the title, the description, the Open Graph
+	// image and the favicon. The URLs are absolute.
+	Metadata struct {
+		FetchedAt   time.Time `json:"fetched_at"`
+		Title       string    `json:"title,omitempty"`
+		Description string    `json:"description,omitempty"`
+		Image       string    `json:"image,omitempty"`
+		Favicon     string    `json:"favicon,omitempty"`
+	}
+
+	// Health represents the result of the last check of the destination
+	// of the short URL. The destination is broken if it responded
+	// with 4xx/5xx status code or could not be reached.
+	Health struct {
+		CheckedAt time.Time `json:"checked_at"`
+		// Error describes the failure of the request, e.g. DNS error.
+		Error      string `json:"error,omitempty"`
+		StatusCode int    `json:"status_code,omitempty"`
+		// LatencyMS is the response time in milliseconds.
+		LatencyMS int64 `json:"latency_ms"`
+		Broken    bool  `json:"broken"`
+	}
+
+	// HealthTarget represents the short URL whose destination is to be checked
+	// or whose destination page is to be fetched.
+	HealthTarget struct {
+		ShortUID string
+		LongURL  string
+	}
+
+	// Webhook represents the endpoint of a user which receives
+	// the signed JSON POSTs on the events of the short URLs.
+	Webhook struct {
+		CreatedAt time.Time `json:"created_at"`
+		ID        string    `json:"id"`
+		UserID    string    `json:"-"`
+		URL       string    `json:"url"`
+		// Secret signs the deliveries, it's returned only on creation.
+		Secret string   `json:"secret,omitempty"`
+		Events []string `json:"events"`
+		// ClickThreshold is the number of clicks of the short URL
+		// on which the click event is sent.
+		ClickThreshold uint `json:"click_threshold,omitempty"`
+	}
+
+	// Delivery represents the event sent to the webhook. The pending
+	// deliveries are the outbox, they are attempted until the webhook
+	// accepts them or the attempts run out.
+	Delivery struct {
+		CreatedAt time.Time `json:"created_at"`
+		// NextAttemptAt is the time of the next attempt of the pending delivery.
+		NextAttemptAt time.Time  `json:"next_attempt_at"`
+		DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+		ID            string     `json:"id"`
+		WebhookID     string     `json:"webhook_id"`
+		Event         string     `json:"event"`
+		// Payload is the signed body of the delivery.
+		Payload json.RawMessage `json:"payload"`
+		// Status is DeliveryPending, DeliveryDelivered or DeliveryFailed.
+		Status   string `json:"status"`
+		Attempts int    `json:"attempts"`
+		// ResponseCode and Error describe the last attempt.
+		ResponseCode int    `json:"response_code,omitempty"`
+		Error        string `json:"error,omitempty"`
+	}
+
+	// ExpiredLink represents the short URL which has expired.
+	ExpiredLink struct {
+		ExpiresAt time.Time
+		ShortUID  string
+		LongURL   string
+		// UserID is the owner of the short URL, empty for anonymous one.
+		UserID string
+	}
+
+	// Variant represents the weighted destination of the short URL
+	// for A/B split redirects with the number of redirects to it.
+	Variant struct {
+		URL    string `json:"url"`
+		Weight int    `json:"weight"`
+		Clicks uint   `json:"clicks"`
+	}
+
+	// UserURL represents a data model of the shortened URL of a user.
+	//
+	// ShortURL is built by the service from ShortUID.
+	UserURL struct {
+		CreatedAt     time.Time  `json:"created_at"`
+		ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+		ShortUID      string     `json:"-"`
+		ShortURL      string     `json:"short_url"`
+		LongURL       string     `json:"original_url"`
+		CorrelationID string     `json:"correlation_id,omitempty"`
+		Folder        string     `json:"folder,omitempty"`
+		Tags          []string   `json:"tags,omitempty"`
+		UTM           *UTM       `json:"utm,omitempty"`
+		ForwardQuery  bool       `json:"forward_query,omitempty"`
+		Rules         []Rule     `json:"rules,omitempty"`
+		Variants      []Variant  `json:"variants,omitempty"`
+		Protected     bool       `json:"protected,omitempty"`
+		MaxClicks     uint       `json:"max_clicks,omitempty"`
+		Health        *Health    `json:"health,omitempty"`
+		Metadata      *Metadata  `json:"metadata,omitempty"`
+		Clicks        uint       `json:"clicks"`
+		Removed       bool       `json:"removed"`
+	}
+
+	// UTM represents the UTM parameters added to the query
+	// of the original URL on redirect.
+	UTM struct {
+		Source   string `json:"utm_source,omitempty"`
+		Medium   string `json:"utm_medium,omitempty"`
+		Campaign string `json:"utm_campaign,omitempty"`
+		Term     string `json:"utm_term,omitempty"`
+		Content  string `json:"utm_content,omitempty"`
+	}
+
+	// Rule represents the targeting rule of the redirect to URL,
+	// the rule matches if all its non-empty conditions match the client.
+	Rule struct {
+		URL string `json:"url"`
+		// Device is the type of the client device: ios, android, mobile or desktop.
+		Device string `json:"device,omitempty"`
+		// Language is the most preferred language of the client, e.g. de or de-AT.
+		Language string `json:"language,omitempty"`
+		// Country is the ISO 3166-1 alpha-2 code of the client country.
+		Country string `json:"country,omitempty"`
+		// CIDRs are the networks containing the client IP address.
+		CIDRs []string `json:"cidrs,omitempty"`
+	}
+
+	// Link represents the short URL with the settings of the redirect.
+	Link struct {
+		LongURL string
+		// UserID is the owner of the short URL, empty for anonymous one.
+		UserID string
+		UTM    UTM
+		// Rules are evaluated in order before falling back to LongURL.
+		Rules []Rule
+		// Variants replace LongURL by the weighted destinations.
+		Variants []Variant
+		// PasswordHash is the hash of the password protecting the short URL.
+		PasswordHash string
+		// ForwardQuery enables passing the query of the short URL
+		// to the original URL.
+		ForwardQuery bool
+	}
+
+	// NewURL represents the URL to be shortened
+	// with the optional alias and expiry.
+	NewURL struct {
+		// ExpiresAt is the time after which the short URL is not resolved.
+		ExpiresAt *time.Time
+		LongURL   string
+		UserID    string
+		// CorrelationID is the ID of the URL in the client data.
+		CorrelationID string
+		// Alias is used as the short UID instead of the generated one.
+		Alias string
+		// Domain is the custom domain of the short URL,
+		// empty for the default domain, see DomainUID.
+		Domain string
+		// Folder is the optional folder (campaign) of the URL.
+		Folder string
+		Tags   []string
+		// UTM and ForwardQuery are the settings of the redirect.
+		UTM          UTM
+		ForwardQuery bool
+		Rules        []Rule
+		Variants     []Variant
+		// PasswordHash is the hash of the password protecting the short URL.
+		PasswordHash string
+		// MaxClicks is the limit of redirects, zero means no limit.
+		MaxClicks uint
+	}
+
+	// LabelsUpdate represents the changes of the tags and the folder of URL,
+	// nil fields are not changed. The tags are replaced entirely,
+	// the empty folder removes URL from its folder.
+	LabelsUpdate struct {
+		Tags   *[]string
+		Folder *string
+	}
+
+	// URLsStatistics represents the aggregated statistics of URLs of a user.
+	URLsStatistics struct {
+		URLs   int  `json:"urls"`
+		Clicks uint `json:"clicks"`
+	}
+
+	// BatchItem represents the URL for batch adding.
+	BatchItem struct {
+		LongURL       string
+		CorrelationID string
+	}
+
+	// BatchResult represents the result of batch adding of the item,
+	// Err is set if the item has not been added.
+	BatchResult struct {
+		Err           error
+		CorrelationID string
+		ShortUID      string
+	}
+
+	// ListOptions represents options for getting
+	// the shortened URLs of a user page by page.
+	ListOptions struct {
+		// Cursor is the position after which the page starts,
+		// an empty cursor means the first page.
+		Cursor string
+		// Limit is the maximum number of URLs on the page.
+		Limit int
+		// Sort is the field by which URLs are sorted:
+		// SortCreatedAt (by default) or SortClicks.
+		Sort string
+		// Desc reverses the sort order.
+		Desc bool
+		// CreatedFrom and CreatedTo restrict the creation time of URLs,
+		// CreatedFrom is inclusive, CreatedTo is exclusive.
+		CreatedFrom time.Time
+		CreatedTo   time.Time
+		// Domain filters URLs by the domain of the original URL
+		// including its subdomains.
+		Domain string
+		// Status filters URLs by removal:
+		// StatusActive (by default), StatusRemoved or StatusAll.
+		Status string
+		// Tag filters URLs by the attached tag.
+		Tag string
+		// Folder filters URLs by the folder (campaign).
+		Folder string
+		// Broken filters URLs whose destination was found dead
+		// by the last check.
+		Broken bool
+	}
+
+	// URLsPage represents a page of the shortened URLs of a user.
+	URLsPage struct {
+		URLs []UserURL
+		// NextCursor is the cursor of the next page,
+		// it is empty if the page is the last one.
+		NextCursor string
+		// Total is the number of URLs matching the filters on all pages.
+		Total int
 	}
 
 	// InternalStats represents a data model for getting statistics
@@ -39,6 +295,7 @@ type (
 		Consumer
 		User
 		Worker
+		Webhooks
 	}
 
 	// Worker interface is used to initialize, ping and close application's storage.
@@ -49,27 +306,60 @@ type (
 	}
 
 	// Producer interface is used adding, updating and deleting data from application's storage.
+	//
+	// The adding methods return the short UIDs of the links,
+	// the short URLs are built by the service.
 	Producer interface {
 		Add(longURL, userID string) (string, error)
 		AddBatch(longURL, userID, corID string) string
+		AddURL(url NewURL) (string, error)
+		AddMany(userID string, items []BatchItem) ([]BatchResult, error)
 		Update(uid string)
+		Click(uid string, variant int) (uint, error)
 		DelUrls(userID string, shortsUID ...string) error
+		SetLabels(userID, shortUID string, update LabelsUpdate) error
+		SetRules(userID, shortUID string, rules []Rule) error
+		SetVariants(userID, shortUID string, variants []Variant) error
+		SetHealth(shortUID string, health Health) error
+		SetMetadata(shortUID string, metadata Metadata) error
 	}
 
 	// Consumer interface is used gettings data from application's storage.
 	Consumer interface {
 		GetURL(uid string) (string, error)
+		GetLink(uid string) (Link, error)
 		GetInternalStats() (InternalStats, error)
 		Stat(uid string) (Statistics, error)
+		LinksToCheck(checkedBefore time.Time, limit int) ([]HealthTarget, error)
+		LinksToFetch(createdAfter time.Time, limit int) ([]HealthTarget, error)
 	}
 
 	// User interface is used to get user data from application's storage or create new user.
 	User interface {
 		Create() uint
-		GetUrlsUser(userID string) ([]struct {
-			ShortUID string `json:"short_url"`
-			LongURL  string `json:"original_url"`
-		}, error)
+		GetUrlsUser(userID string) ([]UserURL, error)
+		ListUrlsUser(userID string, opts ListOptions) (URLsPage, error)
+		StatUrlsUser(userID string, opts ListOptions) (URLsStatistics, error)
+	}
+
+	// Webhooks interface is used to manage the webhooks of users
+	// and the outbox of their deliveries.
+	//
+	// ClaimDeliveries returns the due pending deliveries and postpones them
+	// by the lease, so the concurrent dispatchers don't send them twice.
+	// TakeExpired returns the expired short URLs which haven't been
+	// returned before.
+	Webhooks interface {
+		AddWebhook(hook Webhook) (Webhook, error)
+		GetWebhook(id string) (Webhook, error)
+		ListWebhooks(userID string) ([]Webhook, error)
+		DelWebhook(userID, id string) error
+		AddDeliveries(deliveries ...Delivery) error
+		ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+		UpdateDelivery(delivery Delivery) error
+		ListDeliveries(webhookID string, limit int) ([]Delivery, error)
+		PruneDeliveries(before time.Time) error
+		TakeExpired(now time.Time, limit int) ([]ExpiredLink, error)
 	}
 )
 
@@ -104,3 +394,11 @@ func InitStore(conf config.Configurator, logger *logger.AppLogger) *Store {
 
 	return storeDefault
 }
+
+// plain reports whether URL is shortened without options and alias,
+// such URLs are not duplicated for the user.
+func (u NewURL) plain() bool {
+	return u.Alias == "" && u.Domain == "" && u.ExpiresAt == nil && u.Folder == "" && len(u.Tags) == 0 &&
+		u.UTM == (UTM{}) && !u.ForwardQuery && len(u.Rules) == 0 && len(u.Variants) == 0 &&
+		u.PasswordHash == "" && u.MaxClicks == 0
+}
@@ -1,15 +1,14 @@
 package storage
 
 import (
+	"sort"
 	"strconv"
 )
 
 // GetUrlsUser perfoms getting shorts URLs from default storage for current user.
-func (ds *DefaultStorage) GetUrlsUser(userID string) ([]struct {
-	ShortUID string `json:"short_url"`
-	LongURL  string `json:"original_url"`
-}, error,
-) {
+//
+// Removed URLs are also returned with the Removed flag.
+func (ds *DefaultStorage) GetUrlsUser(userID string) ([]UserURL, error) {
 	uid, err := strconv.Atoi(userID)
 	if err != nil {
 		return nil, ErrUserIDNotValid
@@ -17,27 +16,11 @@ func (ds *DefaultStorage) GetUrlsUser(userID string) ([]struct {
 
 	ds.mu.RLock()
 	uidsShortURL := ds.users[uint(uid)]
-
-	urls := make([]struct {
-		ShortUID string `json:"short_url"`
-		LongURL  string `json:"original_url"`
-	}, 0, len(uidsShortURL))
-
-	if len(uidsShortURL) == 0 {
-		ds.mu.RUnlock()
-
-		return urls, ErrUserUrlsEmpty
-	}
+	urls := make([]UserURL, 0, len(uidsShortURL))
 
 	for _, shortUID := range uidsShortURL {
-		if originalURL, err := ds.GetURL(shortUID); err == nil {
-			urls = append(urls, struct {
-				ShortUID string `json:"short_url"`
-				LongURL  string `json:"original_url"`
-			}{
-				ShortUID: ds.conf.GetBaseURL() + shortUID,
-				LongURL:  originalURL,
-			})
+		if element, ok := ds.urls[shortUID]; ok {
+			urls = append(urls, ds.userURL(shortUID, element))
 		}
 	}
 
@@ -50,6 +33,38 @@ func (ds *DefaultStorage) GetUrlsUser(userID string) ([]struct {
 	return urls, nil
 }
 
+// userURL converts the stored URL to the data model of the user URL.
+func (ds *DefaultStorage) userURL(shortUID string, element *URLElement) UserURL {
+	return UserURL{
+		CreatedAt:     element.CreatedAt,
+		ExpiresAt:     element.ExpiresAt,
+		ShortUID:      shortUID,
+		LongURL:       element.LongURL,
+		CorrelationID: element.CorrelationID,
+		Folder:        element.Folder,
+		Tags:          append([]string(nil), element.Tags...),
+		UTM:           userUTM(element.UTM),
+		ForwardQuery:  element.ForwardQuery,
+		Rules:         append([]Rule(nil), element.Rules...),
+		Variants:      append([]Variant(nil), element.Variants...),
+		Protected:     element.PasswordHash != "",
+		MaxClicks:     element.MaxClicks,
+		Health:        copyHealth(element.Health),
+		Metadata:      copyMetadata(element.Metadata),
+		Clicks:        element.Statistics,
+		Removed:       element.Removed,
+	}
+}
+
+// userUTM returns nil for URL without UTM parameters.
+func userUTM(utm UTM) *UTM {
+	if utm == (UTM{}) {
+		return nil
+	}
+
+	return &utm
+}
+
 // Create performs adding new user in DefaultStorage.
 func (ds *DefaultStorage) Create() uint {
 	ds.mu.Lock()
@@ -59,3 +74,74 @@ func (ds *DefaultStorage) Create() uint {
 
 	return uid
 }
+
+// ListUrlsUser performs getting shorts URLs from default storage
+// for current user page by page.
+//
+// URLs are filtered and sorted according to the options,
+// by default removed URLs are skipped and URLs are ordered by creation time.
+func (ds *DefaultStorage) ListUrlsUser(userID string, opts ListOptions) (URLsPage, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return URLsPage{}, ErrUserIDNotValid
+	}
+
+	opts, err = opts.normalize()
+	if err != nil {
+		return URLsPage{}, err
+	}
+
+	after, err := decodeCursor(opts.Cursor, opts.Sort)
+	if err != nil {
+		return URLsPage{}, err
+	}
+
+	type item struct {
+		url      UserURL
+		shortUID string
+		key      int64
+	}
+
+	ds.mu.RLock()
+	uidsShortURL := ds.users[uint(uid)]
+	items := make([]item, 0, len(uidsShortURL))
+	total := 0
+
+	for _, shortUID := range uidsShortURL {
+		element, ok := ds.urls[shortUID]
+		if !ok || !opts.match(element) {
+			continue
+		}
+
+		total++
+
+		if key := opts.sortKey(element); after.after(key, shortUID, opts.Desc) {
+			items = append(items, item{url: ds.userURL(shortUID, element), shortUID: shortUID, key: key})
+		}
+	}
+	ds.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].key == items[j].key {
+			return items[i].shortUID < items[j].shortUID != opts.Desc
+		}
+
+		return items[i].key < items[j].key != opts.Desc
+	})
+
+	limit := pageLimit(opts.Limit)
+	page := URLsPage{URLs: make([]UserURL, 0, limit), Total: total}
+
+	for i, item := range items {
+		if i == limit {
+			last := items[i-1]
+			page.NextCursor = encodeCursor(opts.Sort, last.key, last.shortUID)
+
+			break
+		}
+
+		page.URLs = append(page.URLs, item.url)
+	}
+
+	return page, nil
+}
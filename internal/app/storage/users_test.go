@@ -0,0 +1,151 @@
+package storage_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+func TestListUrlsUser(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	conf := config.New(config.Options{})
+	storeDefault := storage.NewDefault(conf)
+	userID := "1"
+	longURLs := []string{
+		"http://github.com/alaleks/shortener",
+		"http://go.dev/doc",
+		"http://pkg.go.dev/net/http",
+		"http://example.com/removed",
+	}
+	shortUIDs := make([]string, 0, len(longURLs))
+
+	for i, longURL := range longURLs {
+		shortURL, _ := storeDefault.Add(longURL, userID)
+		shortUID := shortURL[strings.LastIndex(shortURL, "/")+1:]
+		shortUIDs = append(shortUIDs, shortUID)
+
+		// количество переходов равно индексу URL
+		for j := 0; j < i; j++ {
+			storeDefault.Update(shortUID)
+		}
+	}
+
+	_ = storeDefault.DelUrls(userID, shortUIDs[3])
+
+	tests := []struct {
+		name  string
+		opts  storage.ListOptions
+		first string
+		err   error
+		total int
+	}{
+		{name: "все активные", opts: storage.ListOptions{}, first: longURLs[0], total: 3},
+		{
+			name:  "сортировка по переходам",
+			opts:  storage.ListOptions{Sort: storage.SortClicks, Desc: true},
+			first: longURLs[2], total: 3,
+		},
+		{
+			name:  "фильтр по домену",
+			opts:  storage.ListOptions{Domain: "go.dev"},
+			first: longURLs[1], total: 2,
+		},
+		{
+			name:  "удаленные",
+			opts:  storage.ListOptions{Status: storage.StatusRemoved},
+			first: longURLs[3], total: 1,
+		},
+		{name: "все", opts: storage.ListOptions{Status: storage.StatusAll}, first: longURLs[0], total: 4},
+		{name: "неверная сортировка", opts: storage.ListOptions{Sort: "url"}, err: storage.ErrInvalidListOptions},
+		{name: "неверный курсор", opts: storage.ListOptions{Cursor: "wrong"}, err: storage.ErrInvalidCursor},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			page, err := storeDefault.ListUrlsUser(userID, item.opts)
+			if !errors.Is(err, item.err) {
+				t.Fatalf("error should be %v but received %v", item.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if page.Total != item.total {
+				t.Errorf("total should be %d but received %d", item.total, page.Total)
+			}
+
+			if len(page.URLs) == 0 || page.URLs[0].LongURL != item.first {
+				t.Errorf("first URL should be %s but received %v", item.first, page.URLs)
+			}
+		})
+	}
+
+	// проверяем обход страниц с сортировкой по переходам
+	var (
+		opts    = storage.ListOptions{Limit: 1, Sort: storage.SortClicks, Status: storage.StatusAll}
+		visited []string
+	)
+
+	for {
+		page, err := storeDefault.ListUrlsUser(userID, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, url := range page.URLs {
+			visited = append(visited, url.LongURL)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		opts.Cursor = page.NextCursor
+	}
+
+	if strings.Join(visited, " ") != strings.Join(longURLs, " ") {
+		t.Errorf("pages should contain %v but received %v", longURLs, visited)
+	}
+}
+
+func TestGetUrlsUser(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	conf := config.New(config.Options{})
+	storeDefault := storage.NewDefault(conf)
+	userID := "1"
+
+	if _, err := storeDefault.GetUrlsUser(userID); !errors.Is(err, storage.ErrUserUrlsEmpty) {
+		t.Errorf("error should be %v but received %v", storage.ErrUserUrlsEmpty, err)
+	}
+
+	shortUID := storeDefault.AddBatch("http://github.com/alaleks/shortener", userID, "42")
+
+	storeDefault.Update(shortUID)
+	_ = storeDefault.DelUrls(userID, shortUID)
+
+	urls, err := storeDefault.GetUrlsUser(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(urls) != 1 {
+		t.Fatalf("number of URLs should be %d but received %d", 1, len(urls))
+	}
+
+	url := urls[0]
+
+	if url.ShortUID != shortUID || url.CorrelationID != "42" || url.Clicks != 1 ||
+		!url.Removed || url.CreatedAt.IsZero() {
+		t.Errorf("URL should be %s with correlation ID 42, 1 click and removed but received %+v",
+			shortUID, url)
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"strconv"
+
+	"github.com/alaleks/shortener/internal/app/storage/models"
+	"gorm.io/gorm"
+)
+
+// SetVariants performs replacing the weighted destinations of URL
+// of the user in default storage, the counters of redirects are reset.
+// URL with the variants is no longer plain.
+func (ds *DefaultStorage) SetVariants(userID, shortUID string, variants []Variant) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	element, ok := ds.urls[shortUID]
+	if !ok || !ds.owns(userID, shortUID) {
+		return ErrUIDNotValid
+	}
+
+	element.Variants = resetVariants(variants)
+	element.Plain = element.Plain && len(variants) == 0
+
+	return nil
+}
+
+// SetVariants performs replacing the weighted destinations of URL
+// of the user in DB, the counters of redirects are reset.
+// URL with the variants is no longer plain.
+func (d *DB) SetVariants(userID, shortUID string, variants []Variant) error {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return ErrUserIDNotValid
+	}
+
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("short_uid = ? AND uid = ?", shortUID, uid).Limit(1).Find(&models.Urls{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrUIDNotValid
+		}
+
+		if len(variants) > 0 {
+			if res := tx.Model(&models.Urls{}).Where("short_uid = ?", shortUID).Update("plain", false); res.Error != nil {
+				return res.Error
+			}
+		}
+
+		if res := tx.Where("short_uid = ?", shortUID).Delete(&models.Variants{}); res.Error != nil {
+			return res.Error
+		}
+
+		return setVariants(tx, shortUID, variants)
+	})
+}
+
+// variants returns the weighted destinations of URL in order.
+func (d *DB) variants(uid string) ([]Variant, error) {
+	var items []models.Variants
+
+	if res := d.db.Where("short_uid = ?", uid).Order("position").Find(&items); res.Error != nil {
+		return nil, res.Error
+	}
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	out := make([]Variant, 0, len(items))
+
+	for _, item := range items {
+		out = append(out, Variant{URL: item.URL, Weight: item.Weight, Clicks: item.Clicks})
+	}
+
+	return out, nil
+}
+
+// setVariants adds the weighted destinations of URL in the transaction.
+func setVariants(tx *gorm.DB, shortUID string, variants []Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	items := make([]models.Variants, 0, len(variants))
+
+	for i, variant := range variants {
+		items = append(items, models.Variants{ShortUID: shortUID, Position: i, URL: variant.URL, Weight: variant.Weight})
+	}
+
+	return tx.Create(&items).Error
+}
+
+// resetVariants returns the copy of the variants without redirects.
+func resetVariants(variants []Variant) []Variant {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	out := make([]Variant, 0, len(variants))
+
+	for _, variant := range variants {
+		out = append(out, Variant{URL: variant.URL, Weight: variant.Weight})
+	}
+
+	return out
+}
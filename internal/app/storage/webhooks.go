@@ -0,0 +1,546 @@
+package storage
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Statuses of the deliveries of the webhooks.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// sizeWebhookID is the length of the generated ID of the webhook.
+const sizeWebhookID = 16
+
+// AddWebhook performs adding the webhook of the user to default storage,
+// the ID and the time of creation are set by the storage.
+func (ds *DefaultStorage) AddWebhook(hook Webhook) (Webhook, error) {
+	if _, err := strconv.Atoi(hook.UserID); err != nil {
+		return Webhook{}, ErrUserIDNotValid
+	}
+
+	hook.ID = service.GenUID(sizeWebhookID)
+	hook.CreatedAt = time.Now()
+	hook.Events = append([]string(nil), hook.Events...)
+
+	ds.mu.Lock()
+	stored := hook
+	ds.webhooks[hook.ID] = &stored
+	ds.mu.Unlock()
+
+	return hook, nil
+}
+
+// GetWebhook returns the webhook by its ID from default storage.
+func (ds *DefaultStorage) GetWebhook(id string) (Webhook, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	hook, ok := ds.webhooks[id]
+	if !ok {
+		return Webhook{}, ErrWebhookNotFound
+	}
+
+	return copyWebhook(hook), nil
+}
+
+// ListWebhooks returns the webhooks of the user from default storage
+// in the order of creation.
+func (ds *DefaultStorage) ListWebhooks(userID string) ([]Webhook, error) {
+	hooks := make([]Webhook, 0)
+
+	ds.mu.RLock()
+	for _, hook := range ds.webhooks {
+		if hook.UserID == userID {
+			hooks = append(hooks, copyWebhook(hook))
+		}
+	}
+	ds.mu.RUnlock()
+
+	sort.Slice(hooks, func(i, j int) bool {
+		if hooks[i].CreatedAt.Equal(hooks[j].CreatedAt) {
+			return hooks[i].ID < hooks[j].ID
+		}
+
+		return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+	})
+
+	return hooks, nil
+}
+
+// DelWebhook performs deleting the webhook of the user
+// with its deliveries from default storage.
+func (ds *DefaultStorage) DelWebhook(userID, id string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	hook, ok := ds.webhooks[id]
+	if !ok || hook.UserID != userID {
+		return ErrWebhookNotFound
+	}
+
+	delete(ds.webhooks, id)
+
+	for deliveryID, delivery := range ds.deliveries {
+		if delivery.WebhookID == id {
+			delete(ds.deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+// AddDeliveries performs adding the deliveries to the outbox in default storage.
+func (ds *DefaultStorage) AddDeliveries(deliveries ...Delivery) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		if _, ok := ds.webhooks[delivery.WebhookID]; !ok {
+			return ErrWebhookNotFound
+		}
+	}
+
+	for _, delivery := range deliveries {
+		stored := delivery
+		ds.deliveries[delivery.ID] = &stored
+	}
+
+	return nil
+}
+
+// ClaimDeliveries returns at most limit pending deliveries from default storage
+// whose next attempt is due and postpones them by the lease.
+func (ds *DefaultStorage) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	due := make([]*Delivery, 0)
+
+	for _, delivery := range ds.deliveries {
+		if delivery.Status == DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].ID < due[j].ID
+		}
+
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	deliveries := make([]Delivery, 0, len(due))
+
+	for _, delivery := range due {
+		deliveries = append(deliveries, *delivery)
+		delivery.NextAttemptAt = now.Add(lease)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery performs saving the result of the attempt
+// of the delivery in default storage.
+func (ds *DefaultStorage) UpdateDelivery(delivery Delivery) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if _, ok := ds.deliveries[delivery.ID]; !ok {
+		return ErrWebhookNotFound
+	}
+
+	ds.deliveries[delivery.ID] = &delivery
+
+	return nil
+}
+
+// ListDeliveries returns at most limit last deliveries
+// of the webhook from default storage, the newest first.
+func (ds *DefaultStorage) ListDeliveries(webhookID string, limit int) ([]Delivery, error) {
+	deliveries := make([]Delivery, 0)
+
+	ds.mu.RLock()
+	for _, delivery := range ds.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	ds.mu.RUnlock()
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].ID > deliveries[j].ID
+		}
+
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+// PruneDeliveries performs deleting the finished deliveries
+// created before the time from default storage.
+func (ds *DefaultStorage) PruneDeliveries(before time.Time) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	for id, delivery := range ds.deliveries {
+		if delivery.Status != DeliveryPending && delivery.CreatedAt.Before(before) {
+			delete(ds.deliveries, id)
+		}
+	}
+
+	return nil
+}
+
+// TakeExpired returns at most limit short URLs from default storage
+// which have expired by now and haven't been taken before.
+// The removed short URLs are skipped.
+func (ds *DefaultStorage) TakeExpired(now time.Time, limit int) ([]ExpiredLink, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	links := make([]ExpiredLink, 0)
+
+	for shortUID, element := range ds.urls {
+		if element.Removed || element.ExpiryNotified || element.ExpiresAt == nil || element.ExpiresAt.After(now) {
+			continue
+		}
+
+		links = append(links, ExpiredLink{
+			ExpiresAt: *element.ExpiresAt,
+			ShortUID:  shortUID,
+			LongURL:   element.LongURL,
+			UserID:    element.UserID,
+		})
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].ExpiresAt.Equal(links[j].ExpiresAt) {
+			return links[i].ShortUID < links[j].ShortUID
+		}
+
+		return links[i].ExpiresAt.Before(links[j].ExpiresAt)
+	})
+
+	if len(links) > limit {
+		links = links[:limit]
+	}
+
+	for _, link := range links {
+		ds.urls[link.ShortUID].ExpiryNotified = true
+	}
+
+	return links, nil
+}
+
+// AddWebhook performs adding the webhook of the user to DB,
+// the ID and the time of creation are set by the storage.
+func (d *DB) AddWebhook(hook Webhook) (Webhook, error) {
+	uid, err := strconv.Atoi(hook.UserID)
+	if err != nil {
+		return Webhook{}, ErrUserIDNotValid
+	}
+
+	item := models.Webhooks{
+		CreatedAt:      time.Now(),
+		ID:             service.GenUID(sizeWebhookID),
+		UID:            uint(uid),
+		URL:            hook.URL,
+		Secret:         hook.Secret,
+		Events:         strings.Join(hook.Events, ","),
+		ClickThreshold: hook.ClickThreshold,
+	}
+
+	if err := d.db.Create(&item).Error; err != nil {
+		return Webhook{}, err
+	}
+
+	return webhook(item), nil
+}
+
+// GetWebhook returns the webhook by its ID from DB.
+func (d *DB) GetWebhook(id string) (Webhook, error) {
+	var item models.Webhooks
+
+	res := d.db.Where("id = ?", id).Limit(1).Find(&item)
+	if res.Error != nil {
+		return Webhook{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return Webhook{}, ErrWebhookNotFound
+	}
+
+	return webhook(item), nil
+}
+
+// ListWebhooks returns the webhooks of the user from DB
+// in the order of creation.
+func (d *DB) ListWebhooks(userID string) ([]Webhook, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, ErrUserIDNotValid
+	}
+
+	var items []models.Webhooks
+
+	if err := d.db.Where("uid = ?", uid).Order("created_at, id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	hooks := make([]Webhook, 0, len(items))
+
+	for _, item := range items {
+		hooks = append(hooks, webhook(item))
+	}
+
+	return hooks, nil
+}
+
+// DelWebhook performs deleting the webhook of the user from DB,
+// its deliveries are deleted by the foreign key.
+func (d *DB) DelWebhook(userID, id string) error {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return ErrUserIDNotValid
+	}
+
+	res := d.db.Where("id = ? AND uid = ?", id, uid).Delete(&models.Webhooks{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// AddDeliveries performs adding the deliveries to the outbox in DB.
+func (d *DB) AddDeliveries(deliveries ...Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	items := make([]models.WebhookDeliveries, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		items = append(items, deliveryModel(delivery))
+	}
+
+	return d.db.Create(&items).Error
+}
+
+// ClaimDeliveries returns at most limit pending deliveries from DB
+// whose next attempt is due and postpones them by the lease.
+//
+// The rows locked by the concurrent dispatchers are skipped.
+func (d *DB) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	var items []models.WebhookDeliveries
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Order("next_attempt_at, id").Limit(limit).Find(&items)
+		if res.Error != nil || len(items) == 0 {
+			return res.Error
+		}
+
+		ids := make([]string, 0, len(items))
+
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+
+		return tx.Model(&models.WebhookDeliveries{}).Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]Delivery, 0, len(items))
+
+	for _, item := range items {
+		deliveries = append(deliveries, delivery(item))
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery performs saving the result of the attempt
+// of the delivery in DB.
+func (d *DB) UpdateDelivery(delivery Delivery) error {
+	res := d.db.Model(&models.WebhookDeliveries{}).Where("id = ?", delivery.ID).
+		Select("next_attempt_at", "delivered_at", "status", "attempts", "response_code", "error").
+		Updates(deliveryModel(delivery))
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// ListDeliveries returns at most limit last deliveries
+// of the webhook from DB, the newest first.
+func (d *DB) ListDeliveries(webhookID string, limit int) ([]Delivery, error) {
+	var items []models.WebhookDeliveries
+
+	res := d.db.Where("webhook_id = ?", webhookID).Order("created_at DESC, id DESC").Limit(limit).Find(&items)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	deliveries := make([]Delivery, 0, len(items))
+
+	for _, item := range items {
+		deliveries = append(deliveries, delivery(item))
+	}
+
+	return deliveries, nil
+}
+
+// PruneDeliveries performs deleting the finished deliveries
+// created before the time from DB.
+func (d *DB) PruneDeliveries(before time.Time) error {
+	return d.db.Where("status <> ? AND created_at < ?", DeliveryPending, before).
+		Delete(&models.WebhookDeliveries{}).Error
+}
+
+// TakeExpired returns at most limit short URLs from DB
+// which have expired by now and haven't been taken before.
+// The removed short URLs are skipped.
+func (d *DB) TakeExpired(now time.Time, limit int) ([]ExpiredLink, error) {
+	var urls []models.Urls
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("short_uid", "long_url", "uid", "expires_at").
+			Where("expiry_notified = ? AND expires_at <= ? AND removed = ?", false, now, false).
+			Order("expires_at, short_uid").Limit(limit).Find(&urls)
+		if res.Error != nil || len(urls) == 0 {
+			return res.Error
+		}
+
+		shortUIDs := make([]string, 0, len(urls))
+
+		for _, url := range urls {
+			shortUIDs = append(shortUIDs, url.ShortUID)
+		}
+
+		return tx.Model(&models.Urls{}).Where("short_uid IN ?", shortUIDs).
+			UpdateColumn("expiry_notified", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]ExpiredLink, 0, len(urls))
+
+	for _, url := range urls {
+		links = append(links, ExpiredLink{
+			ExpiresAt: *url.ExpiresAt,
+			ShortUID:  url.ShortUID,
+			LongURL:   url.LongURL,
+			UserID:    owner(url.UID),
+		})
+	}
+
+	return links, nil
+}
+
+// owner returns the ID of the owner of the short URL, empty for anonymous one.
+func owner(uid uint) string {
+	if uid == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(uid), 10)
+}
+
+// copyWebhook returns a copy of the stored webhook.
+func copyWebhook(hook *Webhook) Webhook {
+	out := *hook
+	out.Events = append([]string(nil), hook.Events...)
+
+	return out
+}
+
+// webhook converts the stored webhook.
+func webhook(item models.Webhooks) Webhook {
+	var events []string
+
+	if item.Events != "" {
+		events = strings.Split(item.Events, ",")
+	}
+
+	return Webhook{
+		CreatedAt:      item.CreatedAt,
+		ID:             item.ID,
+		UserID:         owner(item.UID),
+		URL:            item.URL,
+		Secret:         item.Secret,
+		Events:         events,
+		ClickThreshold: item.ClickThreshold,
+	}
+}
+
+// delivery converts the stored delivery.
+func delivery(item models.WebhookDeliveries) Delivery {
+	return Delivery{
+		CreatedAt:     item.CreatedAt,
+		NextAttemptAt: item.NextAttemptAt,
+		DeliveredAt:   item.DeliveredAt,
+		ID:            item.ID,
+		WebhookID:     item.WebhookID,
+		Event:         item.Event,
+		Payload:       []byte(item.Payload),
+		Status:        item.Status,
+		Attempts:      item.Attempts,
+		ResponseCode:  item.ResponseCode,
+		Error:         item.Error,
+	}
+}
+
+// deliveryModel converts the delivery to the stored one.
+func deliveryModel(delivery Delivery) models.WebhookDeliveries {
+	return models.WebhookDeliveries{
+		CreatedAt:     delivery.CreatedAt,
+		NextAttemptAt: delivery.NextAttemptAt,
+		DeliveredAt:   delivery.DeliveredAt,
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		Event:         delivery.Event,
+		Payload:       string(delivery.Payload),
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		ResponseCode:  delivery.ResponseCode,
+		Error:         delivery.Error,
+	}
+}
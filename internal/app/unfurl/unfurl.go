@@ -0,0 +1,452 @@
+// Package unfurl fetches the metadata of the destination pages
+// of the short URLs: the title, the description, the Open Graph
+// image and the favicon.
+//
+// Only the head of the HTML page is parsed, the body is read
+// up to the limit and the request is limited by the timeout.
+// The Open Graph properties take precedence over the title
+// and the description meta tag of the page.
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/linkcheck"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/storage/pool"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Default settings of the fetcher.
+const (
+	DefaultTimeout     = 5 * time.Second
+	DefaultMaxBodySize = 512 << 10
+	DefaultConcurrency = 4
+	DefaultMaxPending  = 16
+	DefaultInterval    = 10 * time.Minute
+	DefaultMaxAge      = 24 * time.Hour
+	DefaultBatchSize   = 100
+)
+
+// Limits of the lengths of the metadata fields.
+const (
+	maxTextLen = 512
+	maxURLLen  = 2048
+)
+
+// userAgent identifies the requests of the fetcher, some sites
+// serve Open Graph tags only to the known crawlers.
+const userAgent = "Mozilla/5.0 (compatible; shortener-unfurl/1.0)"
+
+// ErrUnexpectedStatus is an indicator that the destination responded with 4xx/5xx status code.
+var ErrUnexpectedStatus = errors.New("destination responded with error status")
+
+// Store is the part of the storage used by the fetcher.
+type Store interface {
+	LinksToFetch(createdAfter time.Time, limit int) ([]storage.HealthTarget, error)
+	SetMetadata(shortUID string, metadata storage.Metadata) error
+}
+
+// Link represents the short URL whose destination page is fetched.
+type Link struct {
+	ShortUID string
+	LongURL  string
+}
+
+// Options represents the settings of the fetcher,
+// the zero values are replaced by the defaults.
+type Options struct {
+	// Client sends the requests, by default the client
+	// of the link checker with DefaultTimeout is used,
+	// which refuses to connect to the non-public addresses.
+	Client *http.Client
+	// MaxBodySize is the maximum number of bytes of the page read.
+	MaxBodySize int64
+	// Concurrency is the maximum number of simultaneous requests.
+	Concurrency int
+	// MaxPending is the maximum number of the submitted batches
+	// of links waiting for the pool, the new ones are dropped.
+	MaxPending int
+	// Interval is the period of the rounds fetching the metadata
+	// of the links dropped or failed before.
+	Interval time.Duration
+	// MaxAge is the age of the links after which their metadata
+	// isn't fetched by the rounds any more.
+	MaxAge time.Duration
+	// BatchSize is the maximum number of links fetched in one round.
+	BatchSize int
+}
+
+// Fetcher fetches the metadata of the pages.
+type Fetcher struct {
+	client      *http.Client
+	pending     chan []Link
+	maxBodySize int64
+	concurrency int
+	interval    time.Duration
+	maxAge      time.Duration
+	batchSize   int
+	// dropped is the number of the links dropped by Submit.
+	dropped atomic.Uint64
+}
+
+// New returns a pointer of Fetcher.
+func New(opts Options) *Fetcher {
+	if opts.Client == nil {
+		opts.Client = linkcheck.NewClient(DefaultTimeout)
+	}
+
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = DefaultMaxPending
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	return &Fetcher{
+		client:      opts.Client,
+		pending:     make(chan []Link, opts.MaxPending),
+		maxBodySize: opts.MaxBodySize,
+		concurrency: opts.Concurrency,
+		interval:    opts.Interval,
+		maxAge:      opts.MaxAge,
+		batchSize:   opts.BatchSize,
+	}
+}
+
+// Submit queues fetching the metadata of the destination pages
+// of the links and returns true, or drops the links and returns false
+// if MaxPending batches are waiting already. It never blocks.
+//
+// The dropped links are counted and their metadata is fetched
+// by the next round of Run.
+func (f *Fetcher) Submit(links []Link) bool {
+	select {
+	case f.pending <- links:
+		return true
+	default:
+		f.dropped.Add(uint64(len(links)))
+
+		return false
+	}
+}
+
+// Dropped returns the number of the links dropped by Submit.
+func (f *Fetcher) Dropped() uint64 {
+	return f.dropped.Load()
+}
+
+// Run adds the submitted batches of links and, at the interval,
+// the rounds of the links without metadata to the pool until
+// the context is done.
+//
+// The next task is added only when the previous one is finished,
+// the errors of the tasks are logged by the pool. The round fetches
+// the links created during MaxAge, so the links dropped by Submit
+// or whose pages could not be fetched are retried. The requests
+// to the slow pages delay the other tasks of the pool, so the pool
+// must be dedicated to the fetcher.
+func (f *Fetcher) Run(ctx context.Context, store Store, workers *pool.Pool) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		var task func() error
+
+		select {
+		case <-ctx.Done():
+			return
+		case links := <-f.pending:
+			task = func() error {
+				return f.FetchAll(ctx, store, links)
+			}
+		case <-ticker.C:
+			task = func() error {
+				return f.FetchDue(ctx, store)
+			}
+		}
+
+		finished := make(chan struct{})
+
+		workers.AddTask(func() error {
+			defer close(finished)
+
+			return task()
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-finished:
+		}
+	}
+}
+
+// FetchDue fetches the metadata of at most BatchSize links
+// created during MaxAge whose metadata has not been fetched.
+func (f *Fetcher) FetchDue(ctx context.Context, store Store) error {
+	targets, err := store.LinksToFetch(time.Now().Add(-f.maxAge), f.batchSize)
+	if err != nil {
+		return err
+	}
+
+	links := make([]Link, 0, len(targets))
+
+	for _, target := range targets {
+		links = append(links, Link{ShortUID: target.ShortUID, LongURL: target.LongURL})
+	}
+
+	return f.FetchAll(ctx, store, links)
+}
+
+// FetchAll fetches the metadata of the destination pages of the links
+// and saves it in the store.
+//
+// The links whose pages could not be fetched are skipped,
+// the errors of all links are returned joined.
+func (f *Fetcher) FetchAll(ctx context.Context, store Store, links []Link) error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		errs  []error
+		slots = make(chan struct{}, f.concurrency)
+	)
+
+	for _, link := range links {
+		link := link
+		slots <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			metadata, err := f.Fetch(ctx, link.LongURL)
+			if err == nil {
+				err = store.SetMetadata(link.ShortUID, metadata)
+			}
+
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("metadata of %s: %w", link.ShortUID, err))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Fetch requests the page and returns its metadata.
+//
+// The metadata of the response other than HTML contains only the favicon.
+func (f *Fetcher) Fetch(ctx context.Context, longURL string) (storage.Metadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, longURL, nil)
+	if err != nil {
+		return storage.Metadata{}, err
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return storage.Metadata{}, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return storage.Metadata{}, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	// the relative URLs are resolved against the URL after redirects.
+	page := resp.Request.URL
+	metadata := storage.Metadata{FetchedAt: time.Now()}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+		body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBodySize), resp.Header.Get("Content-Type"))
+		if err != nil {
+			return storage.Metadata{}, err
+		}
+
+		metadata = parseHead(body, page)
+		metadata.FetchedAt = time.Now()
+	}
+
+	if metadata.Favicon == "" {
+		metadata.Favicon = absoluteURL(page, "/favicon.ico")
+	}
+
+	return metadata, nil
+}
+
+// parseHead returns the metadata from the head of the HTML page.
+func parseHead(body io.Reader, page *url.URL) storage.Metadata {
+	var (
+		metadata  storage.Metadata
+		title     string
+		ogTitle   string
+		ogDesc    string
+		tokenizer = html.NewTokenizer(body)
+	)
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return finish(metadata, title, ogTitle, ogDesc)
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "head" {
+				return finish(metadata, title, ogTitle, ogDesc)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+
+			switch string(name) {
+			case "body":
+				return finish(metadata, title, ogTitle, ogDesc)
+			case "title":
+				if title == "" && tokenizer.Next() == html.TextToken {
+					title = string(tokenizer.Text())
+				}
+			case "meta":
+				attrs := attributes(tokenizer, hasAttr)
+				key := attrs["property"]
+
+				if key == "" {
+					key = attrs["name"]
+				}
+
+				switch strings.ToLower(key) {
+				case "og:title":
+					ogTitle = attrs["content"]
+				case "og:description":
+					ogDesc = attrs["content"]
+				case "description":
+					if metadata.Description == "" {
+						metadata.Description = attrs["content"]
+					}
+				case "og:image", "og:image:url", "og:image:secure_url", "twitter:image":
+					if metadata.Image == "" {
+						metadata.Image = absoluteURL(page, attrs["content"])
+					}
+				}
+			case "link":
+				attrs := attributes(tokenizer, hasAttr)
+
+				if metadata.Favicon == "" && isIcon(attrs["rel"]) {
+					metadata.Favicon = absoluteURL(page, attrs["href"])
+				}
+			}
+		}
+	}
+}
+
+// finish applies the precedence of Open Graph properties
+// and normalizes the text fields.
+func finish(metadata storage.Metadata, title, ogTitle, ogDesc string) storage.Metadata {
+	if ogTitle != "" {
+		title = ogTitle
+	}
+
+	if ogDesc != "" {
+		metadata.Description = ogDesc
+	}
+
+	metadata.Title = text(title)
+	metadata.Description = text(metadata.Description)
+
+	return metadata
+}
+
+// attributes returns the attributes of the current tag with the lower case keys.
+func attributes(tokenizer *html.Tokenizer, hasAttr bool) map[string]string {
+	attrs := make(map[string]string)
+
+	for hasAttr {
+		var key, value []byte
+
+		key, value, hasAttr = tokenizer.TagAttr()
+		attrs[strings.ToLower(string(key))] = string(value)
+	}
+
+	return attrs
+}
+
+// isIcon returns true if the rel attribute of the link is the favicon.
+func isIcon(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		if value == "icon" || value == "apple-touch-icon" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// absoluteURL resolves the reference against the page,
+// the empty string is returned for the invalid and non-HTTP URLs.
+func absoluteURL(page *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	target, err := page.Parse(ref)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return ""
+	}
+
+	if value := target.String(); len(value) <= maxURLLen {
+		return value
+	}
+
+	return ""
+}
+
+// text collapses the whitespaces of the text and cuts it to the limit.
+func text(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+
+	if runes := []rune(value); len(runes) > maxTextLen {
+		return string(runes[:maxTextLen])
+	}
+
+	return value
+}
@@ -0,0 +1,222 @@
+package unfurl_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/storage/pool"
+	"github.com/alaleks/shortener/internal/app/unfurl"
+)
+
+// newServer returns the test server of the destination pages.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	pages := map[string]struct {
+		contentType string
+		body        string
+	}{
+		"/og": {contentType: "text/html; charset=utf-8", body: `<!DOCTYPE html><html><head>
+<title>Page title</title>
+<meta name="description" content="Page description">
+<meta property="og:title" content="  Open   Graph title ">
+<meta property="og:description" content="Open Graph description">
+<meta property="og:image" content="/images/card.png">
+<link rel="shortcut icon" href="static/icon.png">
+</head><body><meta property="og:image" content="/images/body.png"></body></html>`},
+		"/plain": {contentType: "text/html", body: `<html><head><title>Plain &amp; simple</title>
+<meta name="description" content="Only description"></head></html>`},
+		"/latin1": {contentType: "text/html; charset=iso-8859-1", body: "<title>Caf\xe9</title>"},
+		"/pdf":    {contentType: "application/pdf", body: "%PDF-1.4"},
+		"/long": {contentType: "text/html", body: "<html><head>" + strings.Repeat("<meta name=x>", 1000) +
+			`<meta property="og:title" content="Too far"></head></html>`},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/og", http.StatusFound)
+
+			return
+		}
+
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", page.contentType)
+		_, _ = w.Write([]byte(page.body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	server := newServer(t)
+	fetcher := unfurl.New(unfurl.Options{Client: server.Client(), MaxBodySize: 4096})
+
+	tests := []struct {
+		name     string
+		path     string
+		metadata storage.Metadata
+		err      error
+	}{
+		{
+			name: "open graph", path: "/og",
+			metadata: storage.Metadata{
+				Title: "Open Graph title", Description: "Open Graph description",
+				Image: server.URL + "/images/card.png", Favicon: server.URL + "/static/icon.png",
+			},
+		},
+		{
+			name: "redirect", path: "/redirect",
+			metadata: storage.Metadata{
+				Title: "Open Graph title", Description: "Open Graph description",
+				Image: server.URL + "/images/card.png", Favicon: server.URL + "/static/icon.png",
+			},
+		},
+		{
+			name: "title and description", path: "/plain",
+			metadata: storage.Metadata{
+				Title: "Plain & simple", Description: "Only description", Favicon: server.URL + "/favicon.ico",
+			},
+		},
+		{name: "charset", path: "/latin1", metadata: storage.Metadata{Title: "Café", Favicon: server.URL + "/favicon.ico"}},
+		{name: "not HTML", path: "/pdf", metadata: storage.Metadata{Favicon: server.URL + "/favicon.ico"}},
+		{name: "body limit", path: "/long", metadata: storage.Metadata{Favicon: server.URL + "/favicon.ico"}},
+		{name: "not found", path: "/missing", err: unfurl.ErrUnexpectedStatus},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			metadata, err := fetcher.Fetch(context.Background(), server.URL+item.path)
+			if !errors.Is(err, item.err) {
+				t.Fatalf("error should be %v but received %v", item.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if metadata.FetchedAt.IsZero() {
+				t.Error("time of the fetch should be set")
+			}
+
+			metadata.FetchedAt = item.metadata.FetchedAt
+
+			if metadata != item.metadata {
+				t.Errorf("metadata should be %+v but received %+v", item.metadata, metadata)
+			}
+		})
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	server := newServer(t)
+	store := storage.NewDefault(config.New(config.Options{}))
+	fetcher := unfurl.New(unfurl.Options{Client: server.Client()})
+
+	found, err := store.AddURL(storage.NewURL{LongURL: server.URL + "/og"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missing, err := store.AddURL(storage.NewURL{LongURL: server.URL + "/missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fetcher.FetchAll(context.Background(), store, []unfurl.Link{
+		{ShortUID: found, LongURL: server.URL + "/og"},
+		{ShortUID: missing, LongURL: server.URL + "/missing"},
+	})
+	if !errors.Is(err, unfurl.ErrUnexpectedStatus) {
+		t.Errorf("error should be %v but received %v", unfurl.ErrUnexpectedStatus, err)
+	}
+
+	if stat, err := store.Stat(found); err != nil || stat.Metadata == nil || stat.Metadata.Title != "Open Graph title" {
+		t.Errorf("statistics should contain metadata but received %+v (%v)", stat.Metadata, err)
+	}
+
+	// метаданные недоступной страницы не сохраняются
+	if stat, err := store.Stat(missing); err != nil || stat.Metadata != nil {
+		t.Errorf("statistics should not contain metadata but received %+v (%v)", stat.Metadata, err)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	server := newServer(t)
+	store := storage.NewDefault(config.New(config.Options{}))
+	fetcher := unfurl.New(unfurl.Options{Client: server.Client(), MaxPending: 1, Interval: 50 * time.Millisecond})
+
+	shortUIDs := make([]string, 0, 2)
+
+	for _, path := range []string{"/og", "/plain"} {
+		shortUID, err := store.AddURL(storage.NewURL{LongURL: server.URL + path})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		shortUIDs = append(shortUIDs, shortUID)
+	}
+
+	if !fetcher.Submit([]unfurl.Link{{ShortUID: shortUIDs[0], LongURL: server.URL + "/og"}}) {
+		t.Fatal("links should be submitted")
+	}
+
+	// очередь заполнена, вызов не блокируется
+	if fetcher.Submit([]unfurl.Link{{ShortUID: shortUIDs[1], LongURL: server.URL + "/plain"}}) {
+		t.Error("links should be dropped while the queue is full")
+	}
+
+	if dropped := fetcher.Dropped(); dropped != 1 {
+		t.Errorf("number of dropped links should be 1 but received %d", dropped)
+	}
+
+	workers := pool.Init(logger.NewLogger())
+	go workers.Run()
+
+	t.Cleanup(workers.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go fetcher.Run(ctx, store, workers)
+
+	// метаданные отброшенной ссылки загружаются очередным раундом
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		fetched := 0
+
+		for _, shortUID := range shortUIDs {
+			if stat, err := store.Stat(shortUID); err == nil && stat.Metadata != nil {
+				fetched++
+			}
+		}
+
+		if fetched == len(shortUIDs) {
+			return
+		}
+	}
+
+	t.Error("metadata has not been fetched")
+}
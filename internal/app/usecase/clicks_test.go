@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestResolveMaxClicks(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(&storage.Store{St: storage.NewDefault(appConf)}, appConf, nil)
+
+	// данные для теста
+	tests := []struct {
+		name      string
+		maxClicks uint
+		resolves  int
+		want      int
+	}{
+		{name: "one-time", maxClicks: 1, resolves: 20, want: 1},
+		{name: "limited", maxClicks: 5, resolves: 20, want: 5},
+		{name: "unlimited", maxClicks: 0, resolves: 20, want: 20},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortURL, err := service.Shorten("1", "https://example.com/"+item.name,
+				usecase.ShortenOptions{MaxClicks: item.maxClicks})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var (
+				uid      = shortURL[len(appConf.GetBaseURL()):]
+				resolved int32
+				wg       sync.WaitGroup
+			)
+
+			for i := 0; i < item.resolves; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					_, err := service.Resolve(uid, usecase.Visit{})
+
+					switch {
+					case err == nil:
+						atomic.AddInt32(&resolved, 1)
+					case !errors.Is(err, usecase.ErrGone) || !errors.Is(err, storage.ErrShortURLExhausted):
+						t.Errorf("error should be %v but received %v", storage.ErrShortURLExhausted, err)
+					}
+				}()
+			}
+
+			wg.Wait()
+
+			if int(resolved) != item.want {
+				t.Errorf("number of redirects should be %d but received %d", item.want, resolved)
+			}
+
+			if stat, err := service.Stat(uid); err != nil || stat.Usage != uint(item.want) {
+				t.Errorf("usage should be %d but received %d (%v)", item.want, stat.Usage, err)
+			}
+		})
+	}
+}
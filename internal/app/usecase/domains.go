@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+// domains builds the short URLs on the default domain of the base URL
+// and on the custom domains, which share the scheme of the base URL.
+type domains struct {
+	custom  map[string]struct{}
+	baseURL string
+	scheme  string
+	host    string
+}
+
+// newDomains returns the domains of the base URL and the custom domains.
+func newDomains(baseURL string, custom []string) domains {
+	d := domains{
+		custom:  make(map[string]struct{}, len(custom)),
+		baseURL: baseURL,
+		scheme:  "http",
+	}
+
+	if u, err := url.Parse(baseURL); err == nil {
+		d.scheme, d.host = u.Scheme, strings.ToLower(u.Host)
+	}
+
+	for _, domain := range custom {
+		if domain = strings.ToLower(domain); domain != d.host {
+			d.custom[domain] = struct{}{}
+		}
+	}
+
+	return d
+}
+
+// shortURL returns the short URL of the link by its short UID.
+func (d domains) shortURL(shortUID string) string {
+	if shortUID == "" {
+		return ""
+	}
+
+	domain, uid := storage.SplitUID(shortUID)
+	if domain == "" {
+		return d.baseURL + uid
+	}
+
+	return d.scheme + "://" + domain + "/" + uid
+}
+
+// shortUID returns the short UID of the link by the host and its UID,
+// the hosts except the custom domains are the default domain.
+func (d domains) shortUID(host, uid string) string {
+	return storage.DomainUID(d.lookup(host), uid)
+}
+
+// parse returns the short UID of the link by the short URL,
+// the short UID or the UID prefixed with the host: host/uid.
+func (d domains) parse(shortURL string) string {
+	if u, err := url.Parse(shortURL); err == nil && u.Host != "" {
+		return d.shortUID(u.Host, u.Path[strings.LastIndex(u.Path, "/")+1:])
+	}
+
+	return d.shortUID(storage.SplitUID(shortURL))
+}
+
+// domain returns the domain of the new link, the empty domain
+// and the domain of the base URL are the default domain.
+func (d domains) domain(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == d.host {
+		return "", nil
+	}
+
+	if _, ok := d.custom[name]; !ok {
+		return "", newError(ErrInvalidInput, ErrUnknownDomain)
+	}
+
+	return name, nil
+}
+
+// lookup returns the custom domain matching the host
+// with or without the port, otherwise the empty domain.
+func (d domains) lookup(host string) string {
+	host = strings.ToLower(host)
+	if _, ok := d.custom[host]; ok {
+		return host
+	}
+
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		if _, ok := d.custom[name]; ok {
+			return name
+		}
+	}
+
+	return ""
+}
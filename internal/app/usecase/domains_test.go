@@ -0,0 +1,71 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestShortenDomains(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{})
+	appConf.DefineOptionsFlags([]string{"shortener", "-domains", "Go.Example.com, b.example.com"})
+	service := usecase.New(&storage.Store{St: storage.NewDefault(appConf)}, appConf, nil)
+	userID := "1"
+
+	// данные для теста
+	tests := []struct {
+		name    string
+		domain  string
+		prefix  string
+		host    string
+		longURL string
+	}{
+		{
+			name: "домен по умолчанию", domain: "", prefix: appConf.GetBaseURL(),
+			host: "localhost:8080", longURL: "https://example.com/default",
+		},
+		{
+			name: "свой домен", domain: "go.example.com", prefix: "http://go.example.com/",
+			host: "GO.example.com:80", longURL: "https://example.com/go",
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortURL, err := service.Shorten(userID, item.longURL, usecase.ShortenOptions{Domain: item.domain})
+			if err != nil || !strings.HasPrefix(shortURL, item.prefix) {
+				t.Fatalf("short URL should start with %s but received %s (%v)", item.prefix, shortURL, err)
+			}
+
+			uid := shortURL[len(item.prefix):]
+
+			if redirect, err := service.Resolve(uid, usecase.Visit{Host: item.host}); err != nil ||
+				redirect.URL != item.longURL {
+				t.Errorf("redirect should be %s but received %s (%v)", item.longURL, redirect.URL, err)
+			}
+
+			if stat, err := service.Stat(shortURL); err != nil || stat.ShortURL != shortURL {
+				t.Errorf("short URL should be %s but received %s (%v)", shortURL, stat.ShortURL, err)
+			}
+
+			// ссылка не находится на другом домене
+			if _, err := service.Resolve(uid, usecase.Visit{Host: "b.example.com"}); !errors.Is(err, usecase.ErrNotFound) {
+				t.Errorf("error should be %v but received %v", usecase.ErrNotFound, err)
+			}
+		})
+	}
+
+	_, err := service.Shorten(userID, "https://example.com/unknown", usecase.ShortenOptions{Domain: "c.example.com"})
+	if !errors.Is(err, usecase.ErrUnknownDomain) || !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("error should be %v but received %v", usecase.ErrUnknownDomain, err)
+	}
+}
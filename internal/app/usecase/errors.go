@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+// Kinds of domain errors, transports map them to their status codes.
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrUnauthorized = errors.New("user is not defined")
+	ErrForbidden    = errors.New("access denied")
+	ErrNotFound     = errors.New("not found")
+	ErrGone         = errors.New("gone")
+	ErrConflict     = errors.New("conflict")
+	ErrInternal     = errors.New("internal error")
+)
+
+// List of typical errors.
+var (
+	ErrEmptyBatch           = errors.New("URL batching error, please check the source data")
+	ErrEmptyShortUIDs       = errors.New("short URLs for deletion are empty")
+	ErrAccessTrustedSubnet  = errors.New("your IP is not included in the trusted subnet")
+	ErrInvalidDate          = errors.New("date must be in RFC 3339 or YYYY-MM-DD format")
+	ErrInvalidLimit         = errors.New("limit of the page must be a positive number")
+	ErrInvalidAlias         = errors.New("alias must contain from 1 to 64 letters, digits, '_' or '-'")
+	ErrExpiryInPast         = errors.New("expiry time of URL is in the past")
+	ErrBatchRejected        = errors.New("atomic batch is rejected because it contains invalid URLs")
+	ErrIdempotencyKeyReused = errors.New("idempotency key is already used for another request")
+	ErrInvalidTag           = errors.New("tag must contain from 1 to 64 characters")
+	ErrTooManyTags          = errors.New("URL can have at most 20 tags")
+	ErrInvalidFolder        = errors.New("folder must contain at most 128 characters")
+	ErrEmptyLabels          = errors.New("tags or folder must be passed")
+	ErrInvalidUTM           = errors.New("UTM parameter must contain at most 256 characters")
+	ErrInvalidRule          = errors.New("targeting rule is invalid")
+	ErrTooManyRules         = errors.New("URL can have at most 20 targeting rules")
+	ErrInvalidVariant       = errors.New("variant must have valid URL and weight from 1 to 1000")
+	ErrTooManyVariants      = errors.New("URL can have at most 10 variants")
+	ErrInvalidPassword      = errors.New("password must contain from 4 to 72 bytes")
+	ErrPasswordRequired     = errors.New("short URL is protected by the password")
+	ErrWrongPassword        = errors.New("password is wrong")
+	ErrUnknownDomain        = errors.New("domain of short URL is not configured")
+	ErrInvalidBroken        = errors.New("broken must be true or false")
+	ErrInvalidWebhookURL    = errors.New("webhook URL must be absolute HTTP(S) URL of at most 2048 characters")
+	ErrInvalidEvent         = errors.New("events must be link.created, link.deleted, link.expired or link.clicks")
+	ErrTooManyWebhooks      = errors.New("user can have at most 10 webhooks")
+	ErrWebhooksDisabled     = errors.New("webhooks are disabled")
+)
+
+// Error represents the domain error of the specific kind.
+//
+// Both the kind and the cause are matched by errors.Is,
+// and the message of the error is the message of the cause.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the kind and the cause of the error.
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// newError returns the domain error of the kind with the cause.
+func newError(kind, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// wrap returns the domain error for the storage error.
+func wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	kind := ErrInternal
+
+	switch {
+	case errors.Is(err, storage.ErrUIDNotValid), errors.Is(err, storage.ErrUserUrlsEmpty),
+		errors.Is(err, storage.ErrWebhookNotFound):
+		kind = ErrNotFound
+	case errors.Is(err, storage.ErrShortURLRemoved), errors.Is(err, storage.ErrShortURLExpired),
+		errors.Is(err, storage.ErrShortURLExhausted):
+		kind = ErrGone
+	case errors.Is(err, storage.ErrAlreadyExists), errors.Is(err, storage.ErrAliasExists):
+		kind = ErrConflict
+	case errors.Is(err, storage.ErrUserIDNotValid), errors.Is(err, storage.ErrUserNotExists):
+		kind = ErrUnauthorized
+	case errors.Is(err, storage.ErrInvalidData), errors.Is(err, storage.ErrInvalidCursor),
+		errors.Is(err, storage.ErrInvalidListOptions):
+		kind = ErrInvalidInput
+	}
+
+	return newError(kind, err)
+}
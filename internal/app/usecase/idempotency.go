@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultIdempotencyTTL = 24 * time.Hour
+	purgeInterval         = time.Minute
+	// maxIdempotencyKeys is the maximum number of the stored keys.
+	maxIdempotencyKeys = 10000
+	// maxUserIdempotencyKeys is the maximum number of the stored keys of the user.
+	maxUserIdempotencyKeys = 100
+)
+
+// idempotency stores the results of batch requests by user and idempotency key.
+//
+// A repeated request with the same key gets the stored results without
+// shortening URLs again, a concurrent request waits for the first one.
+// The number of the keys is limited in total and per user,
+// the oldest keys are evicted first.
+type idempotency struct {
+	entries map[string]*idempotentEntry
+	// order is the list of the entries from the oldest one.
+	order *list.List
+	// users are the lists of the entries of the users from the oldest one.
+	users     map[string]*list.List
+	lastPurge time.Time
+	ttl       time.Duration
+	mu        sync.Mutex
+}
+
+// idempotentEntry represents the results of the request, done is closed
+// when the request is completed.
+type idempotentEntry struct {
+	expiresAt   time.Time
+	done        chan struct{}
+	inOrder     *list.Element
+	inUser      *list.Element
+	id          string
+	userID      string
+	results     []BatchResult
+	fingerprint [sha256.Size]byte
+	ok          bool
+}
+
+// newIdempotency returns a pointer of idempotency.
+func newIdempotency(ttl time.Duration) *idempotency {
+	return &idempotency{
+		entries: make(map[string]*idempotentEntry),
+		order:   list.New(),
+		users:   make(map[string]*list.List),
+		ttl:     ttl,
+	}
+}
+
+// do returns the stored results of the request with the key
+// or calls fn and stores its results if there is no error.
+func (i *idempotency) do(userID, key string, fingerprint [sha256.Size]byte,
+	fn func() ([]BatchResult, error),
+) ([]BatchResult, error) {
+	id := userID + ":" + key
+
+	for {
+		i.mu.Lock()
+		i.purge()
+
+		entry, ok := i.entries[id]
+		if ok && entry.ok && time.Now().After(entry.expiresAt) {
+			i.remove(entry)
+
+			ok = false
+		}
+
+		if !ok {
+			entry = &idempotentEntry{done: make(chan struct{}), id: id, userID: userID, fingerprint: fingerprint}
+			i.add(entry)
+			i.mu.Unlock()
+
+			return i.complete(id, entry, fn)
+		}
+
+		i.mu.Unlock()
+
+		if entry.fingerprint != fingerprint {
+			return nil, newError(ErrConflict, ErrIdempotencyKeyReused)
+		}
+
+		<-entry.done
+
+		// the first request has failed, so the request is repeated.
+		if entry.ok {
+			return entry.results, nil
+		}
+	}
+}
+
+// complete calls fn and stores its results in the entry,
+// the entry is removed if fn returns an error.
+func (i *idempotency) complete(id string, entry *idempotentEntry,
+	fn func() ([]BatchResult, error),
+) ([]BatchResult, error) {
+	results, err := fn()
+
+	i.mu.Lock()
+	if err != nil {
+		// the entry may have been evicted and replaced by another request.
+		if i.entries[id] == entry {
+			i.remove(entry)
+		}
+	} else {
+		entry.results, entry.ok = results, true
+		entry.expiresAt = time.Now().Add(i.ttl)
+	}
+	i.mu.Unlock()
+
+	close(entry.done)
+
+	return results, err
+}
+
+// purge removes the expired entries, it's called under the lock.
+func (i *idempotency) purge() {
+	now := time.Now()
+	if now.Sub(i.lastPurge) < purgeInterval {
+		return
+	}
+
+	i.lastPurge = now
+
+	for _, entry := range i.entries {
+		if entry.ok && now.After(entry.expiresAt) {
+			i.remove(entry)
+		}
+	}
+}
+
+// add stores the entry and evicts the oldest entries of the user
+// and of all users over the limits, it's called under the lock.
+//
+// The requests waiting for the evicted entry get its results,
+// the next request with its key is performed again.
+func (i *idempotency) add(entry *idempotentEntry) {
+	user, ok := i.users[entry.userID]
+	if !ok {
+		user = list.New()
+		i.users[entry.userID] = user
+	}
+
+	for user.Len() >= maxUserIdempotencyKeys {
+		i.remove(user.Front().Value.(*idempotentEntry))
+	}
+
+	for i.order.Len() >= maxIdempotencyKeys {
+		i.remove(i.order.Front().Value.(*idempotentEntry))
+	}
+
+	// the list of the user could be removed with its last entry.
+	if user.Len() == 0 {
+		i.users[entry.userID] = user
+	}
+
+	i.entries[entry.id] = entry
+	entry.inOrder = i.order.PushBack(entry)
+	entry.inUser = user.PushBack(entry)
+}
+
+// remove removes the entry, it's called under the lock.
+func (i *idempotency) remove(entry *idempotentEntry) {
+	delete(i.entries, entry.id)
+	i.order.Remove(entry.inOrder)
+
+	user := i.users[entry.userID]
+	user.Remove(entry.inUser)
+
+	if user.Len() == 0 {
+		delete(i.users, entry.userID)
+	}
+}
+
+// batchFingerprint returns the hash of the batch request,
+// which is used to detect reusing of the key for another request.
+func batchFingerprint(items []BatchItem, opts BatchOptions) [sha256.Size]byte {
+	hash := sha256.New()
+	hash.Write([]byte(strconv.FormatBool(opts.Atomic)))
+
+	for _, item := range items {
+		hash.Write([]byte{0})
+		hash.Write([]byte(item.CorID))
+		hash.Write([]byte{0})
+		hash.Write([]byte(item.OriginalURL))
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], hash.Sum(nil))
+
+	return sum
+}
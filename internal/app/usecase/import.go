@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/unfurl"
+)
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ImportItem represents the URL imported in bulk
+// with the optional alias and expiry.
+type ImportItem struct {
+	ExpiresAt   *time.Time
+	OriginalURL string
+	Alias       string
+	CorID       string
+}
+
+// Import shortens the imported URL for the user.
+//
+// If the user has already shortened the URL without options, the existing
+// short URL is returned with the error of kind ErrConflict.
+func (s *Service) Import(userID string, item ImportItem) BatchResult {
+	if err := service.IsURL(item.OriginalURL); err != nil {
+		return BatchResult{CorID: item.CorID, Err: newError(ErrInvalidInput, err)}
+	}
+
+	if item.Alias != "" && !aliasPattern.MatchString(item.Alias) {
+		return BatchResult{CorID: item.CorID, Err: newError(ErrInvalidInput, ErrInvalidAlias)}
+	}
+
+	if item.ExpiresAt != nil && !item.ExpiresAt.After(time.Now()) {
+		return BatchResult{CorID: item.CorID, Err: newError(ErrInvalidInput, ErrExpiryInPast)}
+	}
+
+	shortUID, err := s.store.St.AddURL(storage.NewURL{
+		ExpiresAt:     item.ExpiresAt,
+		LongURL:       item.OriginalURL,
+		UserID:        userID,
+		CorrelationID: item.CorID,
+		Alias:         item.Alias,
+	})
+	if err == nil {
+		err = s.created(userID, unfurl.Link{ShortUID: shortUID, LongURL: item.OriginalURL})
+	}
+
+	return BatchResult{CorID: item.CorID, ShortURL: s.domains.shortURL(shortUID), Err: wrap(err)}
+}
+
+// ImportMany shortens the chunk of the imported URLs for the user
+// and returns the results in the order of the items.
+//
+// The URLs without the alias and the expiry are added in one storage call,
+// the URLs shortened by the user earlier without options get their existing
+// short URLs. The other URLs are added one by one by Import.
+func (s *Service) ImportMany(userID string, items []ImportItem) []BatchResult {
+	out := make([]BatchResult, len(items))
+	plain := make([]storage.BatchItem, 0, len(items))
+	// indexes of the plain items in the chunk.
+	indexes := make([]int, 0, len(items))
+
+	for i, item := range items {
+		if item.Alias != "" || item.ExpiresAt != nil {
+			out[i] = s.Import(userID, item)
+
+			continue
+		}
+
+		if err := service.IsURL(item.OriginalURL); err != nil {
+			out[i] = BatchResult{CorID: item.CorID, Err: newError(ErrInvalidInput, err)}
+
+			continue
+		}
+
+		plain = append(plain, storage.BatchItem{LongURL: item.OriginalURL, CorrelationID: item.CorID})
+		indexes = append(indexes, i)
+	}
+
+	if len(plain) == 0 {
+		return out
+	}
+
+	results, err := s.store.St.AddMany(userID, plain)
+	if err != nil {
+		for i, index := range indexes {
+			out[index] = BatchResult{CorID: plain[i].CorrelationID, Err: wrap(err)}
+		}
+
+		return out
+	}
+
+	links := make([]unfurl.Link, 0, len(results))
+
+	for i, result := range results {
+		out[indexes[i]] = BatchResult{
+			CorID:    result.CorrelationID,
+			ShortURL: s.domains.shortURL(result.ShortUID),
+			Err:      wrap(result.Err),
+		}
+
+		if result.Err == nil {
+			links = append(links, unfurl.Link{ShortUID: result.ShortUID, LongURL: plain[i].LongURL})
+		}
+	}
+
+	// the URLs are added, so the error of the events is set in their results.
+	if err := s.created(userID, links...); err != nil {
+		for _, index := range indexes {
+			if out[index].Err == nil {
+				out[index].Err = wrap(err)
+			}
+		}
+	}
+
+	return out
+}
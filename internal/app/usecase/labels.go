@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+// Limits of the labels of URL.
+const (
+	maxTags      = 20
+	maxTagLen    = 64
+	maxFolderLen = 128
+)
+
+// LabelsUpdate represents the change of the labels of URL,
+// nil fields are not changed, the empty ones are cleared.
+type LabelsUpdate struct {
+	Tags   *[]string
+	Folder *string
+}
+
+// LabelStatistics represents the statistics of URLs with the tag or in the folder.
+type LabelStatistics struct {
+	Tag    string `json:"tag,omitempty"`
+	Folder string `json:"folder,omitempty"`
+	URLs   int    `json:"urls"`
+	Clicks uint   `json:"clicks"`
+}
+
+// SetLabels replaces the tags and sets the folder of URL of the user.
+//
+// URL can be passed as the short URL or the short URL ID.
+func (s *Service) SetLabels(userID, shortURL string, update LabelsUpdate) error {
+	if userID == "" {
+		return newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	shortUIDs, err := s.parseShortUIDs([]string{shortURL})
+	if err != nil {
+		return err
+	}
+
+	var out storage.LabelsUpdate
+
+	if update.Tags != nil {
+		tags, err := normalizeTags(*update.Tags)
+		if err != nil {
+			return err
+		}
+
+		out.Tags = &tags
+	}
+
+	if update.Folder != nil {
+		folder, err := normalizeFolder(*update.Folder)
+		if err != nil {
+			return err
+		}
+
+		out.Folder = &folder
+	}
+
+	if out.Tags == nil && out.Folder == nil {
+		return newError(ErrInvalidInput, ErrEmptyLabels)
+	}
+
+	return wrap(s.store.St.SetLabels(userID, shortUIDs[0], out))
+}
+
+// TagStat returns the number of URLs of the user with the tag
+// and the sum of their clicks including the removed URLs.
+func (s *Service) TagStat(userID, tag string) (LabelStatistics, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return LabelStatistics{}, newError(ErrInvalidInput, ErrInvalidTag)
+	}
+
+	stat, err := s.labelStat(userID, storage.ListOptions{Tag: tag})
+
+	return LabelStatistics{Tag: tag, URLs: stat.URLs, Clicks: stat.Clicks}, err
+}
+
+// FolderStat returns the number of URLs of the user in the folder
+// and the sum of their clicks including the removed URLs.
+func (s *Service) FolderStat(userID, folder string) (LabelStatistics, error) {
+	folder = strings.TrimSpace(folder)
+	if folder == "" {
+		return LabelStatistics{}, newError(ErrInvalidInput, ErrInvalidFolder)
+	}
+
+	stat, err := s.labelStat(userID, storage.ListOptions{Folder: folder})
+
+	return LabelStatistics{Folder: folder, URLs: stat.URLs, Clicks: stat.Clicks}, err
+}
+
+// labelStat returns the statistics of all URLs of the user matching the options.
+func (s *Service) labelStat(userID string, opts storage.ListOptions) (storage.URLsStatistics, error) {
+	if userID == "" {
+		return storage.URLsStatistics{}, newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	opts.Status = storage.StatusAll
+
+	stat, err := s.store.St.StatUrlsUser(userID, opts)
+
+	return stat, wrap(err)
+}
+
+// normalizeTags trims the tags and removes the duplicates keeping the order.
+func normalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || len(tag) > maxTagLen {
+			return nil, newError(ErrInvalidInput, ErrInvalidTag)
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+
+	if len(out) > maxTags {
+		return nil, newError(ErrInvalidInput, ErrTooManyTags)
+	}
+
+	return out, nil
+}
+
+// normalizeFolder trims the folder name.
+func normalizeFolder(folder string) (string, error) {
+	folder = strings.TrimSpace(folder)
+	if len(folder) > maxFolderLen {
+		return "", newError(ErrInvalidInput, ErrInvalidFolder)
+	}
+
+	return folder, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+const dateLayout = "2006-01-02"
+
+// ListQuery represents the parameters of the list of the user URLs
+// as they are passed by transports.
+//
+// Sort is the sort field with the optional "-" prefix for
+// the descending order, e.g. "-clicks". Dates are accepted
+// in RFC 3339 or YYYY-MM-DD format.
+type ListQuery struct {
+	Cursor      string
+	Limit       string
+	Sort        string
+	CreatedFrom string
+	CreatedTo   string
+	Domain      string
+	Status      string
+	Tag         string
+	Folder      string
+	// Broken is "true" to get only URLs with the dead destination.
+	Broken string
+}
+
+// Options converts the query to the list options of the storage.
+func (q ListQuery) Options() (storage.ListOptions, error) {
+	opts := storage.ListOptions{
+		Cursor: q.Cursor,
+		Domain: strings.TrimSpace(q.Domain),
+		Status: q.Status,
+		Tag:    q.Tag,
+		Folder: q.Folder,
+	}
+
+	opts.Sort, opts.Desc = strings.CutPrefix(q.Sort, "-")
+
+	if q.Limit != "" {
+		limit, err := strconv.Atoi(q.Limit)
+		if err != nil || limit <= 0 {
+			return opts, newError(ErrInvalidInput, ErrInvalidLimit)
+		}
+
+		opts.Limit = limit
+	}
+
+	var err error
+
+	if q.Broken != "" {
+		if opts.Broken, err = strconv.ParseBool(q.Broken); err != nil {
+			return opts, newError(ErrInvalidInput, ErrInvalidBroken)
+		}
+	}
+
+	if opts.CreatedFrom, err = parseDate(q.CreatedFrom); err != nil {
+		return opts, newError(ErrInvalidInput, fmt.Errorf("created_from: %w", err))
+	}
+
+	if opts.CreatedTo, err = parseDate(q.CreatedTo); err != nil {
+		return opts, newError(ErrInvalidInput, fmt.Errorf("created_to: %w", err))
+	}
+
+	return opts, nil
+}
+
+// parseDate parses the date in RFC 3339 or YYYY-MM-DD format,
+// the empty string is parsed into a zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+
+	return date, nil
+}
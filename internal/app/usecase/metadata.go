@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/unfurl"
+)
+
+// SetFetcher sets the fetcher of the metadata of the destination pages,
+// it must be called before the service is used. The metadata
+// isn't fetched without the fetcher, the fetcher must be run
+// for the submitted links to be fetched.
+func (s *Service) SetFetcher(fetcher *unfurl.Fetcher) {
+	s.unfurl = fetcher
+}
+
+// Preview returns the statistics of the short URL with the metadata
+// of the destination page for the preview of the link, the destinations
+// of the protected short URL are hidden.
+//
+// The short URL that isn't resolved any more (removed, expired or
+// reached the limit of clicks) causes the error of kind ErrGone.
+// URL can be passed as the short URL, the short URL ID
+// or the short URL ID prefixed with the host: host/uid.
+func (s *Service) Preview(shortURL string) (storage.Statistics, error) {
+	shortUIDs, err := s.parseShortUIDs([]string{shortURL})
+	if err != nil {
+		return storage.Statistics{}, err
+	}
+
+	if _, err := s.store.St.GetLink(shortUIDs[0]); err != nil {
+		return storage.Statistics{}, wrap(err)
+	}
+
+	stat, err := s.store.St.Stat(shortUIDs[0])
+	if err != nil {
+		return stat, wrap(err)
+	}
+
+	stat.ShortURL = s.domains.shortURL(shortUIDs[0])
+
+	return hideProtected(stat), nil
+}
+
+// fetchMetadata queues fetching the metadata of the destination pages
+// of the created short URLs without blocking the request, the metadata
+// of the links dropped by the busy fetcher is fetched by its next round.
+func (s *Service) fetchMetadata(links ...unfurl.Link) {
+	if s.unfurl == nil || len(links) == 0 {
+		return
+	}
+
+	s.unfurl.Submit(links)
+}
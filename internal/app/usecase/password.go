@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Limits of the password of URL, bcrypt uses only the first 72 bytes.
+const (
+	minPasswordLen = 4
+	maxPasswordLen = 72
+)
+
+// Unlock checks the password of the protected short URL.
+//
+// The short URL without the password is unlocked by any password,
+// the short URLs on the custom domains are found by the host.
+func (s *Service) Unlock(host, uid, password string) error {
+	link, err := s.store.St.GetLink(s.domains.shortUID(host, uid))
+	if err != nil {
+		return wrap(err)
+	}
+
+	if link.PasswordHash == "" {
+		return nil
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return newError(ErrUnauthorized, ErrWrongPassword)
+	}
+
+	if err != nil {
+		return newError(ErrInternal, err)
+	}
+
+	return nil
+}
+
+// ShortUID returns the short UID of the link by the host and its UID,
+// the access to the protected short URL is granted for this UID
+// so it isn't shared by the links with the same UID on other domains.
+func (s *Service) ShortUID(host, uid string) string {
+	return s.domains.shortUID(host, uid)
+}
+
+// hashPassword returns the bcrypt hash of the password.
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLen || len(password) > maxPasswordLen {
+		return "", newError(ErrInvalidInput, ErrInvalidPassword)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", newError(ErrInternal, err)
+	}
+
+	return string(hash), nil
+}
+
+// hideProtected removes the destinations of the protected short URL
+// and the metadata of the destination page from the public statistics.
+func hideProtected(stat storage.Statistics) storage.Statistics {
+	if !stat.Protected {
+		return stat
+	}
+
+	stat.LongURL = ""
+	stat.Metadata = nil
+	variants := make([]storage.Variant, 0, len(stat.Variants))
+
+	for _, variant := range stat.Variants {
+		variants = append(variants, storage.Variant{Weight: variant.Weight, Clicks: variant.Clicks})
+	}
+
+	stat.Variants = variants
+
+	return stat
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/alaleks/shortener/internal/app/qr"
+)
+
+// QRQuery represents the options of the QR code as they are passed
+// in the query, the empty fields are set to the default values.
+type QRQuery struct {
+	Format string
+	Level  string
+	Size   string
+	Margin string
+}
+
+// Options converts the query to the options of the QR code.
+func (q QRQuery) Options() (qr.Options, error) {
+	opts := qr.DefaultOptions()
+
+	if q.Format != "" {
+		opts.Format = q.Format
+	}
+
+	if q.Level != "" {
+		opts.Level = q.Level
+	}
+
+	if q.Size != "" {
+		size, err := strconv.Atoi(q.Size)
+		if err != nil {
+			return opts, newError(ErrInvalidInput, qr.ErrInvalidSize)
+		}
+
+		opts.Size = size
+	}
+
+	if q.Margin != "" {
+		margin, err := strconv.Atoi(q.Margin)
+		if err != nil {
+			return opts, newError(ErrInvalidInput, qr.ErrInvalidMargin)
+		}
+
+		opts.Margin = margin
+	}
+
+	opts, err := opts.Validate()
+	if err != nil {
+		return opts, newError(ErrInvalidInput, err)
+	}
+
+	return opts, nil
+}
+
+// QRCode returns the QR code of the short URL, the images
+// are cached since the short URL is never changed.
+//
+// URL can be passed as the short URL, the short URL ID
+// or the short URL ID prefixed with the host: host/uid.
+func (s *Service) QRCode(shortURL string, opts qr.Options) (qr.Image, error) {
+	shortUIDs, err := s.parseShortUIDs([]string{shortURL})
+	if err != nil {
+		return qr.Image{}, err
+	}
+
+	if _, err := s.store.St.Stat(shortUIDs[0]); err != nil {
+		return qr.Image{}, wrap(err)
+	}
+
+	img, err := s.qr.Render(s.domains.shortURL(shortUIDs[0]), opts)
+
+	switch {
+	case err == nil:
+		return img, nil
+	case errors.Is(err, qr.ErrInvalidFormat), errors.Is(err, qr.ErrInvalidLevel),
+		errors.Is(err, qr.ErrInvalidSize), errors.Is(err, qr.ErrInvalidMargin):
+		return img, newError(ErrInvalidInput, err)
+	default:
+		return img, newError(ErrInternal, err)
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+const maxUTMLen = 256
+
+// Visit represents the client following the short URL.
+type Visit struct {
+	Query          url.Values
+	IP             netip.Addr
+	UserAgent      string
+	AcceptLanguage string
+	// Host is the host of the request, the short URLs
+	// on the custom domains are resolved by it.
+	Host string
+	// Variant is the ID of the variant served to the client before,
+	// it's kept for the client if the variant still exists.
+	Variant string
+	// Unlocked is true if the client has entered the password
+	// of the protected short URL.
+	Unlocked bool
+}
+
+// Redirect represents the result of following the short URL.
+type Redirect struct {
+	URL string
+	// Variant is the ID of the served variant, which should be kept
+	// for the client, it's empty if the short URL has no variants.
+	Variant string
+}
+
+// destination returns the target URL of the link for the redirect
+// with the merged query.
+//
+// The query parameters are merged with the following precedence,
+// from the lowest to the highest: the query of the original URL,
+// the UTM parameters of the link, the query of the short URL
+// (only if forwarding is enabled for the link). The parameter of the higher
+// level replaces all values of the parameter with the same name.
+// The other parameters of the original URL are kept as is.
+func destination(targetURL string, link storage.Link, query url.Values) string {
+	utm := utmValues(link.UTM)
+	if len(utm) == 0 && (!link.ForwardQuery || len(query) == 0) {
+		return targetURL
+	}
+
+	target, err := url.Parse(targetURL)
+	if err != nil {
+		return targetURL
+	}
+
+	values := utm
+
+	if link.ForwardQuery {
+		for key, value := range query {
+			values[key] = value
+		}
+	}
+
+	// параметры исходного URL сохраняются без перекодирования
+	params := make([]string, 0, len(values)+1)
+
+	for _, param := range strings.Split(target.RawQuery, "&") {
+		if param == "" {
+			continue
+		}
+
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && values.Has(name) {
+			continue
+		}
+
+		params = append(params, param)
+	}
+
+	if len(values) > 0 {
+		params = append(params, values.Encode())
+	}
+
+	target.RawQuery = strings.Join(params, "&")
+
+	return target.String()
+}
+
+// utmValues returns the non-empty UTM parameters as the query values.
+func utmValues(utm storage.UTM) url.Values {
+	values := make(url.Values)
+
+	for key, value := range map[string]string{
+		"utm_source":   utm.Source,
+		"utm_medium":   utm.Medium,
+		"utm_campaign": utm.Campaign,
+		"utm_term":     utm.Term,
+		"utm_content":  utm.Content,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	return values
+}
+
+// normalizeUTM trims the UTM parameters and checks their length.
+func normalizeUTM(utm storage.UTM) (storage.UTM, error) {
+	for _, value := range []*string{&utm.Source, &utm.Medium, &utm.Campaign, &utm.Term, &utm.Content} {
+		*value = strings.TrimSpace(*value)
+		if len(*value) > maxUTMLen {
+			return utm, newError(ErrInvalidInput, ErrInvalidUTM)
+		}
+	}
+
+	return utm, nil
+}
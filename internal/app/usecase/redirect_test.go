@@ -0,0 +1,70 @@
+package usecase_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestResolveQuery(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(&storage.Store{St: storage.NewDefault(appConf)}, appConf, nil)
+	userID := "1"
+
+	// данные для теста
+	tests := []struct {
+		name     string
+		longURL  string
+		opts     usecase.ShortenOptions
+		query    url.Values
+		expected string
+	}{
+		{
+			name:     "без параметров",
+			longURL:  "https://github.com/alaleks/shortener?tab=readme",
+			query:    url.Values{"ref": {"x"}},
+			expected: "https://github.com/alaleks/shortener?tab=readme",
+		},
+		{
+			name:     "utm заменяет параметры ссылки",
+			longURL:  "https://go.dev/doc?utm_source=old&tab=1",
+			opts:     usecase.ShortenOptions{UTM: storage.UTM{Source: "mail", Campaign: "spring"}},
+			expected: "https://go.dev/doc?tab=1&utm_campaign=spring&utm_source=mail",
+		},
+		{
+			name:     "параметры ссылки не перекодируются",
+			longURL:  "https://example.com/search?q=a+b&ids=1,2&flag&utm_source=old&page=%7E2",
+			opts:     usecase.ShortenOptions{UTM: storage.UTM{Source: "mail"}},
+			expected: "https://example.com/search?q=a+b&ids=1,2&flag&page=%7E2&utm_source=mail",
+		},
+		{
+			name:     "параметры запроса заменяют utm",
+			longURL:  "https://pkg.go.dev/net/http#Client",
+			opts:     usecase.ShortenOptions{UTM: storage.UTM{Source: "mail"}, ForwardQuery: true},
+			query:    url.Values{"utm_source": {"ads"}, "ref": {"x"}},
+			expected: "https://pkg.go.dev/net/http?ref=x&utm_source=ads#Client",
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortURL, err := service.Shorten(userID, item.longURL, item.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			redirect, err := service.Resolve(shortURL[len(appConf.GetBaseURL()):], usecase.Visit{Query: item.query})
+			if err != nil || redirect.URL != item.expected {
+				t.Errorf("destination should be %s but received %s (%v)", item.expected, redirect.URL, err)
+			}
+		})
+	}
+}
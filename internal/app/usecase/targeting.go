@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+const maxRules = 20
+
+var errNoConditions = errors.New("rule must have at least one condition")
+
+// Devices of the targeting rules.
+const (
+	DeviceIOS     = "ios"
+	DeviceAndroid = "android"
+	DeviceMobile  = "mobile"
+	DeviceDesktop = "desktop"
+)
+
+// SetRules replaces the targeting rules of URL of the user,
+// the empty list removes the rules.
+//
+// URL can be passed as the short URL or the short URL ID.
+func (s *Service) SetRules(userID, shortURL string, rules []storage.Rule) error {
+	if userID == "" {
+		return newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	shortUIDs, err := s.parseShortUIDs([]string{shortURL})
+	if err != nil {
+		return err
+	}
+
+	if rules, err = normalizeRules(rules); err != nil {
+		return err
+	}
+
+	return wrap(s.store.St.SetRules(userID, shortUIDs[0], rules))
+}
+
+// target returns URL of the first rule matching the client, the variant
+// picked for the client with its index or the original URL of the link.
+func (s *Service) target(link storage.Link, visit Visit) (string, int) {
+	if len(link.Rules) == 0 {
+		return pickVariant(link, visit.Variant)
+	}
+
+	var (
+		device     = deviceOf(visit.UserAgent)
+		language   = preferredLanguage(visit.AcceptLanguage)
+		country, _ = s.geo.Country(visit.IP)
+	)
+
+	for _, rule := range link.Rules {
+		if matchRule(rule, device, language, country, visit.IP) {
+			return rule.URL, noVariant
+		}
+	}
+
+	return pickVariant(link, visit.Variant)
+}
+
+// matchRule returns true if all conditions of the rule match the client.
+func matchRule(rule storage.Rule, device []string, language, country string, addr netip.Addr) bool {
+	if rule.Device != "" && !contains(device, rule.Device) {
+		return false
+	}
+
+	if rule.Language != "" && language != rule.Language && !strings.HasPrefix(language, rule.Language+"-") {
+		return false
+	}
+
+	if rule.Country != "" && rule.Country != country {
+		return false
+	}
+
+	if len(rule.CIDRs) == 0 {
+		return true
+	}
+
+	for _, cidr := range rule.CIDRs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil && addr.IsValid() && prefix.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deviceOf returns the device types of the client by its user agent.
+func deviceOf(userAgent string) []string {
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"),
+		strings.Contains(userAgent, "iPod"):
+		return []string{DeviceIOS, DeviceMobile}
+	case strings.Contains(userAgent, "Android"):
+		return []string{DeviceAndroid, DeviceMobile}
+	case strings.Contains(userAgent, "Mobile"):
+		return []string{DeviceMobile}
+	default:
+		return []string{DeviceDesktop}
+	}
+}
+
+// preferredLanguage returns the language with the highest quality
+// from the Accept-Language header in lower case.
+func preferredLanguage(header string) string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	languages := make([]language, 0)
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+
+		if quality > 0 {
+			languages = append(languages, language{tag: strings.ToLower(tag), quality: quality})
+		}
+	}
+
+	if len(languages) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	return languages[0].tag
+}
+
+// normalizeRules checks the rules and brings their conditions to the canonical form.
+func normalizeRules(rules []storage.Rule) ([]storage.Rule, error) {
+	if len(rules) > maxRules {
+		return nil, newError(ErrInvalidInput, ErrTooManyRules)
+	}
+
+	out := make([]storage.Rule, 0, len(rules))
+
+	for i, rule := range rules {
+		rule.Device = strings.ToLower(strings.TrimSpace(rule.Device))
+		rule.Language = strings.ToLower(strings.TrimSpace(rule.Language))
+		rule.Country = strings.ToUpper(strings.TrimSpace(rule.Country))
+
+		if err := validateRule(rule); err != nil {
+			return nil, newError(ErrInvalidInput, fmt.Errorf("%w (rule %d): %s", ErrInvalidRule, i+1, err.Error()))
+		}
+
+		cidrs := make([]string, 0, len(rule.CIDRs))
+
+		for _, cidr := range rule.CIDRs {
+			cidrs = append(cidrs, netip.MustParsePrefix(strings.TrimSpace(cidr)).Masked().String())
+		}
+
+		rule.CIDRs = cidrs
+
+		out = append(out, rule)
+	}
+
+	return out, nil
+}
+
+// validateRule returns the description of the invalid part of the rule.
+func validateRule(rule storage.Rule) error {
+	if err := service.IsURL(rule.URL); err != nil {
+		return err
+	}
+
+	switch rule.Device {
+	case "", DeviceIOS, DeviceAndroid, DeviceMobile, DeviceDesktop:
+	default:
+		return fmt.Errorf("unknown device %q", rule.Device)
+	}
+
+	if rule.Country != "" && len(rule.Country) != 2 {
+		return fmt.Errorf("country %q must be ISO 3166-1 alpha-2 code", rule.Country)
+	}
+
+	for _, cidr := range rule.CIDRs {
+		if _, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err != nil {
+			return fmt.Errorf("network %q must be in CIDR notation", cidr)
+		}
+	}
+
+	if rule.Device == "" && rule.Language == "" && rule.Country == "" && len(rule.CIDRs) == 0 {
+		return errNoConditions
+	}
+
+	return nil
+}
+
+// contains returns true if the value is in the list.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/geo"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestResolveRules(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(&storage.Store{St: storage.NewDefault(appConf)}, appConf, nil)
+	userID := "1"
+
+	geoDB, err := geo.Read(strings.NewReader("203.0.113.0/24,FR\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service.SetGeo(geoDB)
+
+	shortURL, err := service.Shorten(userID, "https://example.com", usecase.ShortenOptions{
+		Rules: []storage.Rule{
+			{Device: "ios", URL: "https://apps.apple.com/app/id1"},
+			{Device: "android", URL: "https://play.google.com/store/apps"},
+			{Language: "de", URL: "https://example.com/de"},
+			{Country: "fr", URL: "https://fr.example.com"},
+			{CIDRs: []string{"192.0.2.0/24"}, URL: "https://mirror.example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid := shortURL[len(appConf.GetBaseURL()):]
+
+	// данные для теста
+	tests := []struct {
+		name     string
+		visit    usecase.Visit
+		expected string
+	}{
+		{
+			name:     "iphone",
+			visit:    usecase.Visit{UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X)"},
+			expected: "https://apps.apple.com/app/id1",
+		},
+		{
+			name:     "android",
+			visit:    usecase.Visit{UserAgent: "Mozilla/5.0 (Linux; Android 13; Pixel 7)"},
+			expected: "https://play.google.com/store/apps",
+		},
+		{name: "немецкий язык", visit: usecase.Visit{AcceptLanguage: "de-AT,en;q=0.5"}, expected: "https://example.com/de"},
+		{name: "немецкий не основной", visit: usecase.Visit{AcceptLanguage: "en,de;q=0.5"}, expected: "https://example.com"},
+		{name: "страна", visit: usecase.Visit{IP: netip.MustParseAddr("203.0.113.7")}, expected: "https://fr.example.com"},
+		{name: "сеть", visit: usecase.Visit{IP: netip.MustParseAddr("192.0.2.7")}, expected: "https://mirror.example.com"},
+		{name: "без совпадений", visit: usecase.Visit{IP: netip.MustParseAddr("198.51.100.1")}, expected: "https://example.com"},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			redirect, err := service.Resolve(uid, item.visit)
+			if err != nil || redirect.URL != item.expected {
+				t.Errorf("destination should be %s but received %s (%v)", item.expected, redirect.URL, err)
+			}
+		})
+	}
+
+	err = service.SetRules(userID, uid, []storage.Rule{{Device: "tv", URL: "https://example.com/tv"}})
+	if !errors.Is(err, usecase.ErrInvalidRule) || !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("error should be %v but received %v", usecase.ErrInvalidRule, err)
+	}
+}
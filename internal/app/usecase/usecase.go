@@ -0,0 +1,463 @@
+// Package usecase implements the business logic of the application
+// independent of the transport.
+//
+// The web server handlers, the grpc server and the JSON-RPC API are
+// adapters over the Service. Errors are returned as *Error with one of
+// the kinds (ErrNotFound, ErrGone, etc.), which transports map
+// to their own status codes.
+package usecase
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/geo"
+	"github.com/alaleks/shortener/internal/app/qr"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/unfurl"
+	"github.com/alaleks/shortener/internal/app/webhook"
+)
+
+// Service represents the use cases of the application.
+type Service struct {
+	store          *storage.Store
+	idempotency    *idempotency
+	geo            *geo.DB
+	qr             *qr.Cache
+	unfurl         *unfurl.Fetcher
+	webhooks       *webhook.Dispatcher
+	hooks          *hookCache
+	domains        domains
+	trustedSubnets realip.Subnets
+}
+
+// BatchItem represents the URL for batch shortening.
+type BatchItem struct {
+	CorID       string
+	OriginalURL string
+}
+
+// BatchOptions represents the options of batch shortening.
+type BatchOptions struct {
+	// IdempotencyKey identifies the request, the results of the request
+	// with the same key of the user are returned without shortening again.
+	IdempotencyKey string
+	// Atomic rejects the whole batch if any URL is invalid.
+	Atomic bool
+}
+
+// ShortenOptions represents the optional parameters of the shortened URL.
+type ShortenOptions struct {
+	// Tags and Folder (campaign) label URL for filtering and statistics.
+	Tags   []string
+	Folder string
+	// UTM parameters are added to the original URL on redirect.
+	UTM storage.UTM
+	// ForwardQuery passes the query of the short URL to the original URL.
+	ForwardQuery bool
+	// Rules redirect the matching clients to other URLs.
+	Rules []storage.Rule
+	// Variants replace the original URL by the weighted destinations.
+	Variants []storage.Variant
+	// Password protects the redirect, only its hash is stored.
+	Password string
+	// MaxClicks limits the number of redirects, zero means no limit.
+	MaxClicks uint
+	// Domain is one of the custom domains of the short URL,
+	// the empty domain is the domain of the base URL.
+	Domain string
+}
+
+// BatchResult represents the result of shortening of the batch item,
+// Err is set if the URL is invalid.
+type BatchResult struct {
+	Err      error
+	CorID    string
+	ShortURL string
+}
+
+// New returns a pointer of Service.
+//
+// The short URLs are built from the base URL and the custom domains of the config.
+func New(store *storage.Store, conf config.Recipient, trustedSubnets realip.Subnets) *Service {
+	return &Service{
+		store:          store,
+		idempotency:    newIdempotency(defaultIdempotencyTTL),
+		qr:             qr.NewCache(qr.DefaultCacheSize),
+		hooks:          newHookCache(),
+		domains:        newDomains(conf.GetBaseURL(), conf.GetDomains()),
+		trustedSubnets: trustedSubnets,
+	}
+}
+
+// SetIdempotencyTTL sets the time during which the results
+// of batch requests with the idempotency key are stored.
+func (s *Service) SetIdempotencyTTL(ttl time.Duration) {
+	s.idempotency.mu.Lock()
+	s.idempotency.ttl = ttl
+	s.idempotency.mu.Unlock()
+}
+
+// SetGeo sets the geo database used by the country targeting rules,
+// it must be called before the service is used.
+func (s *Service) SetGeo(db *geo.DB) {
+	s.geo = db
+}
+
+// Shorten shortens the URL for the user.
+//
+// If the user has already shortened the URL without options, the existing
+// short URL is returned with the error of kind ErrConflict. The metadata
+// of the destination page of the new short URL is fetched in the background
+// and the event of the creation is emitted to the webhooks of the user.
+func (s *Service) Shorten(userID, longURL string, opts ShortenOptions) (string, error) {
+	if err := service.IsURL(longURL); err != nil {
+		return "", newError(ErrInvalidInput, err)
+	}
+
+	opts, err := s.validate(opts)
+	if err != nil {
+		return "", err
+	}
+
+	if len(opts.Tags) == 0 && opts.Folder == "" && opts.UTM == (storage.UTM{}) && !opts.ForwardQuery &&
+		len(opts.Rules) == 0 && len(opts.Variants) == 0 && opts.Password == "" && opts.MaxClicks == 0 && opts.Domain == "" {
+		shortUID, err := s.store.St.Add(longURL, userID)
+		if err == nil {
+			err = s.created(userID, unfurl.Link{ShortUID: shortUID, LongURL: longURL})
+		}
+
+		return s.domains.shortURL(shortUID), wrap(err)
+	}
+
+	var passwordHash string
+
+	if opts.Password != "" {
+		if passwordHash, err = hashPassword(opts.Password); err != nil {
+			return "", err
+		}
+	}
+
+	shortUID, err := s.store.St.AddURL(storage.NewURL{
+		LongURL:      longURL,
+		UserID:       userID,
+		Tags:         opts.Tags,
+		Folder:       opts.Folder,
+		UTM:          opts.UTM,
+		ForwardQuery: opts.ForwardQuery,
+		Rules:        opts.Rules,
+		Variants:     opts.Variants,
+		PasswordHash: passwordHash,
+		MaxClicks:    opts.MaxClicks,
+		Domain:       opts.Domain,
+	})
+	if err == nil {
+		err = s.created(userID, unfurl.Link{ShortUID: shortUID, LongURL: longURL})
+	}
+
+	return s.domains.shortURL(shortUID), wrap(err)
+}
+
+// validate returns the options with the trimmed and deduplicated tags
+// and the domain of the short URL.
+func (s *Service) validate(o ShortenOptions) (ShortenOptions, error) {
+	var err error
+
+	if o.Domain, err = s.domains.domain(o.Domain); err != nil {
+		return o, err
+	}
+
+	if o.Tags, err = normalizeTags(o.Tags); err != nil {
+		return o, err
+	}
+
+	if o.Folder, err = normalizeFolder(o.Folder); err != nil {
+		return o, err
+	}
+
+	if o.UTM, err = normalizeUTM(o.UTM); err != nil {
+		return o, err
+	}
+
+	if o.Rules, err = normalizeRules(o.Rules); err != nil {
+		return o, err
+	}
+
+	o.Variants, err = normalizeVariants(o.Variants)
+
+	return o, err
+}
+
+// ShortenBatch shortens the batch of URLs for the user.
+//
+// Invalid URLs do not interrupt the processing, the error is set
+// in the result of the item. In the atomic mode no URL is shortened
+// if any URL is invalid, the error of kind ErrInvalidInput is returned
+// with the results. The results of the request with the idempotency key
+// are stored, the key reused for another batch causes ErrConflict.
+// The number of the stored keys is limited, the oldest ones are evicted.
+func (s *Service) ShortenBatch(userID string, items []BatchItem, opts BatchOptions) ([]BatchResult, error) {
+	if len(items) == 0 {
+		return nil, newError(ErrInvalidInput, ErrEmptyBatch)
+	}
+
+	if opts.IdempotencyKey == "" {
+		return s.shortenBatch(userID, items, opts)
+	}
+
+	return s.idempotency.do(userID, opts.IdempotencyKey, batchFingerprint(items, opts),
+		func() ([]BatchResult, error) {
+			return s.shortenBatch(userID, items, opts)
+		})
+}
+
+// shortenBatch validates the URLs and adds the valid ones in one storage call.
+func (s *Service) shortenBatch(userID string, items []BatchItem, opts BatchOptions) ([]BatchResult, error) {
+	out := make([]BatchResult, len(items))
+	valid := make([]storage.BatchItem, 0, len(items))
+	// indexes of the valid items in the batch.
+	indexes := make([]int, 0, len(items))
+
+	for i, item := range items {
+		if err := service.IsURL(item.OriginalURL); err != nil {
+			out[i] = BatchResult{CorID: item.CorID, Err: newError(ErrInvalidInput, err)}
+
+			continue
+		}
+
+		out[i] = BatchResult{CorID: item.CorID}
+		valid = append(valid, storage.BatchItem{LongURL: item.OriginalURL, CorrelationID: item.CorID})
+		indexes = append(indexes, i)
+	}
+
+	if opts.Atomic && len(valid) < len(items) {
+		return out, newError(ErrInvalidInput, ErrBatchRejected)
+	}
+
+	if len(valid) == 0 {
+		return out, nil
+	}
+
+	results, err := s.store.St.AddMany(userID, valid)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	links := make([]unfurl.Link, 0, len(results))
+
+	for i, result := range results {
+		out[indexes[i]] = BatchResult{
+			CorID:    result.CorrelationID,
+			ShortURL: s.domains.shortURL(result.ShortUID),
+			Err:      wrap(result.Err),
+		}
+
+		if result.Err == nil {
+			links = append(links, unfurl.Link{ShortUID: result.ShortUID, LongURL: valid[i].LongURL})
+		}
+	}
+
+	if err := s.created(userID, links...); err != nil {
+		return nil, wrap(err)
+	}
+
+	return out, nil
+}
+
+// ShortenItem shortens one item of the batch for the user,
+// the item is added the same way as the items of the batch,
+// so the collisions of the short UIDs are retried by the storage.
+func (s *Service) ShortenItem(userID string, item BatchItem) BatchResult {
+	out, err := s.shortenBatch(userID, []BatchItem{item}, BatchOptions{})
+	if err != nil {
+		return BatchResult{CorID: item.CorID, Err: err}
+	}
+
+	return out[0]
+}
+
+// Resolve returns the redirect by the short URL ID
+// and counts the usage of the short URL.
+//
+// The client is redirected to URL of the first matching targeting rule,
+// to one of the weighted variants or to the original URL. The UTM parameters
+// of the short URL and, if forwarding is enabled, the query of the request
+// are merged into the query of the target URL, see destination for the precedence.
+// The protected short URL is resolved only for the unlocked visit.
+// The short URL with the limit of clicks is not resolved after
+// the limit has been reached, the error of kind ErrGone is returned.
+// The short URLs on the custom domains are found by the host of the visit.
+// The event is emitted when the short URL reaches the click threshold
+// of the webhooks of its owner.
+func (s *Service) Resolve(uid string, visit Visit) (Redirect, error) {
+	uid = s.domains.shortUID(visit.Host, uid)
+
+	link, err := s.store.St.GetLink(uid)
+	if err != nil {
+		return Redirect{}, wrap(err)
+	}
+
+	if link.PasswordHash != "" && !visit.Unlocked {
+		return Redirect{}, newError(ErrUnauthorized, ErrPasswordRequired)
+	}
+
+	targetURL, variant := s.target(link, visit)
+	out := Redirect{URL: destination(targetURL, link, visit.Query)}
+
+	clicks, err := s.store.St.Click(uid, variant)
+	if err != nil {
+		return Redirect{}, wrap(err)
+	}
+
+	if err := s.clicked(uid, link, clicks); err != nil {
+		return Redirect{}, wrap(err)
+	}
+
+	if variant != noVariant {
+		out.Variant = variantID(link.Variants[variant])
+	}
+
+	return out, nil
+}
+
+// DomainUID returns the short UID of the link by its UID
+// on the domain, the empty domain is the default domain.
+// The domain must be the domain of the base URL or the custom domain.
+func (s *Service) DomainUID(domain, uid string) (string, error) {
+	domain, err := s.domains.domain(domain)
+	if err != nil {
+		return "", err
+	}
+
+	return storage.DomainUID(domain, uid), nil
+}
+
+// Stat returns the statistics on the use of the short URL,
+// the destinations of the protected short URL are hidden.
+//
+// URL can be passed as the short URL, the short URL ID
+// or the short URL ID prefixed with the host: host/uid.
+func (s *Service) Stat(shortURL string) (storage.Statistics, error) {
+	shortUIDs, err := s.parseShortUIDs([]string{shortURL})
+	if err != nil {
+		return storage.Statistics{}, err
+	}
+
+	stat, err := s.store.St.Stat(shortUIDs[0])
+	if err != nil {
+		return stat, wrap(err)
+	}
+
+	stat.ShortURL = s.domains.shortURL(shortUIDs[0])
+
+	return hideProtected(stat), nil
+}
+
+// List returns all shortened URLs of the user.
+func (s *Service) List(userID string) ([]storage.UserURL, error) {
+	if userID == "" {
+		return nil, newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	urls, err := s.store.St.GetUrlsUser(userID)
+
+	return s.withShortURLs(urls), wrap(err)
+}
+
+// ListPage returns the page of shortened URLs of the user.
+func (s *Service) ListPage(userID string, opts storage.ListOptions) (storage.URLsPage, error) {
+	if userID == "" {
+		return storage.URLsPage{}, newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	page, err := s.store.St.ListUrlsUser(userID, opts)
+	page.URLs = s.withShortURLs(page.URLs)
+
+	return page, wrap(err)
+}
+
+// Delete deletes the shortened URLs of the user.
+//
+// URLs can be passed as short URLs or short URL IDs.
+// The events of the deletion are emitted to the webhooks of the user.
+func (s *Service) Delete(userID string, urls ...string) error {
+	shortUIDs, err := s.parseShortUIDs(urls)
+	if err != nil {
+		return err
+	}
+
+	deleted := s.deletedLinks(userID, shortUIDs)
+
+	if err := s.store.St.DelUrls(userID, shortUIDs...); err != nil {
+		return wrap(err)
+	}
+
+	return wrap(s.emit(userID, webhook.EventLinkDeleted, deleted...))
+}
+
+// DeleteAsync deletes the shortened URLs of the user in the pool,
+// the events of the deletion are added to the outbox by the same task.
+// The result of the deletion is logged by the pool.
+func (s *Service) DeleteAsync(userID string, urls ...string) error {
+	shortUIDs, err := s.parseShortUIDs(urls)
+	if err != nil {
+		return err
+	}
+
+	s.store.Pool.AddTask(func() error {
+		deleted := s.deletedLinks(userID, shortUIDs)
+
+		if err := s.store.St.DelUrls(userID, shortUIDs...); err != nil {
+			return fmt.Errorf("deletion error: %w", err)
+		}
+
+		return s.emit(userID, webhook.EventLinkDeleted, deleted...)
+	})
+
+	return nil
+}
+
+// InternalStats returns the number of shortened URLs and users.
+//
+// Access is allowed only to clients from the trusted subnets.
+func (s *Service) InternalStats(clientIP netip.Addr) (storage.InternalStats, error) {
+	if !clientIP.IsValid() || !s.trustedSubnets.Contains(clientIP) {
+		return storage.InternalStats{}, newError(ErrForbidden, ErrAccessTrustedSubnet)
+	}
+
+	stat, err := s.store.St.GetInternalStats()
+
+	return stat, wrap(err)
+}
+
+// withShortURLs sets the short URLs of the user URLs by their short UIDs.
+func (s *Service) withShortURLs(urls []storage.UserURL) []storage.UserURL {
+	for i := range urls {
+		urls[i].ShortURL = s.domains.shortURL(urls[i].ShortUID)
+	}
+
+	return urls
+}
+
+// parseShortUIDs returns the short URL IDs from short URLs,
+// the short URLs on the custom domains are found by the host.
+func (s *Service) parseShortUIDs(urls []string) ([]string, error) {
+	shortUIDs := make([]string, 0, len(urls))
+
+	for _, v := range urls {
+		if sUID := s.domains.parse(v); !strings.HasSuffix(sUID, "/") && sUID != "" {
+			shortUIDs = append(shortUIDs, sUID)
+		}
+	}
+
+	if len(shortUIDs) == 0 {
+		return nil, newError(ErrInvalidInput, ErrEmptyShortUIDs)
+	}
+
+	return shortUIDs, nil
+}
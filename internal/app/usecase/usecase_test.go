@@ -0,0 +1,206 @@
+package usecase_test
+
+import (
+	"errors"
+	"net/netip"
+	"strconv"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestServiceErrors(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	st := storage.InitStore(appConf, logger.NewLogger())
+	service := usecase.New(st, appConf, realip.ParseSubnets("192.0.2.0/24"))
+	userID := "1"
+
+	shortURL, err := service.Shorten(userID, "https://github.com/alaleks/shortener", usecase.ShortenOptions{})
+	if err != nil {
+		t.Fatalf("failed shorten url: %s", err)
+	}
+
+	_, errInvalid := service.Shorten(userID, "github.com", usecase.ShortenOptions{})
+	_, errNotFound := service.Stat("unknown")
+	_, errForbidden := service.InternalStats(netip.MustParseAddr("198.51.100.1"))
+	_, errNoIP := service.InternalStats(netip.Addr{})
+	_, errBatch := service.ShortenBatch(userID, nil, usecase.BatchOptions{})
+	errDelete := service.Delete(userID, "", "/")
+	_, errAllowed := service.InternalStats(netip.MustParseAddr("192.0.2.10"))
+
+	// данные для теста
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{name: "невалидная ссылка", err: errInvalid, kind: usecase.ErrInvalidInput},
+		{name: "неизвестная короткая ссылка", err: errNotFound, kind: usecase.ErrNotFound},
+		{name: "ip вне доверенной подсети", err: errForbidden, kind: usecase.ErrForbidden},
+		{name: "ip не определен", err: errNoIP, kind: usecase.ErrForbidden},
+		{name: "пустой батч", err: errBatch, kind: usecase.ErrInvalidInput},
+		{name: "пустой список на удаление", err: errDelete, kind: usecase.ErrInvalidInput},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			if !errors.Is(item.err, item.kind) {
+				t.Errorf("error should be %v but received %v", item.kind, item.err)
+			}
+		})
+	}
+
+	if !errors.Is(errNotFound, storage.ErrUIDNotValid) {
+		t.Errorf("error should wrap the storage error but received %v", errNotFound)
+	}
+
+	if errAllowed != nil {
+		t.Errorf("access from trusted subnet should be allowed but received %v", errAllowed)
+	}
+
+	if redirect, err := service.Resolve(shortURL[len(appConf.GetBaseURL()):], usecase.Visit{}); err != nil || redirect.URL == "" {
+		t.Errorf("short url should be resolved but received %v", err)
+	}
+}
+
+func TestShortenBatch(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(storage.InitStore(appConf, logger.NewLogger()), appConf, nil)
+
+	// данные для теста
+	items := []usecase.BatchItem{
+		{CorID: "1", OriginalURL: "https://github.com/alaleks/shortener"},
+		{CorID: "2", OriginalURL: "github.com"},
+		{CorID: "3", OriginalURL: "https://go.dev"},
+	}
+
+	results, err := service.ShortenBatch("1", items, usecase.BatchOptions{})
+	if err != nil {
+		t.Fatalf("failed shorten batch: %s", err)
+	}
+
+	if len(results) != len(items) {
+		t.Fatalf("number of results should be %d but received %d", len(items), len(results))
+	}
+
+	for i, result := range results {
+		invalid := items[i].CorID == "2"
+
+		if result.CorID != items[i].CorID || (result.Err != nil) != invalid || (result.ShortURL == "") != invalid {
+			t.Errorf("result of item %s is unexpected: %+v", items[i].CorID, result)
+		}
+	}
+}
+
+func TestShortenItem(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(storage.InitStore(appConf, logger.NewLogger()), appConf, nil)
+
+	// данные для теста
+	tests := []struct {
+		item usecase.BatchItem
+		err  error
+	}{
+		{item: usecase.BatchItem{CorID: "1", OriginalURL: "https://github.com/alaleks/shortener"}},
+		{item: usecase.BatchItem{CorID: "2", OriginalURL: "github.com"}, err: usecase.ErrInvalidInput},
+	}
+
+	for _, item := range tests {
+		result := service.ShortenItem("1", item.item)
+
+		if result.CorID != item.item.CorID || !errors.Is(result.Err, item.err) || (result.ShortURL == "") != (item.err != nil) {
+			t.Errorf("result of item %s is unexpected: %+v", item.item.CorID, result)
+		}
+	}
+}
+
+func TestShortenBatchIdempotency(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(storage.InitStore(appConf, logger.NewLogger()), appConf, nil)
+	userID := "1"
+
+	// данные для теста
+	items := []usecase.BatchItem{{CorID: "1", OriginalURL: "https://github.com/alaleks/shortener"}}
+	opts := usecase.BatchOptions{IdempotencyKey: "key"}
+
+	first, err := service.ShortenBatch(userID, items, opts)
+	if err != nil {
+		t.Fatalf("failed shorten batch: %s", err)
+	}
+
+	// повторный запрос с тем же ключом возвращает тот же результат
+	retried, err := service.ShortenBatch(userID, items, opts)
+	if err != nil || retried[0].ShortURL != first[0].ShortURL {
+		t.Errorf("short url should be %s but received %+v, %v", first[0].ShortURL, retried, err)
+	}
+
+	// ключ другого пользователя не пересекается
+	other, _ := service.ShortenBatch("2", items, opts)
+	if other[0].ShortURL == first[0].ShortURL {
+		t.Errorf("short url of another user should differ from %s", first[0].ShortURL)
+	}
+
+	// ключ используется для другого запроса
+	_, err = service.ShortenBatch(userID, append(items, items...), opts)
+	if !errors.Is(err, usecase.ErrConflict) || !errors.Is(err, usecase.ErrIdempotencyKeyReused) {
+		t.Errorf("error should be %v but received %v", usecase.ErrIdempotencyKeyReused, err)
+	}
+
+	// атомарный батч с невалидной ссылкой отклоняется целиком
+	invalid := append(items, usecase.BatchItem{CorID: "2", OriginalURL: "github.com"})
+
+	results, err := service.ShortenBatch(userID, invalid, usecase.BatchOptions{Atomic: true})
+	if !errors.Is(err, usecase.ErrBatchRejected) || results[0].ShortURL != "" || results[1].Err == nil {
+		t.Errorf("atomic batch should be rejected but received %+v, %v", results, err)
+	}
+}
+
+func TestShortenBatchIdempotencyLimit(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(storage.InitStore(appConf, logger.NewLogger()), appConf, nil)
+
+	// данные для теста
+	items := []usecase.BatchItem{{CorID: "1", OriginalURL: "https://github.com/alaleks/shortener"}}
+	other := []usecase.BatchItem{{CorID: "1", OriginalURL: "https://go.dev"}}
+
+	for _, userID := range []string{"1", "2"} {
+		if _, err := service.ShortenBatch(userID, items, usecase.BatchOptions{IdempotencyKey: "first"}); err != nil {
+			t.Fatalf("failed shorten batch: %s", err)
+		}
+	}
+
+	// новые ключи пользователя вытесняют его самый старый ключ
+	for i := 0; i < 100; i++ {
+		opts := usecase.BatchOptions{IdempotencyKey: strconv.Itoa(i)}
+		if _, err := service.ShortenBatch("1", items, opts); err != nil {
+			t.Fatalf("failed shorten batch: %s", err)
+		}
+	}
+
+	if _, err := service.ShortenBatch("1", other, usecase.BatchOptions{IdempotencyKey: "first"}); err != nil {
+		t.Errorf("evicted key should be accepted for another batch but received %v", err)
+	}
+
+	// ключи другого пользователя не вытесняются
+	_, err := service.ShortenBatch("2", other, usecase.BatchOptions{IdempotencyKey: "first"})
+	if !errors.Is(err, usecase.ErrIdempotencyKeyReused) {
+		t.Errorf("error should be %v but received %v", usecase.ErrIdempotencyKeyReused, err)
+	}
+}
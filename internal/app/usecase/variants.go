@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage"
+)
+
+// Limits of the variants of URL.
+const (
+	maxVariants = 10
+	maxWeight   = 1000
+)
+
+// noVariant is the index of the variant when the original URL
+// or the targeting rule is served.
+const noVariant = -1
+
+// SetVariants replaces the weighted destinations of URL of the user,
+// the counters of redirects are reset. The empty list removes the variants.
+//
+// URL can be passed as the short URL or the short URL ID.
+func (s *Service) SetVariants(userID, shortURL string, variants []storage.Variant) error {
+	if userID == "" {
+		return newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	shortUIDs, err := s.parseShortUIDs([]string{shortURL})
+	if err != nil {
+		return err
+	}
+
+	if variants, err = normalizeVariants(variants); err != nil {
+		return err
+	}
+
+	return wrap(s.store.St.SetVariants(userID, shortUIDs[0], variants))
+}
+
+// pickVariant returns the variant of the link for the client with its index.
+//
+// The variant served before is kept while its URL is among the variants,
+// otherwise the variant is picked randomly according to the weights.
+// The original URL is returned if the link has no variants.
+func pickVariant(link storage.Link, previous string) (string, int) {
+	if len(link.Variants) == 0 {
+		return link.LongURL, noVariant
+	}
+
+	if previous != "" {
+		for i, variant := range link.Variants {
+			if variantID(variant) == previous {
+				return variant.URL, i
+			}
+		}
+	}
+
+	total := 0
+
+	for _, variant := range link.Variants {
+		total += variant.Weight
+	}
+
+	if total <= 0 {
+		return link.LongURL, noVariant
+	}
+
+	n := rand.Intn(total)
+
+	for i, variant := range link.Variants {
+		if n < variant.Weight {
+			return variant.URL, i
+		}
+
+		n -= variant.Weight
+	}
+
+	return link.LongURL, noVariant
+}
+
+// variantID returns the ID of the variant kept for the client, it's derived
+// from URL of the variant, so it doesn't change when the variants are reordered.
+func variantID(variant storage.Variant) string {
+	sum := sha256.Sum256([]byte(variant.URL))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// normalizeVariants checks the variants and resets their counters.
+func normalizeVariants(variants []storage.Variant) ([]storage.Variant, error) {
+	if len(variants) > maxVariants {
+		return nil, newError(ErrInvalidInput, ErrTooManyVariants)
+	}
+
+	out := make([]storage.Variant, 0, len(variants))
+
+	for i, variant := range variants {
+		if err := service.IsURL(variant.URL); err != nil || variant.Weight < 1 || variant.Weight > maxWeight {
+			return nil, newError(ErrInvalidInput, fmt.Errorf("%w (variant %d)", ErrInvalidVariant, i+1))
+		}
+
+		out = append(out, storage.Variant{URL: variant.URL, Weight: variant.Weight})
+	}
+
+	return out, nil
+}
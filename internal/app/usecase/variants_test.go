@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+)
+
+func TestResolveVariants(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(&storage.Store{St: storage.NewDefault(appConf)}, appConf, nil)
+	userID := "1"
+
+	// данные для теста
+	variants := []storage.Variant{
+		{URL: "https://example.com/a", Weight: 3},
+		{URL: "https://example.com/b", Weight: 1},
+	}
+
+	shortURL, err := service.Shorten(userID, "https://example.com", usecase.ShortenOptions{Variants: variants})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid := shortURL[len(appConf.GetBaseURL()):]
+	served := make(map[string]int)
+	// идентификаторы вариантов по их URL
+	ids := make(map[string]string)
+
+	for i := 0; i < 100; i++ {
+		redirect, err := service.Resolve(uid, usecase.Visit{})
+		if err != nil || redirect.Variant == "" {
+			t.Fatalf("variant should be served but received %+v (%v)", redirect, err)
+		}
+
+		served[redirect.URL]++
+		ids[redirect.URL] = redirect.Variant
+	}
+
+	if served[variants[0].URL] == 0 || served[variants[1].URL] == 0 || len(served) != 2 {
+		t.Errorf("both variants should be served but received %v", served)
+	}
+
+	// вариант сохраняется для посетителя
+	for i := 0; i < 10; i++ {
+		if redirect, _ := service.Resolve(uid, usecase.Visit{Variant: ids[variants[1].URL]}); redirect.URL != variants[1].URL {
+			t.Fatalf("variant should be %s but received %s", variants[1].URL, redirect.URL)
+		}
+	}
+
+	stat, err := service.Stat(uid)
+	if err != nil || len(stat.Variants) != 2 || stat.Usage != 110 ||
+		stat.Variants[0].Clicks+stat.Variants[1].Clicks != 110 ||
+		stat.Variants[1].Clicks != uint(served[variants[1].URL]+10) {
+		t.Errorf("statistics should count the variants but received %+v (%v)", stat, err)
+	}
+
+	// вариант сохраняется после изменения порядка вариантов
+	if err := service.SetVariants(userID, uid, []storage.Variant{variants[1], variants[0]}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, variant := range variants {
+		redirect, _ := service.Resolve(uid, usecase.Visit{Variant: ids[variant.URL]})
+		if redirect.URL != variant.URL || redirect.Variant != ids[variant.URL] {
+			t.Errorf("variant should be %s but received %+v", variant.URL, redirect)
+		}
+	}
+
+	err = service.SetVariants(userID, uid, []storage.Variant{{URL: "https://example.com/c", Weight: 0}})
+	if !errors.Is(err, usecase.ErrInvalidVariant) {
+		t.Errorf("error should be %v but received %v", usecase.ErrInvalidVariant, err)
+	}
+
+	if err := service.SetVariants(userID, uid, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if redirect, _ := service.Resolve(uid, usecase.Visit{Variant: ids[variants[1].URL]}); redirect.URL != "https://example.com" ||
+		redirect.Variant != "" {
+		t.Errorf("original URL should be served but received %+v", redirect)
+	}
+}
@@ -0,0 +1,450 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/unfurl"
+	"github.com/alaleks/shortener/internal/app/webhook"
+)
+
+const (
+	maxWebhooks       = 10
+	maxWebhookURLLen  = 2048
+	maxDeliveriesLog  = 100
+	sizeWebhookSecret = 32
+	// hookCacheTTL is the time during which the webhooks of the user
+	// are cached, so the redirects don't read them on every click.
+	// The changes made on other instances are seen after it.
+	hookCacheTTL = 30 * time.Second
+	// hookCacheSize is the number of the cached users
+	// after which the expired ones are removed.
+	hookCacheSize = 10000
+	// sweepBatchSize is the number of the expired short URLs taken at once.
+	sweepBatchSize = 100
+	// maxExpiredAge limits the age of the expiry of the events,
+	// the short URLs expired long ago (e.g. before the webhooks
+	// were introduced) are skipped.
+	maxExpiredAge = 24 * time.Hour
+)
+
+// WebhookInput represents the webhook registered by the user.
+type WebhookInput struct {
+	URL    string
+	Events []string
+	// ClickThreshold is the number of clicks of the short URL
+	// on which link.clicks is sent, 1 by default.
+	ClickThreshold uint
+}
+
+// hookCache caches the webhooks of the users.
+type hookCache struct {
+	items map[string]hookCacheItem
+	mu    sync.Mutex
+}
+
+// hookCacheItem represents the cached webhooks of the user.
+type hookCacheItem struct {
+	expiresAt time.Time
+	hooks     []storage.Webhook
+}
+
+// SetDispatcher sets the dispatcher of the deliveries of the webhooks,
+// it must be called before the service is used. The events aren't
+// emitted without the dispatcher.
+func (s *Service) SetDispatcher(dispatcher *webhook.Dispatcher) {
+	s.webhooks = dispatcher
+}
+
+// AddWebhook registers the webhook of the user for the events.
+//
+// The secret signing the deliveries is generated and returned
+// only by this method.
+func (s *Service) AddWebhook(userID string, input WebhookInput) (storage.Webhook, error) {
+	if userID == "" {
+		return storage.Webhook{}, newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	target, err := url.Parse(input.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" ||
+		len(input.URL) > maxWebhookURLLen {
+		return storage.Webhook{}, newError(ErrInvalidInput, ErrInvalidWebhookURL)
+	}
+
+	events, err := normalizeEvents(input.Events)
+	if err != nil {
+		return storage.Webhook{}, err
+	}
+
+	if input.ClickThreshold == 0 {
+		input.ClickThreshold = 1
+	}
+
+	hooks, err := s.store.St.ListWebhooks(userID)
+	if err != nil {
+		return storage.Webhook{}, wrap(err)
+	}
+
+	if len(hooks) >= maxWebhooks {
+		return storage.Webhook{}, newError(ErrConflict, ErrTooManyWebhooks)
+	}
+
+	hook, err := s.store.St.AddWebhook(storage.Webhook{
+		UserID:         userID,
+		URL:            input.URL,
+		Secret:         "whsec_" + service.GenUID(sizeWebhookSecret),
+		Events:         events,
+		ClickThreshold: input.ClickThreshold,
+	})
+	if err != nil {
+		return storage.Webhook{}, wrap(err)
+	}
+
+	s.hooks.forget(userID)
+
+	return hook, nil
+}
+
+// Webhooks returns the webhooks of the user without the secrets.
+func (s *Service) Webhooks(userID string) ([]storage.Webhook, error) {
+	if userID == "" {
+		return nil, newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	hooks, err := s.store.St.ListWebhooks(userID)
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	return hooks, nil
+}
+
+// DeleteWebhook deletes the webhook of the user with its deliveries.
+func (s *Service) DeleteWebhook(userID, id string) error {
+	if userID == "" {
+		return newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	if err := s.store.St.DelWebhook(userID, id); err != nil {
+		return wrap(err)
+	}
+
+	s.hooks.forget(userID)
+
+	return nil
+}
+
+// WebhookDeliveries returns the last deliveries of the webhook
+// of the user, the newest first.
+func (s *Service) WebhookDeliveries(userID, id string) ([]storage.Delivery, error) {
+	if _, err := s.webhook(userID, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.store.St.ListDeliveries(id, maxDeliveriesLog)
+
+	return deliveries, wrap(err)
+}
+
+// TestWebhook sends the ping event to the webhook of the user
+// and returns the delivery with the result of the attempt.
+//
+// The failed ping is retried as other deliveries.
+func (s *Service) TestWebhook(ctx context.Context, userID, id string) (storage.Delivery, error) {
+	hook, err := s.webhook(userID, id)
+	if err != nil {
+		return storage.Delivery{}, err
+	}
+
+	if s.webhooks == nil {
+		return storage.Delivery{}, newError(ErrInternal, ErrWebhooksDisabled)
+	}
+
+	delivery, err := s.webhooks.Ping(ctx, hook)
+
+	return delivery, wrap(err)
+}
+
+// SweepExpired adds the events of the short URLs expired since
+// the previous sweep to the outbox, it's called by the dispatcher.
+func (s *Service) SweepExpired() error {
+	for {
+		now := time.Now()
+
+		links, err := s.store.St.TakeExpired(now, sweepBatchSize)
+		if err != nil {
+			return err
+		}
+
+		var deliveries []storage.Delivery
+
+		for _, link := range links {
+			if link.ExpiresAt.Before(now.Add(-maxExpiredAge)) {
+				continue
+			}
+
+			expiresAt := link.ExpiresAt
+			items, err := s.deliveries(link.UserID, webhook.EventLinkExpired, webhook.LinkData{
+				ExpiresAt:   &expiresAt,
+				ShortURL:    s.domains.shortURL(link.ShortUID),
+				OriginalURL: link.LongURL,
+			})
+			if err != nil {
+				return err
+			}
+
+			deliveries = append(deliveries, items...)
+		}
+
+		if err := s.store.St.AddDeliveries(deliveries...); err != nil {
+			return err
+		}
+
+		if len(links) < sweepBatchSize {
+			return nil
+		}
+	}
+}
+
+// created fetches the metadata of the destination pages of the created
+// short URLs and emits their events.
+func (s *Service) created(userID string, links ...unfurl.Link) error {
+	s.fetchMetadata(links...)
+
+	items := make([]webhook.LinkData, 0, len(links))
+
+	for _, link := range links {
+		items = append(items, webhook.LinkData{
+			ShortURL:    s.domains.shortURL(link.ShortUID),
+			OriginalURL: link.LongURL,
+		})
+	}
+
+	return s.emit(userID, webhook.EventLinkCreated, items...)
+}
+
+// clicked emits the event if the short URL has reached
+// the click threshold of the webhooks of its owner.
+func (s *Service) clicked(shortUID string, link storage.Link, clicks uint) error {
+	return s.emit(link.UserID, webhook.EventLinkClicks, webhook.LinkData{
+		ShortURL:    s.domains.shortURL(shortUID),
+		OriginalURL: link.LongURL,
+		Clicks:      clicks,
+	})
+}
+
+// deletedLinks returns the events of the short URLs of the user
+// which are deleted by the short UIDs: the removed and other users'
+// short URLs are skipped. Nothing is returned if the webhooks
+// of the user aren't subscribed to the deletion.
+func (s *Service) deletedLinks(userID string, shortUIDs []string) []webhook.LinkData {
+	if s.webhooks == nil || !s.subscribed(userID, webhook.EventLinkDeleted) {
+		return nil
+	}
+
+	items := make([]webhook.LinkData, 0, len(shortUIDs))
+
+	for _, shortUID := range shortUIDs {
+		link, err := s.store.St.GetLink(shortUID)
+		if link.UserID != userID || errors.Is(err, storage.ErrShortURLRemoved) {
+			continue
+		}
+
+		items = append(items, webhook.LinkData{ShortURL: s.domains.shortURL(shortUID), OriginalURL: link.LongURL})
+	}
+
+	return items
+}
+
+// emit adds the deliveries of the events to the webhooks of the user
+// to the outbox before returning, so the events aren't lost
+// if the server stops.
+func (s *Service) emit(userID, event string, items ...webhook.LinkData) error {
+	if s.webhooks == nil || len(items) == 0 {
+		return nil
+	}
+
+	deliveries, err := s.deliveries(userID, event, items...)
+	if err != nil {
+		return fmt.Errorf("webhook event %s: %w", event, err)
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.store.St.AddDeliveries(deliveries...)
+}
+
+// deliveries returns the deliveries of the events to the webhooks
+// of the user subscribed to them. The click event is delivered only
+// to the webhooks whose threshold equals the number of clicks.
+func (s *Service) deliveries(userID, event string, items ...webhook.LinkData) ([]storage.Delivery, error) {
+	if userID == "" {
+		return nil, nil
+	}
+
+	hooks, err := s.hooks.get(userID, s.store.St.ListWebhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []storage.Delivery
+
+	for _, hook := range hooks {
+		if !subscribed(hook, event) {
+			continue
+		}
+
+		for _, item := range items {
+			if event == webhook.EventLinkClicks {
+				if item.Clicks != hook.ClickThreshold {
+					continue
+				}
+
+				item.Threshold = hook.ClickThreshold
+			}
+
+			delivery, err := webhook.NewDelivery(hook.ID, event, item)
+			if err != nil {
+				return nil, err
+			}
+
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries, nil
+}
+
+// subscribed returns true if any webhook of the user is subscribed to the event.
+func (s *Service) subscribed(userID, event string) bool {
+	if userID == "" {
+		return false
+	}
+
+	hooks, err := s.hooks.get(userID, s.store.St.ListWebhooks)
+	if err != nil {
+		return false
+	}
+
+	for _, hook := range hooks {
+		if subscribed(hook, event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// webhook returns the webhook of the user by its ID,
+// the webhooks of other users aren't found.
+func (s *Service) webhook(userID, id string) (storage.Webhook, error) {
+	if userID == "" {
+		return storage.Webhook{}, newError(ErrUnauthorized, storage.ErrUserIDNotValid)
+	}
+
+	hook, err := s.store.St.GetWebhook(id)
+	if err != nil {
+		return storage.Webhook{}, wrap(err)
+	}
+
+	if hook.UserID != userID {
+		return storage.Webhook{}, wrap(storage.ErrWebhookNotFound)
+	}
+
+	return hook, nil
+}
+
+// subscribed returns true if the webhook is subscribed to the event.
+func subscribed(hook storage.Webhook, event string) bool {
+	for _, value := range hook.Events {
+		if value == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeEvents checks the events and removes the duplicates.
+func normalizeEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, newError(ErrInvalidInput, ErrInvalidEvent)
+	}
+
+	out := make([]string, 0, len(webhook.Events))
+
+	for _, known := range webhook.Events {
+		for _, event := range events {
+			if event == known {
+				out = append(out, known)
+
+				break
+			}
+		}
+	}
+
+	for _, event := range events {
+		if !subscribed(storage.Webhook{Events: out}, event) {
+			return nil, newError(ErrInvalidInput, ErrInvalidEvent)
+		}
+	}
+
+	return out, nil
+}
+
+// newHookCache returns a pointer of hookCache.
+func newHookCache() *hookCache {
+	return &hookCache{items: make(map[string]hookCacheItem)}
+}
+
+// get returns the cached webhooks of the user, the expired ones are loaded.
+func (c *hookCache) get(userID string, load func(userID string) ([]storage.Webhook, error)) ([]storage.Webhook, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	item, ok := c.items[userID]
+	c.mu.Unlock()
+
+	if ok && now.Before(item.expiresAt) {
+		return item.hooks, nil
+	}
+
+	hooks, err := load(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.items) >= hookCacheSize {
+		for key, value := range c.items {
+			if !now.Before(value.expiresAt) {
+				delete(c.items, key)
+			}
+		}
+	}
+
+	c.items[userID] = hookCacheItem{expiresAt: now.Add(hookCacheTTL), hooks: hooks}
+
+	return hooks, nil
+}
+
+// forget removes the cached webhooks of the user after their change.
+func (c *hookCache) forget(userID string) {
+	c.mu.Lock()
+	delete(c.items, userID)
+	c.mu.Unlock()
+}
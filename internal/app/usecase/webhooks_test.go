@@ -0,0 +1,173 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+	"github.com/alaleks/shortener/internal/app/webhook"
+)
+
+func TestAddWebhook(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	service := usecase.New(&storage.Store{St: storage.NewDefault(appConf)}, appConf, nil)
+
+	// данные для теста
+	tests := []struct {
+		name   string
+		userID string
+		input  usecase.WebhookInput
+		err    error
+	}{
+		{
+			name: "valid", userID: "1",
+			input: usecase.WebhookInput{URL: "https://crm.example.com/hook", Events: []string{"link.clicks"}},
+		},
+		{
+			name: "unauthorized", userID: "",
+			input: usecase.WebhookInput{URL: "https://crm.example.com/hook", Events: []string{"link.clicks"}},
+			err:   usecase.ErrUnauthorized,
+		},
+		{
+			name: "relative URL", userID: "1",
+			input: usecase.WebhookInput{URL: "/hook", Events: []string{"link.clicks"}},
+			err:   usecase.ErrInvalidWebhookURL,
+		},
+		{
+			name: "unknown event", userID: "1",
+			input: usecase.WebhookInput{URL: "https://crm.example.com/hook", Events: []string{"link.updated"}},
+			err:   usecase.ErrInvalidEvent,
+		},
+		{
+			name: "no events", userID: "1",
+			input: usecase.WebhookInput{URL: "https://crm.example.com/hook"},
+			err:   usecase.ErrInvalidEvent,
+		},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			hook, err := service.AddWebhook(item.userID, item.input)
+			if !errors.Is(err, item.err) {
+				t.Fatalf("error should be %v but received %v", item.err, err)
+			}
+
+			if err == nil && (hook.ID == "" || hook.Secret == "" || hook.ClickThreshold != 1) {
+				t.Errorf("webhook should have ID, secret and default threshold but received %+v", hook)
+			}
+		})
+	}
+}
+
+func TestWebhookEvents(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	store := storage.NewDefault(appConf)
+	service := usecase.New(&storage.Store{St: store}, appConf, nil)
+	service.SetDispatcher(webhook.New(store, webhook.Options{}))
+
+	// данные для теста
+	const userID = "1"
+
+	hook, err := service.AddWebhook(userID, usecase.WebhookInput{
+		URL:            "https://crm.example.com/hook",
+		Events:         []string{webhook.EventLinkCreated, webhook.EventLinkClicks, webhook.EventLinkDeleted, webhook.EventLinkExpired},
+		ClickThreshold: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ссылка другого пользователя не вызывает событий
+	if _, err := service.Shorten("2", "https://example.com/other", usecase.ShortenOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	shortURL, err := service.Shorten(userID, "https://example.com/campaign", usecase.ShortenOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid := shortURL[len(appConf.GetBaseURL()):]
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.Resolve(uid, usecase.Visit{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := service.Delete(userID, uid); err != nil {
+		t.Fatal(err)
+	}
+
+	// удаленная ссылка не удаляется повторно
+	if err := service.Delete(userID, uid); err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(50 * time.Millisecond)
+	if result := service.Import(userID, usecase.ImportItem{
+		OriginalURL: "https://example.com/expiring",
+		ExpiresAt:   &expiresAt,
+	}); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := service.SweepExpired(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{
+		webhook.EventLinkCreated: 2,
+		webhook.EventLinkClicks:  1,
+		webhook.EventLinkDeleted: 1,
+		webhook.EventLinkExpired: 1,
+	}
+
+	// события добавляются в очередь до возврата из методов сервиса
+	deliveries, err := service.WebhookDeliveries(userID, hook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(map[string]int)
+
+	for _, delivery := range deliveries {
+		events[delivery.Event]++
+
+		var payload struct {
+			Data webhook.LinkData `json:"data"`
+		}
+
+		if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+			t.Fatal(err)
+		}
+
+		if delivery.Event == webhook.EventLinkClicks && (payload.Data.Clicks != 2 || payload.Data.ShortURL != shortURL) {
+			t.Errorf("click event should be sent on the second click of %s but received %+v", shortURL, payload.Data)
+		}
+	}
+
+	for event, count := range want {
+		if events[event] != count {
+			t.Errorf("number of %s events should be %d but received %d", event, count, events[event])
+		}
+	}
+
+	// журнал доставок чужого вебхука не доступен
+	if _, err := service.WebhookDeliveries("2", hook.ID); !errors.Is(err, usecase.ErrNotFound) {
+		t.Errorf("error should be %v but received %v", usecase.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,460 @@
+// Package webhook delivers the events of the short URLs
+// to the webhooks of the users.
+//
+// The events are added to the outbox in the storage and the dispatcher
+// sends them in rounds as signed JSON POSTs. The delivery is accepted
+// with 2xx status code, otherwise it's retried with exponential backoff
+// until the attempts run out. The body is signed with HMAC-SHA256
+// by the secret of the webhook:
+//
+//	Webhook-Signature: sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+//
+// where timestamp is the value of the Webhook-Timestamp header
+// (Unix time in seconds), so the receivers can reject the replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/linkcheck"
+	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/storage/pool"
+)
+
+// Events of the short URLs.
+const (
+	EventLinkCreated = "link.created"
+	EventLinkDeleted = "link.deleted"
+	EventLinkExpired = "link.expired"
+	// EventLinkClicks is sent when the short URL reaches
+	// the click threshold of the webhook.
+	EventLinkClicks = "link.clicks"
+	// EventPing is sent by the test of the webhook.
+	EventPing = "ping"
+)
+
+// Headers of the deliveries.
+const (
+	HeaderEvent     = "Webhook-Event"
+	HeaderDelivery  = "Webhook-Delivery"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+// Default settings of the dispatcher.
+const (
+	DefaultInterval    = 5 * time.Second
+	DefaultConcurrency = 4
+	DefaultTimeout     = 10 * time.Second
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 10
+	DefaultBaseDelay   = 30 * time.Second
+	DefaultMaxDelay    = 6 * time.Hour
+	// DefaultRetention is the time during which
+	// the finished deliveries are kept in the log.
+	DefaultRetention = 30 * 24 * time.Hour
+)
+
+// sizeDeliveryID is the length of the generated ID of the delivery.
+const sizeDeliveryID = 20
+
+// userAgent identifies the requests of the dispatcher.
+const userAgent = "shortener-webhook/1.0"
+
+// maxBodyRead is the number of bytes of the response read before
+// closing it, so the connection can be reused.
+const maxBodyRead = 64 << 10
+
+// Events are the events the webhooks can subscribe to.
+var Events = []string{EventLinkCreated, EventLinkDeleted, EventLinkExpired, EventLinkClicks}
+
+// ErrUnexpectedStatus is an indicator that the webhook responded with non-2xx status code.
+var ErrUnexpectedStatus = errors.New("webhook responded with non-2xx status")
+
+// Store is the part of the storage used by the dispatcher.
+type Store interface {
+	GetWebhook(id string) (storage.Webhook, error)
+	AddDeliveries(deliveries ...storage.Delivery) error
+	ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]storage.Delivery, error)
+	UpdateDelivery(delivery storage.Delivery) error
+	PruneDeliveries(before time.Time) error
+}
+
+// Payload represents the body of the delivery.
+type Payload struct {
+	CreatedAt time.Time `json:"created_at"`
+	// ID is the ID of the delivery, the receivers can use it
+	// to skip the retried deliveries they have accepted.
+	ID    string `json:"id"`
+	Event string `json:"event"`
+	Data  any    `json:"data"`
+}
+
+// LinkData represents the data of the event of the short URL.
+type LinkData struct {
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ShortURL    string     `json:"short_url"`
+	OriginalURL string     `json:"original_url,omitempty"`
+	Clicks      uint       `json:"clicks,omitempty"`
+	Threshold   uint       `json:"threshold,omitempty"`
+}
+
+// Options represents the settings of the dispatcher,
+// the zero values are replaced by the defaults.
+type Options struct {
+	// Client sends the requests, by default the client of the link
+	// checker with DefaultTimeout is used, which refuses to connect
+	// to the non-public addresses. Redirects are not followed.
+	Client *http.Client
+	// Interval is the period of the rounds.
+	Interval time.Duration
+	// Concurrency is the maximum number of simultaneous requests.
+	Concurrency int
+	// BatchSize is the maximum number of deliveries sent in one round.
+	BatchSize int
+	// MaxAttempts is the number of attempts after which
+	// the delivery is failed.
+	MaxAttempts int
+	// BaseDelay and MaxDelay are the delays of the first retry
+	// and the limit of the delay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Sweep is called at the start of each round,
+	// e.g. to emit the events of the expired short URLs.
+	Sweep func() error
+}
+
+// Dispatcher sends the deliveries of the outbox in rounds.
+type Dispatcher struct {
+	store       Store
+	client      *http.Client
+	sweep       func() error
+	interval    time.Duration
+	concurrency int
+	batchSize   int
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	// running is set while the round is queued or in progress.
+	running atomic.Bool
+}
+
+// New returns a pointer of Dispatcher.
+func New(store Store, opts Options) *Dispatcher {
+	if opts.Client == nil {
+		client := *linkcheck.NewClient(DefaultTimeout)
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+
+		opts.Client = &client
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = DefaultBaseDelay
+	}
+
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = DefaultMaxDelay
+	}
+
+	return &Dispatcher{
+		store:       store,
+		client:      opts.Client,
+		sweep:       opts.Sweep,
+		interval:    opts.Interval,
+		concurrency: opts.Concurrency,
+		batchSize:   opts.BatchSize,
+		maxAttempts: opts.MaxAttempts,
+		baseDelay:   opts.BaseDelay,
+		maxDelay:    opts.MaxDelay,
+	}
+}
+
+// NewDelivery returns the pending delivery of the event to the webhook
+// with the payload, it's due immediately.
+func NewDelivery(webhookID, event string, data any) (storage.Delivery, error) {
+	now := time.Now()
+	id := service.GenUID(sizeDeliveryID)
+
+	payload, err := json.Marshal(Payload{CreatedAt: now, ID: id, Event: event, Data: data})
+	if err != nil {
+		return storage.Delivery{}, err
+	}
+
+	return storage.Delivery{
+		CreatedAt:     now,
+		NextAttemptAt: now,
+		ID:            id,
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       payload,
+		Status:        storage.DeliveryPending,
+	}, nil
+}
+
+// Sign returns the signature of the body sent at the time (Unix seconds).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if the signature of the body is valid,
+// it's used by the receivers of the deliveries.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Run adds a round of the deliveries to the pool at the start and then
+// at the interval until the context is done.
+//
+// The pool executes the tasks one by one, so the round is limited
+// by the batch size and the next round is added only when the previous
+// one is finished. The errors of the rounds are logged by the pool.
+// The round can last up to BatchSize / Concurrency timeouts of the client,
+// so the pool must be dedicated to the dispatcher and not shared
+// with the tasks of the requests.
+func (d *Dispatcher) Run(ctx context.Context, workers *pool.Pool) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if d.running.CompareAndSwap(false, true) {
+			workers.AddTask(func() error {
+				defer d.running.Store(false)
+
+				return d.round(ctx)
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// round calls the sweep, sends the due deliveries
+// and prunes the old finished ones.
+func (d *Dispatcher) round(ctx context.Context) error {
+	var errs []error
+
+	if d.sweep != nil {
+		if err := d.sweep(); err != nil {
+			errs = append(errs, fmt.Errorf("webhook sweep: %w", err))
+		}
+	}
+
+	if _, err := d.DeliverDue(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("webhook deliveries: %w", err))
+	}
+
+	if err := d.store.PruneDeliveries(time.Now().Add(-DefaultRetention)); err != nil {
+		errs = append(errs, fmt.Errorf("webhook prune: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeliverDue sends the pending deliveries whose attempt is due
+// and returns the number of the sent deliveries.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimDeliveries(time.Now(), d.lease(), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errSave error
+		slots   = make(chan struct{}, d.concurrency)
+	)
+
+	for _, delivery := range deliveries {
+		delivery := delivery
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+
+			return 0, ctx.Err()
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			if _, err := d.Deliver(ctx, delivery); err != nil && !errors.Is(err, storage.ErrWebhookNotFound) {
+				mu.Lock()
+				errSave = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return len(deliveries), errSave
+}
+
+// Ping adds the ping delivery of the webhook to the outbox and attempts it
+// immediately, the failed ping is retried as other deliveries.
+func (d *Dispatcher) Ping(ctx context.Context, hook storage.Webhook) (storage.Delivery, error) {
+	delivery, err := NewDelivery(hook.ID, EventPing, map[string]string{"webhook_id": hook.ID})
+	if err != nil {
+		return delivery, err
+	}
+
+	// the rounds don't claim the delivery before the attempt.
+	delivery.NextAttemptAt = delivery.CreatedAt.Add(d.lease())
+
+	if err := d.store.AddDeliveries(delivery); err != nil {
+		return delivery, err
+	}
+
+	return d.Deliver(ctx, delivery)
+}
+
+// Deliver attempts the delivery and saves the result of the attempt,
+// the updated delivery is returned. The delivery of the deleted webhook
+// causes storage.ErrWebhookNotFound.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery storage.Delivery) (storage.Delivery, error) {
+	hook, err := d.store.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		return delivery, err
+	}
+
+	statusCode, err := d.send(ctx, hook, delivery)
+	if ctx.Err() != nil {
+		return delivery, ctx.Err()
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseCode = statusCode
+	delivery.Error = ""
+
+	switch {
+	case err == nil:
+		delivery.Status = storage.DeliveryDelivered
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = storage.DeliveryFailed
+		delivery.Error = describe(err)
+	default:
+		delivery.NextAttemptAt = now.Add(d.Backoff(delivery.Attempts))
+		delivery.Error = describe(err)
+	}
+
+	return delivery, d.store.UpdateDelivery(delivery)
+}
+
+// Backoff returns the delay of the retry after the attempt:
+// the base delay doubled after each attempt up to the limit.
+func (d *Dispatcher) Backoff(attempt int) time.Duration {
+	delay := d.baseDelay
+
+	for i := 1; i < attempt && delay < d.maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > d.maxDelay {
+		return d.maxDelay
+	}
+
+	return delay
+}
+
+// lease returns the time for which the claimed deliveries are postponed,
+// so they are retried by another dispatcher if this one has stopped.
+func (d *Dispatcher) lease() time.Duration {
+	return 2*d.client.Timeout + d.interval
+}
+
+// send posts the payload of the delivery to the webhook
+// and returns the status code of the response.
+func (d *Dispatcher) send(ctx context.Context, hook storage.Webhook, delivery storage.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyRead))
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// describe returns the reason of the failed request without the URL.
+func describe(err error) string {
+	var urlErr *url.Error
+
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,251 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/webhook"
+)
+
+const secret = "whsec_test"
+
+// receiver represents the test webhook which checks the signatures
+// and records the accepted payloads.
+type receiver struct {
+	server   *httptest.Server
+	payloads []webhook.Payload
+	mu       sync.Mutex
+}
+
+// newReceiver returns the test webhook: /ok accepts the deliveries,
+// /fail responds with 500 and /redirect redirects to /ok.
+func newReceiver(t *testing.T) *receiver {
+	t.Helper()
+
+	rec := &receiver{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+
+		if !webhook.Verify(secret, timestamp, body, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		var payload webhook.Payload
+		if err := json.Unmarshal(body, &payload); err != nil || payload.ID != r.Header.Get(webhook.HeaderDelivery) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		rec.mu.Lock()
+		rec.payloads = append(rec.payloads, payload)
+		rec.mu.Unlock()
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusTemporaryRedirect)
+	})
+
+	rec.server = httptest.NewServer(mux)
+	t.Cleanup(rec.server.Close)
+
+	return rec
+}
+
+// accepted returns the payloads accepted by the webhook.
+func (rec *receiver) accepted() []webhook.Payload {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return append([]webhook.Payload(nil), rec.payloads...)
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	body := []byte(`{"event":"ping"}`)
+	signature := webhook.Sign(secret, 1700000000, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		valid     bool
+	}{
+		{name: "valid", secret: secret, timestamp: 1700000000, body: body, valid: true},
+		{name: "wrong secret", secret: "other", timestamp: 1700000000, body: body},
+		{name: "replayed later", secret: secret, timestamp: 1700000060, body: body},
+		{name: "changed body", secret: secret, timestamp: 1700000000, body: []byte(`{"event":"pong"}`)},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			if valid := webhook.Verify(item.secret, item.timestamp, item.body, signature); valid != item.valid {
+				t.Errorf("signature should be valid %v but received %v", item.valid, valid)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	dispatcher := webhook.New(nil, webhook.Options{BaseDelay: time.Minute, MaxDelay: time.Hour})
+
+	tests := []struct {
+		attempt int
+		delay   time.Duration
+	}{
+		{attempt: 1, delay: time.Minute},
+		{attempt: 2, delay: 2 * time.Minute},
+		{attempt: 4, delay: 8 * time.Minute},
+		{attempt: 7, delay: time.Hour},
+		{attempt: 100, delay: time.Hour},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(strconv.Itoa(item.attempt), func(t *testing.T) {
+			t.Parallel()
+
+			if delay := dispatcher.Backoff(item.attempt); delay != item.delay {
+				t.Errorf("delay should be %v but received %v", item.delay, delay)
+			}
+		})
+	}
+}
+
+func TestDeliverDue(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	rec := newReceiver(t)
+	store := storage.NewDefault(config.New(config.Options{}))
+	dispatcher := webhook.New(store, webhook.Options{
+		Client:      noRedirects(rec.server.Client()),
+		MaxAttempts: 2,
+		BaseDelay:   time.Minute,
+	})
+
+	deliveries := make(map[string]storage.Delivery)
+
+	for _, path := range []string{"/ok", "/fail", "/redirect"} {
+		hook, err := store.AddWebhook(storage.Webhook{UserID: "1", URL: rec.server.URL + path, Secret: secret})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		delivery, err := webhook.NewDelivery(hook.ID, webhook.EventLinkClicks,
+			webhook.LinkData{ShortURL: "http://localhost:8080/abc", Clicks: 1, Threshold: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.AddDeliveries(delivery); err != nil {
+			t.Fatal(err)
+		}
+
+		deliveries[path] = delivery
+	}
+
+	sent, err := dispatcher.DeliverDue(context.Background())
+	if err != nil || sent != len(deliveries) {
+		t.Fatalf("number of sent deliveries should be %d but received %d (%v)", len(deliveries), sent, err)
+	}
+
+	if payloads := rec.accepted(); len(payloads) != 1 || payloads[0].ID != deliveries["/ok"].ID ||
+		payloads[0].Event != webhook.EventLinkClicks {
+		t.Errorf("receiver should accept delivery %s but received %+v", deliveries["/ok"].ID, payloads)
+	}
+
+	log, err := store.ListDeliveries(deliveries["/ok"].WebhookID, 10)
+	if err != nil || len(log) != 1 || log[0].Status != storage.DeliveryDelivered || log[0].DeliveredAt == nil {
+		t.Errorf("delivery should be delivered but received %+v (%v)", log, err)
+	}
+
+	// неудачная доставка повторяется позже
+	for _, path := range []string{"/fail", "/redirect"} {
+		log, err := store.ListDeliveries(deliveries[path].WebhookID, 10)
+		if err != nil || len(log) != 1 || log[0].Status != storage.DeliveryPending || log[0].Attempts != 1 ||
+			!log[0].NextAttemptAt.After(time.Now().Add(50*time.Second)) || log[0].Error == "" {
+			t.Errorf("delivery to %s should be retried in a minute but received %+v (%v)", path, log, err)
+		}
+	}
+
+	if sent, err = dispatcher.DeliverDue(context.Background()); err != nil || sent != 0 {
+		t.Errorf("number of sent deliveries should be 0 but received %d (%v)", sent, err)
+	}
+
+	// последняя попытка завершает доставку с ошибкой
+	failed, err := store.ListDeliveries(deliveries["/fail"].WebhookID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed[0], err = dispatcher.Deliver(context.Background(), failed[0])
+	if err != nil || failed[0].Status != storage.DeliveryFailed || failed[0].ResponseCode != http.StatusInternalServerError {
+		t.Errorf("delivery should be failed but received %+v (%v)", failed[0], err)
+	}
+
+	// доставки удаленного вебхука не отправляются
+	if err := store.DelWebhook("1", deliveries["/redirect"].WebhookID); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dispatcher.Deliver(context.Background(), deliveries["/redirect"])
+	if !errors.Is(err, storage.ErrWebhookNotFound) {
+		t.Errorf("error should be %v but received %v", storage.ErrWebhookNotFound, err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+	// данные для теста
+	rec := newReceiver(t)
+	store := storage.NewDefault(config.New(config.Options{}))
+	dispatcher := webhook.New(store, webhook.Options{Client: rec.server.Client()})
+
+	hook, err := store.AddWebhook(storage.Webhook{UserID: "1", URL: rec.server.URL + "/ok", Secret: secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivery, err := dispatcher.Ping(context.Background(), hook)
+	if err != nil || delivery.Status != storage.DeliveryDelivered || delivery.ResponseCode != http.StatusOK {
+		t.Fatalf("ping should be delivered but received %+v (%v)", delivery, err)
+	}
+
+	if payloads := rec.accepted(); len(payloads) != 1 || payloads[0].Event != webhook.EventPing {
+		t.Errorf("receiver should accept ping but received %+v", payloads)
+	}
+}
+
+// noRedirects returns the copy of the client which doesn't follow redirects
+// as the default client of the dispatcher.
+func noRedirects(client *http.Client) *http.Client {
+	out := *client
+	out.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &out
+}
@@ -0,0 +1,74 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/alaleks/shortener/internal/app/usecase"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// statusError maps the domain errors to the grpc status errors.
+//
+// The gateway maps the status codes to the HTTP status codes,
+// e.g. NotFound to 404 and InvalidArgument to 400. The short URL
+// that isn't resolved any more is FailedPrecondition, which
+// the gateway maps to 410 as the web server does, see httpError.
+func statusError(err error) error {
+	code := codes.Internal
+
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		code = codes.InvalidArgument
+	case errors.Is(err, usecase.ErrUnauthorized):
+		code = codes.Unauthenticated
+	case errors.Is(err, usecase.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, usecase.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, usecase.ErrGone):
+		code = codes.FailedPrecondition
+	case errors.Is(err, usecase.ErrConflict):
+		code = codes.AlreadyExists
+	}
+
+	return status.Error(code, err.Error())
+}
+
+// httpError writes the error of the gateway, FailedPrecondition
+// is written with 410, the other codes with the default mapping.
+func httpError(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler,
+	writer http.ResponseWriter, req *http.Request, err error,
+) {
+	if status.Code(err) == codes.FailedPrecondition {
+		err = &runtime.HTTPStatusError{HTTPStatus: http.StatusGone, Err: err}
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, writer, req, err)
+}
+
+// batchRejectedError returns the InvalidArgument status error
+// with the invalid items of the rejected atomic batch in the details.
+func batchRejectedError(results []usecase.BatchResult, err error) error {
+	var details errdetails.BadRequest
+
+	for _, item := range results {
+		if item.Err != nil {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       item.CorID,
+				Description: item.Err.Error(),
+			})
+		}
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&details)
+	if detailsErr != nil {
+		return statusError(err)
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,147 @@
+package proto
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// OpenAPI is the OpenAPI (Swagger 2.0) specification of the REST/JSON gateway
+// generated from the HTTP annotations of shortener.proto.
+//
+//go:embed shortener.swagger.json
+var OpenAPI []byte
+
+const pathOpenAPI = "/openapi.json"
+
+// NewGateway returns the REST/JSON gateway of the grpc service mounted on the prefix.
+//
+// The gateway calls the server methods in-process through the interceptors
+// passed, so both transports share one implementation and error mapping.
+// The user ID and the client IP address are taken from the request context,
+// which is filled by the web server middleware.
+// The OpenAPI specification is served at GET <prefix>/openapi.json.
+func NewGateway(ctx context.Context, server ShortenerServer, prefix string,
+	interceptors ...grpc.UnaryServerInterceptor,
+) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		// the Idempotency-Key header is passed as the idempotency-key metadata.
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			if http.CanonicalHeaderKey(key) == "Idempotency-Key" {
+				return mdIdempotencyKey, true
+			}
+
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+		runtime.WithErrorHandler(httpError),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+
+	if len(interceptors) > 0 {
+		server = &interceptedServer{ShortenerServer: server, interceptor: chainUnary(interceptors)}
+	}
+
+	if err := RegisterShortenerHandlerServer(ctx, mux, server); err != nil {
+		return nil, err
+	}
+
+	err := mux.HandlePath(http.MethodGet, pathOpenAPI,
+		func(writer http.ResponseWriter, req *http.Request, _ map[string]string) {
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write(OpenAPI)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return http.StripPrefix(prefix, mux), nil
+}
+
+// interceptedServer calls the unary methods of the server through
+// the interceptor as the grpc server does, the streams are not served
+// by the gateway.
+type interceptedServer struct {
+	ShortenerServer
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// ShortenURL implements ShortenerServer.
+func (s *interceptedServer) ShortenURL(ctx context.Context, in *ShortenRequest) (*ShortenResponse, error) {
+	return intercept(ctx, s, Shortener_ShortenURL_FullMethodName, in, s.ShortenerServer.ShortenURL)
+}
+
+// GetStat implements ShortenerServer.
+func (s *interceptedServer) GetStat(ctx context.Context, in *StatRequest) (*StatResponse, error) {
+	return intercept(ctx, s, Shortener_GetStat_FullMethodName, in, s.ShortenerServer.GetStat)
+}
+
+// GetUsersURL implements ShortenerServer.
+func (s *interceptedServer) GetUsersURL(ctx context.Context, in *UsersURLRequest) (*UsersURL, error) {
+	return intercept(ctx, s, Shortener_GetUsersURL_FullMethodName, in, s.ShortenerServer.GetUsersURL)
+}
+
+// ShortenURLBatch implements ShortenerServer.
+func (s *interceptedServer) ShortenURLBatch(ctx context.Context, in *ShortenBatchRequest) (*ShortenBatchResponse, error) {
+	return intercept(ctx, s, Shortener_ShortenURLBatch_FullMethodName, in, s.ShortenerServer.ShortenURLBatch)
+}
+
+// ShortenDelete implements ShortenerServer.
+func (s *interceptedServer) ShortenDelete(ctx context.Context, in *ShortenDeleteRequest) (*Empty, error) {
+	return intercept(ctx, s, Shortener_ShortenDelete_FullMethodName, in, s.ShortenerServer.ShortenDelete)
+}
+
+// StatsInternal implements ShortenerServer.
+func (s *interceptedServer) StatsInternal(ctx context.Context, in *Empty) (*StatsInternalReponse, error) {
+	return intercept(ctx, s, Shortener_StatsInternal_FullMethodName, in, s.ShortenerServer.StatsInternal)
+}
+
+// SetRules implements ShortenerServer.
+func (s *interceptedServer) SetRules(ctx context.Context, in *SetRulesRequest) (*Empty, error) {
+	return intercept(ctx, s, Shortener_SetRules_FullMethodName, in, s.ShortenerServer.SetRules)
+}
+
+// SetVariants implements ShortenerServer.
+func (s *interceptedServer) SetVariants(ctx context.Context, in *SetVariantsRequest) (*Empty, error) {
+	return intercept(ctx, s, Shortener_SetVariants_FullMethodName, in, s.ShortenerServer.SetVariants)
+}
+
+// intercept calls the method of the server through the interceptor.
+func intercept[Req, Res any](ctx context.Context, s *interceptedServer, fullMethod string, in Req,
+	method func(context.Context, Req) (Res, error),
+) (Res, error) {
+	info := &grpc.UnaryServerInfo{Server: s.ShortenerServer, FullMethod: fullMethod}
+
+	out, err := s.interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+		return method(ctx, req.(Req))
+	})
+
+	res, _ := out.(Res)
+
+	return res, err
+}
+
+// chainUnary returns the interceptor calling the interceptors in order.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,114 @@
+package proto_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/interceptor"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+	pb "github.com/alaleks/shortener/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGateway(t *testing.T) {
+	t.Parallel()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	st := storage.InitStore(appConf, logger.NewLogger())
+	authorization := auth.TurnOn(st.St, appConf.GetSecretKey())
+
+	metrics := interceptor.NewMetrics()
+	recovery := interceptor.NewRecovery(logger.NewLogger())
+
+	gateway, err := pb.NewGateway(context.Background(), pb.New(usecase.New(st, appConf, nil), logger.NewLogger()), "/v1",
+		metrics.UnaryInterceptor, recovery.UnaryInterceptor)
+	if err != nil {
+		t.Fatalf("failed create gateway: %s", err)
+	}
+
+	handler := authorization.Authorization(gateway)
+
+	// сокращаем ссылку
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/shorten",
+		strings.NewReader(`{"url":"https://github.com/alaleks/shortener"}`)))
+
+	var shorten struct {
+		Result  string `json:"result"`
+		Success bool   `json:"success"`
+	}
+
+	if err := json.NewDecoder(rec.Body).Decode(&shorten); err != nil || !shorten.Success {
+		t.Fatalf("shorten should be successful but received %d: %v", rec.Code, err)
+	}
+
+	uid := shorten.Result[strings.LastIndex(shorten.Result, "/")+1:]
+
+	// запросы шлюза проходят через перехватчики
+	if stats := metrics.Snapshot()[pb.Shortener_ShortenURL_FullMethodName]; stats.Count != 1 || stats.Codes["OK"] != 1 {
+		t.Errorf("gateway request should be counted by metrics but received %+v", stats)
+	}
+
+	// данные для теста
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+	}{
+		{name: "stat", method: http.MethodGet, path: "/v1/stat/" + uid, code: http.StatusOK},
+		{name: "stat unknown uid", method: http.MethodGet, path: "/v1/stat/unknown", code: http.StatusNotFound},
+		{name: "internal stats denied", method: http.MethodGet, path: "/v1/internal/stats", code: http.StatusForbidden},
+		{name: "openapi", method: http.MethodGet, path: "/v1/openapi.json", code: http.StatusOK},
+		{name: "unknown path", method: http.MethodGet, path: "/v1/unknown", code: http.StatusNotFound},
+	}
+
+	for _, v := range tests {
+		item := v
+		t.Run(item.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(item.method, item.path, nil))
+
+			if rec.Code != item.code {
+				t.Errorf("status code should be %d but received %d", item.code, rec.Code)
+			}
+		})
+	}
+}
+
+// goneServer returns the status of the short URL that isn't resolved any more.
+type goneServer struct {
+	pb.UnimplementedShortenerServer
+}
+
+// GetStat implements pb.ShortenerServer.
+func (goneServer) GetStat(context.Context, *pb.StatRequest) (*pb.StatResponse, error) {
+	return nil, status.Error(codes.FailedPrecondition, usecase.ErrGone.Error())
+}
+
+func TestGatewayGone(t *testing.T) {
+	t.Parallel()
+
+	gateway, err := pb.NewGateway(context.Background(), goneServer{}, "/v1")
+	if err != nil {
+		t.Fatalf("failed create gateway: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+	gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/stat/removed", nil))
+
+	if rec.Code != http.StatusGone {
+		t.Errorf("status code should be %d but received %d", http.StatusGone, rec.Code)
+	}
+}
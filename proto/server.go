@@ -1,62 +1,51 @@
 // Package service implements support protobuf for gprc server.
 package proto
 
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative --grpc-gateway_out=. --grpc-gateway_opt=paths=source_relative,allow_delete_body=true --openapiv2_out=. --openapiv2_opt=allow_delete_body=true shortener.proto
+
 import (
 	context "context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
-	"encoding/binary"
 	"errors"
-	"fmt"
-	"net/netip"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/alaleks/shortener/internal/app/logger"
-	"github.com/alaleks/shortener/internal/app/service"
+	"github.com/alaleks/shortener/internal/app/qr"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/realip"
 	"github.com/alaleks/shortener/internal/app/storage"
-	"google.golang.org/grpc"
+	"github.com/alaleks/shortener/internal/app/usecase"
 	codes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	status "google.golang.org/grpc/status"
 )
 
-const (
-	mdAuthName  = "Authorization"
-	xrealIPName = "X-Real-IP"
-)
+// mdIdempotencyKey is the metadata key of the idempotent request.
+const mdIdempotencyKey = "idempotency-key"
 
 // List of typical errors.
 var (
-	ErrInvalidMetadataAuth = errors.New("invalid authorization token in metadata")
-	ErrorEmptyData         = errors.New("request does not contains data")
-	ErrEmptyBatch          = errors.New("URL batching error, please check the source data")
-	ErrorAccessDenied      = errors.New("access denied")
+	ErrorEmptyData = errors.New("request does not contains data")
 )
 
 // Define a server struct that implements the grpc server interface.
 type (
 	Server struct {
-		srv            UnsafeShortenerServer
-		store          *storage.Store
-		log            *logger.AppLogger
-		trustedSubnets netip.Prefix
-		secret         []byte
+		srv     UnsafeShortenerServer
+		service *usecase.Service
+		log     *logger.AppLogger
 	}
 )
 
 // New creates a new grpc server.
-func New(st *storage.Store, log *logger.AppLogger,
-	secret []byte, trustedSubnet string) *Server {
+//
+// The user is authorized by the auth interceptor, which
+// must be registered for methods that require the user ID.
+func New(service *usecase.Service, log *logger.AppLogger) *Server {
 	server := Server{
-		srv:    UnimplementedShortenerServer{},
-		store:  st,
-		secret: secret,
-	}
-
-	if network, err := netip.ParsePrefix(trustedSubnet); err == nil {
-		server.trustedSubnets = network
+		srv:     UnimplementedShortenerServer{},
+		service: service,
+		log:     log,
 	}
 
 	return &server
@@ -64,31 +53,53 @@ func New(st *storage.Store, log *logger.AppLogger,
 
 // ShortenURL implements URL shortening.
 func (s *Server) ShortenURL(ctx context.Context, in *ShortenRequest) (*ShortenResponse, error) {
-	if err := service.IsURL(in.Url); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+	userID, err := definitionUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shortURL, err := s.service.Shorten(userID, in.Url, usecase.ShortenOptions{
+		Tags:         in.Tags,
+		Folder:       in.Folder,
+		UTM:          storageUTM(in.Utm),
+		ForwardQuery: in.ForwardQuery,
+		Rules:        storageRules(in.Rules),
+		Variants:     storageVariants(in.Variants),
+		Password:     in.Password,
+		MaxClicks:    uint(in.MaxClicks),
+		Domain:       in.Domain,
+	})
+	out := ShortenResponse{
+		Result:  shortURL,
+		Success: true,
 	}
 
-	userID, err := s.auth(ctx)
-	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+	// the existing short URL is returned with the error as in the web API.
+	switch {
+	case errors.Is(err, usecase.ErrConflict):
+		out.Error = err.Error()
+		out.Success = false
+	case err != nil:
+		return nil, statusError(err)
 	}
 
-	shortURL, err := s.store.St.Add(in.Url, userID)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if in.QrCode {
+		img, err := s.service.QRCode(shortURL, qr.DefaultOptions())
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		out.QrCode = img.DataURI()
 	}
 
-	return &ShortenResponse{
-		Result:  shortURL,
-		Success: true,
-	}, nil
+	return &out, nil
 }
 
 // GetStatAPI implements getting statistics on the use of a short URL.
 func (s *Server) GetStat(ctx context.Context, in *StatRequest) (*StatResponse, error) {
-	stat, err := s.store.St.Stat(in.Shortuid)
+	stat, err := s.service.Stat(in.Shortuid)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 
 	return &StatResponse{
@@ -96,30 +107,57 @@ func (s *Server) GetStat(ctx context.Context, in *StatRequest) (*StatResponse, e
 		Longurl:   stat.LongURL,
 		CreatedAt: stat.CreatedAt,
 		Usage:     uint64(stat.Usage),
+		Variants:  variants(stat.Variants),
+		Protected: stat.Protected,
+		Health:    health(stat.Health),
+		Metadata:  pageMetadata(stat.Metadata),
 	}, nil
 }
 
-// GetUsersURL returns all shortened URLs for current user.
-func (s *Server) GetUsersURL(ctx context.Context, in *Empty) (*UsersURL, error) {
-	userID, err := s.definitionUser(ctx)
+// GetUsersURL returns the shortened URLs for current user page by page.
+func (s *Server) GetUsersURL(ctx context.Context, in *UsersURLRequest) (*UsersURL, error) {
+	userID, err := definitionUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+
+	query := usecase.ListQuery{
+		Cursor:      in.Cursor,
+		Sort:        in.Sort,
+		CreatedFrom: in.CreatedFrom,
+		CreatedTo:   in.CreatedTo,
+		Domain:      in.Domain,
+		Status:      in.Status,
+		Tag:         in.Tag,
+		Folder:      in.Folder,
 	}
 
-	out, err := s.store.St.GetUrlsUser(userID)
+	if in.Broken {
+		query.Broken = strconv.FormatBool(in.Broken)
+	}
+
+	if in.Limit != 0 {
+		query.Limit = strconv.Itoa(int(in.Limit))
+	}
+
+	opts, err := query.Options()
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
+	}
+
+	page, err := s.service.ListPage(userID, opts)
+	if err != nil {
+		return nil, statusError(err)
 	}
 
 	userURLS := UsersURL{
-		Urls: make([]*UserURL, 0, len(out)),
+		Urls:       make([]*UserURL, 0, len(page.URLs)),
+		NextCursor: page.NextCursor,
+		Total:      int64(page.Total),
 	}
 
-	for _, v := range out {
-		userURLS.Urls = append(userURLS.Urls, &UserURL{
-			LongUrl:  v.LongURL,
-			ShortUrl: v.ShortUID,
-		})
+	for _, v := range page.URLs {
+		userURLS.Urls = append(userURLS.Urls, userURL(v))
 	}
 
 	return &userURLS, nil
@@ -128,87 +166,103 @@ func (s *Server) GetUsersURL(ctx context.Context, in *Empty) (*UsersURL, error)
 // ShortenURLBatch implements url batch shortening.
 func (s *Server) ShortenURLBatch(ctx context.Context, in *ShortenBatchRequest) (*ShortenBatchResponse, error) {
 	if len(in.Urls) == 0 {
-		return nil, status.Error(codes.NotFound, ErrorEmptyData.Error())
+		return nil, status.Error(codes.InvalidArgument, ErrorEmptyData.Error())
 	}
 
-	userID, err := s.definitionUser(ctx)
+	userID, err := definitionUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+		return nil, err
 	}
 
-	output, err := s.processingURLBatch(userID, in)
-	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	items := make([]usecase.BatchItem, 0, len(in.Urls))
+
+	for _, item := range in.Urls {
+		items = append(items, usecase.BatchItem{
+			CorID:       item.CorrelationId,
+			OriginalURL: item.OriginalUrl,
+		})
 	}
 
-	return output, nil
-}
+	results, err := s.service.ShortenBatch(userID, items, usecase.BatchOptions{
+		IdempotencyKey: idempotencyKey(ctx),
+		Atomic:         in.Atomic,
+	})
 
-// ShortenDelete performs deletion all shortened URLs
-func (s *Server) ShortenDelete(ctx context.Context, in *ShortenDeleteRequest) (*Empty, error) {
-	if len(in.Urls) == 0 {
-		return nil, status.Error(codes.NotFound, ErrorEmptyData.Error())
+	switch {
+	case errors.Is(err, usecase.ErrBatchRejected):
+		return nil, batchRejectedError(results, err)
+	case err != nil:
+		return nil, statusError(err)
 	}
 
-	userID, err := s.definitionUser(ctx)
-	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+	out := ShortenBatchResponse{
+		Urls: make([]*ShortenBatchResponseItem, 0, len(results)),
 	}
 
-	var data = struct {
-		userID         string
-		shortUIDForDel []string
-	}{
-		userID:         userID,
-		shortUIDForDel: in.Urls,
+	for _, item := range results {
+		out.Urls = append(out.Urls, batchResponseItem(item))
 	}
 
-	s.store.Pool.AddTask(func() error {
-		err := s.store.St.DelUrls(data.userID,
-			checkShortUID(data.shortUIDForDel...)...)
-		if err != nil {
-			return fmt.Errorf("deletion error: %w", err)
-		}
+	return &out, nil
+}
 
-		return nil
-	})
+// SetRules replaces the targeting rules of URL of the user.
+func (s *Server) SetRules(ctx context.Context, in *SetRulesRequest) (*Empty, error) {
+	userID, err := definitionUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.SetRules(userID, in.ShortUrl, storageRules(in.Rules)); err != nil {
+		return nil, statusError(err)
+	}
 
 	return &Empty{}, nil
 }
 
-// StatsInternal implement getting data about the number of shortened URLs
-func (s *Server) StatsInternal(ctx context.Context, in *Empty) (*StatsInternalReponse, error) {
-	var (
-		xrealIP string
-	)
-
-	md, ok := metadata.FromIncomingContext(ctx)
-	if ok {
-		if values := md.Get(xrealIPName); len(values) > 0 {
-			xrealIP = values[0]
-		}
+// SetVariants replaces the weighted destinations of URL of the user.
+func (s *Server) SetVariants(ctx context.Context, in *SetVariantsRequest) (*Empty, error) {
+	userID, err := definitionUser(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(xrealIP) == 0 {
-		s.log.LZ.Error(ErrorAccessDenied)
-		return nil, status.Error(codes.Unauthenticated, ErrorAccessDenied.Error())
+	if err := s.service.SetVariants(userID, in.ShortUrl, storageVariants(in.Variants)); err != nil {
+		return nil, statusError(err)
 	}
 
-	realIP, err := netip.ParseAddr(xrealIP)
+	return &Empty{}, nil
+}
+
+// ShortenDelete performs deletion all shortened URLs
+func (s *Server) ShortenDelete(ctx context.Context, in *ShortenDeleteRequest) (*Empty, error) {
+	if len(in.Urls) == 0 {
+		return nil, status.Error(codes.InvalidArgument, ErrorEmptyData.Error())
+	}
+
+	userID, err := definitionUser(ctx)
 	if err != nil {
-		s.log.LZ.Error(ErrorAccessDenied)
-		return nil, status.Error(codes.Unauthenticated, ErrorAccessDenied.Error())
+		return nil, err
 	}
 
-	if !s.trustedSubnets.Contains(realIP) {
-		s.log.LZ.Error(ErrorAccessDenied)
-		return nil, status.Error(codes.Unauthenticated, ErrorAccessDenied.Error())
+	if err := s.service.DeleteAsync(userID, in.Urls...); err != nil {
+		return nil, statusError(err)
 	}
 
-	stat, err := s.store.St.GetInternalStats()
+	return &Empty{}, nil
+}
+
+// StatsInternal implement getting data about the number of shortened URLs
+//
+// Access is allowed only to clients from the trusted subnets,
+// the client IP address is resolved by the realip interceptor.
+func (s *Server) StatsInternal(ctx context.Context, in *Empty) (*StatsInternalReponse, error) {
+	realIP, _ := realip.FromPeer(ctx)
+
+	stat, err := s.service.InternalStats(realIP)
 	if err != nil {
 		s.log.LZ.Error(err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 
 	return &StatsInternalReponse{
@@ -217,148 +271,166 @@ func (s *Server) StatsInternal(ctx context.Context, in *Empty) (*StatsInternalRe
 	}, nil
 }
 
-// auth performs the authorization this grpc server implements.
-func (s *Server) auth(ctx context.Context) (string, error) {
-	var (
-		userID    string
-		mdAuthVal string
-	)
-
-	md, ok := metadata.FromIncomingContext(ctx)
-	if ok {
-		if values := md.Get(mdAuthName); len(values) > 0 {
-			mdAuthVal = values[0]
-		}
+// definitionUser returns the user ID placed in the context by the auth interceptor.
+func definitionUser(ctx context.Context) (string, error) {
+	userID, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, auth.ErrInvalidMetadataAuth.Error())
 	}
 
-	if len(mdAuthVal) == 0 {
-		uid := s.store.St.Create()
+	return userID, nil
+}
+
+// idempotencyKey returns the idempotency key from the request metadata.
+func idempotencyKey(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, mdIdempotencyKey); len(values) > 0 {
+		return values[0]
+	}
 
-		header := metadata.Pairs("Authorization", s.encryptAuth(uid))
-		grpc.SendHeader(ctx, header)
+	return ""
+}
 
-		return strconv.Itoa(int(uid)), nil
+// userURL converts the user URL to the response item.
+func userURL(item storage.UserURL) *UserURL {
+	out := UserURL{
+		ShortUrl:      item.ShortURL,
+		LongUrl:       item.LongURL,
+		CreatedAt:     item.CreatedAt.Format(time.RFC3339),
+		Clicks:        uint64(item.Clicks),
+		CorrelationId: item.CorrelationID,
+		Removed:       item.Removed,
+		Tags:          item.Tags,
+		Folder:        item.Folder,
+		ForwardQuery:  item.ForwardQuery,
+		Rules:         make([]*Rule, 0, len(item.Rules)),
+		Variants:      variants(item.Variants),
+		Protected:     item.Protected,
+		MaxClicks:     uint32(item.MaxClicks),
+		Health:        health(item.Health),
+		Metadata:      pageMetadata(item.Metadata),
+	}
+
+	for _, rule := range item.Rules {
+		out.Rules = append(out.Rules, &Rule{
+			Url:      rule.URL,
+			Device:   rule.Device,
+			Language: rule.Language,
+			Country:  rule.Country,
+			Cidrs:    rule.CIDRs,
+		})
 	}
 
-	uid, err := s.decryptAuth(mdAuthVal)
-	if err != nil {
-		return userID, ErrInvalidMetadataAuth
+	if item.ExpiresAt != nil {
+		out.ExpiresAt = item.ExpiresAt.Format(time.RFC3339)
 	}
 
-	header := metadata.Pairs("Authorization", mdAuthVal)
-	grpc.SendHeader(ctx, header)
+	if item.UTM != nil {
+		out.Utm = &UTM{
+			Source:   item.UTM.Source,
+			Medium:   item.UTM.Medium,
+			Campaign: item.UTM.Campaign,
+			Term:     item.UTM.Term,
+			Content:  item.UTM.Content,
+		}
+	}
 
-	return strconv.Itoa(int(uid)), nil
+	return &out
 }
 
-// decryptAuth perfoms decryption metadata value
-// and returns user ID and error value.
-func (s *Server) decryptAuth(mdAuth string) (uint, error) {
-	signedVal, err := base64.URLEncoding.DecodeString(mdAuth)
-	if err != nil {
-		return 0, fmt.Errorf("metadata Authorization decoding error: %w", err)
+// health converts the result of the check of the destination,
+// nil for the unchecked URL.
+func health(item *storage.Health) *Health {
+	if item == nil {
+		return nil
 	}
 
-	signature := signedVal[:sha256.Size]
-	mac := hmac.New(sha256.New, s.secret)
-	mac.Write(signedVal[sha256.Size:])
-	expectedSignature := mac.Sum(nil)
+	return &Health{
+		CheckedAt:  item.CheckedAt.Format(time.RFC3339),
+		StatusCode: int32(item.StatusCode),
+		LatencyMs:  item.LatencyMS,
+		Error:      item.Error,
+		Broken:     item.Broken,
+	}
+}
 
-	if !hmac.Equal(signature, expectedSignature) {
-		return 0, ErrInvalidMetadataAuth
+// pageMetadata converts the metadata of the destination page,
+// nil if it's not fetched.
+func pageMetadata(item *storage.Metadata) *Metadata {
+	if item == nil {
+		return nil
 	}
 
-	return uint(int64(binary.LittleEndian.Uint64(signedVal[sha256.Size:]))), nil
+	return &Metadata{
+		FetchedAt:   item.FetchedAt.Format(time.RFC3339),
+		Title:       item.Title,
+		Description: item.Description,
+		Image:       item.Image,
+		Favicon:     item.Favicon,
+	}
 }
 
-// encryptAuth perfoms encryption user ID.
-//
-// Encryption is performed according to the sha256 algorithm.
-// The secret key and user ID are used for encryption.
-func (s *Server) encryptAuth(uid uint) string {
-	b := make([]byte, 8)
-	binary.LittleEndian.PutUint64(b, uint64(uid))
-
-	mac := hmac.New(sha256.New, s.secret)
-	mac.Write(b)
-	signature := mac.Sum(nil)
-	signature = append(signature, b...)
-
-	return base64.URLEncoding.EncodeToString(signature)
-}
+// variants converts the variants to the response items.
+func variants(items []storage.Variant) []*Variant {
+	out := make([]*Variant, 0, len(items))
 
-// definitionUser performs checking user ID from metadata.
-func (s *Server) definitionUser(ctx context.Context) (string, error) {
-	var (
-		userID    string
-		mdAuthVal string
-	)
-
-	md, ok := metadata.FromIncomingContext(ctx)
-	if ok {
-		if values := md.Get(mdAuthName); len(values) > 0 {
-			mdAuthVal = values[0]
-		}
+	for _, item := range items {
+		out = append(out, &Variant{Url: item.URL, Weight: int32(item.Weight), Clicks: uint64(item.Clicks)})
 	}
 
-	if len(mdAuthVal) == 0 {
-		return userID, ErrInvalidMetadataAuth
-	}
+	return out
+}
 
-	uid, err := s.decryptAuth(mdAuthVal)
-	if err != nil {
-		return userID, ErrInvalidMetadataAuth
-	}
+// storageVariants converts the variants of the request.
+func storageVariants(items []*Variant) []storage.Variant {
+	out := make([]storage.Variant, 0, len(items))
 
-	header := metadata.Pairs("Authorization", mdAuthVal)
-	grpc.SendHeader(ctx, header)
+	for _, item := range items {
+		out = append(out, storage.Variant{URL: item.GetUrl(), Weight: int(item.GetWeight())})
+	}
 
-	return strconv.Itoa(int(uid)), nil
+	return out
 }
 
-// processingURLBatch performs processing of batch of URLs.
-func (s *Server) processingURLBatch(userID string, in *ShortenBatchRequest) (*ShortenBatchResponse, error) {
-	out := ShortenBatchResponse{
-		Urls: make([]*ShortenBatchResponseItem, 0, len(in.Urls)),
+// storageRules converts the targeting rules of the request.
+func storageRules(rules []*Rule) []storage.Rule {
+	out := make([]storage.Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		out = append(out, storage.Rule{
+			URL:      rule.GetUrl(),
+			Device:   rule.GetDevice(),
+			Language: rule.GetLanguage(),
+			Country:  rule.GetCountry(),
+			CIDRs:    rule.GetCidrs(),
+		})
 	}
 
-	for _, item := range in.Urls {
-		err := service.IsURL(item.OriginalUrl)
-
-		if err == nil {
-			shortURL := s.store.St.AddBatch(item.OriginalUrl, userID, item.CorrelationId)
-			out.Urls = append(out.Urls, &ShortenBatchResponseItem{
-				CorrelationId: item.CorrelationId,
-				ShortUrl:      shortURL,
-			})
-		} else {
-			out.Urls = append(out.Urls, &ShortenBatchResponseItem{
-				CorrelationId: item.CorrelationId,
-				Error:         err.Error(),
-			})
-		}
-	}
+	return out
+}
 
-	if len(out.Urls) == 0 {
-		return nil, ErrEmptyBatch
+// storageUTM converts the UTM parameters of the request.
+func storageUTM(utm *UTM) storage.UTM {
+	return storage.UTM{
+		Source:   utm.GetSource(),
+		Medium:   utm.GetMedium(),
+		Campaign: utm.GetCampaign(),
+		Term:     utm.GetTerm(),
+		Content:  utm.GetContent(),
 	}
-
-	return &out, nil
 }
 
-// checkShortUID perform checking shortUID before deleting.
-func checkShortUID(shortUID ...string) []string {
-	var correctShortUID []string
+// batchResponseItem converts the result of batch shortening to the response item.
+func batchResponseItem(item usecase.BatchResult) *ShortenBatchResponseItem {
+	out := ShortenBatchResponseItem{
+		CorrelationId: item.CorID,
+		ShortUrl:      item.ShortURL,
+	}
 
-	for _, v := range shortUID {
-		if !strings.Contains(v, "/") && v != "" {
-			correctShortUID = append(correctShortUID, v)
-		} else if sUID := v[strings.LastIndex(v, "/")+1:]; sUID != "" {
-			correctShortUID = append(correctShortUID, sUID)
-		}
+	if item.Err != nil {
+		out.Error = item.Err.Error()
 	}
 
-	return correctShortUID
+	return &out
 }
 
 // mustEmbedUnimplementedShortenerServer implements interface UnsafeShortenerServer.
@@ -1,674 +1,2469 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.29.1
+// 	protoc        v3.12.4
 // source: shortener.proto
 
 package proto
 
 import (
-	fmt "fmt"
-	proto "github.com/golang/protobuf/proto"
-	math "math"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Empty simple is stub parameter.
+type Empty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{0}
+}
+
+// The request message for ShortenURL.
+type ShortenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// The tags and the folder (campaign) of URL.
+	Tags   []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	Folder string   `protobuf:"bytes,3,opt,name=folder,proto3" json:"folder,omitempty"`
+	// The UTM parameters added to the original URL on redirect.
+	Utm *UTM `protobuf:"bytes,4,opt,name=utm,proto3" json:"utm,omitempty"`
+	// Pass the query of the short URL to the original URL on redirect.
+	ForwardQuery bool `protobuf:"varint,5,opt,name=forward_query,json=forwardQuery,proto3" json:"forward_query,omitempty"`
+	// The targeting rules of the redirect.
+	Rules []*Rule `protobuf:"bytes,6,rep,name=rules,proto3" json:"rules,omitempty"`
+	// The weighted destinations replacing url for A/B split redirects.
+	Variants []*Variant `protobuf:"bytes,7,rep,name=variants,proto3" json:"variants,omitempty"`
+	// The password protecting the redirect.
+	Password string `protobuf:"bytes,8,opt,name=password,proto3" json:"password,omitempty"`
+	// The number of redirects after which URL stops resolving, 0 means no limit.
+	MaxClicks uint32 `protobuf:"varint,9,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	// Return the QR code of the short URL in the response.
+	QrCode bool `protobuf:"varint,10,opt,name=qr_code,json=qrCode,proto3" json:"qr_code,omitempty"`
+	// One of the custom domains of the short URL, empty for the domain of the base URL.
+	Domain string `protobuf:"bytes,11,opt,name=domain,proto3" json:"domain,omitempty"`
+}
+
+func (x *ShortenRequest) Reset() {
+	*x = ShortenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortenRequest) ProtoMessage() {}
+
+func (x *ShortenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortenRequest.ProtoReflect.Descriptor instead.
+func (*ShortenRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ShortenRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetUtm() *UTM {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetForwardQuery() bool {
+	if x != nil {
+		return x.ForwardQuery
+	}
+	return false
+}
+
+func (x *ShortenRequest) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetMaxClicks() uint32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *ShortenRequest) GetQrCode() bool {
+	if x != nil {
+		return x.QrCode
+	}
+	return false
+}
+
+func (x *ShortenRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+// The weighted destination of URL with the number of redirects to it.
+type Variant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url    string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Weight int32  `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	Clicks uint64 `protobuf:"varint,3,opt,name=clicks,proto3" json:"clicks,omitempty"`
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Variant) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Variant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Variant) GetClicks() uint64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+// The request message for SetVariants.
+//
+// The counters of redirects are reset, the empty list removes the variants.
+type SetVariantsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short URL or the short URL ID.
+	ShortUrl string     `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Variants []*Variant `protobuf:"bytes,2,rep,name=variants,proto3" json:"variants,omitempty"`
+}
+
+func (x *SetVariantsRequest) Reset() {
+	*x = SetVariantsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetVariantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVariantsRequest) ProtoMessage() {}
+
+func (x *SetVariantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVariantsRequest.ProtoReflect.Descriptor instead.
+func (*SetVariantsRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetVariantsRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *SetVariantsRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// The targeting rule of the redirect to url, the rule matches
+// if all its non-empty conditions match the client.
+type Rule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// The device of the client: ios, android, mobile or desktop.
+	Device string `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	// The most preferred language of the client, e.g. de or de-AT.
+	Language string `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	// The ISO 3166-1 alpha-2 code of the client country.
+	Country string `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	// The networks in CIDR notation containing the client IP address.
+	Cidrs []string `protobuf:"bytes,5,rep,name=cidrs,proto3" json:"cidrs,omitempty"`
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Rule) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Rule) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *Rule) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Rule) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Rule) GetCidrs() []string {
+	if x != nil {
+		return x.Cidrs
+	}
+	return nil
+}
+
+// The request message for SetRules.
+//
+// The rules are evaluated in order on redirect, the empty list removes the rules.
+type SetRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short URL or the short URL ID.
+	ShortUrl string  `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Rules    []*Rule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *SetRulesRequest) Reset() {
+	*x = SetRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRulesRequest) ProtoMessage() {}
+
+func (x *SetRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRulesRequest.ProtoReflect.Descriptor instead.
+func (*SetRulesRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetRulesRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *SetRulesRequest) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// The UTM parameters of URL.
+type UTM struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Source   string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Medium   string `protobuf:"bytes,2,opt,name=medium,proto3" json:"medium,omitempty"`
+	Campaign string `protobuf:"bytes,3,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Term     string `protobuf:"bytes,4,opt,name=term,proto3" json:"term,omitempty"`
+	Content  string `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (x *UTM) Reset() {
+	*x = UTM{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UTM) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTM) ProtoMessage() {}
+
+func (x *UTM) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTM.ProtoReflect.Descriptor instead.
+func (*UTM) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UTM) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *UTM) GetMedium() string {
+	if x != nil {
+		return x.Medium
+	}
+	return ""
+}
+
+func (x *UTM) GetCampaign() string {
+	if x != nil {
+		return x.Campaign
+	}
+	return ""
+}
+
+func (x *UTM) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *UTM) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// The response message for ShortenURL.
+type ShortenResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error   string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Result  string `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	Success bool   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	// The PNG image of the QR code as the data URI.
+	QrCode string `protobuf:"bytes,4,opt,name=qr_code,json=qrCode,proto3" json:"qr_code,omitempty"`
+}
+
+func (x *ShortenResponse) Reset() {
+	*x = ShortenResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortenResponse) ProtoMessage() {}
+
+func (x *ShortenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortenResponse.ProtoReflect.Descriptor instead.
+func (*ShortenResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ShortenResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ShortenResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *ShortenResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ShortenResponse) GetQrCode() string {
+	if x != nil {
+		return x.QrCode
+	}
+	return ""
+}
+
+// The request message for GetStat.
+type StatRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short URL ID or the short URL, which is required for the custom domains.
+	Shortuid string `protobuf:"bytes,1,opt,name=shortuid,proto3" json:"shortuid,omitempty"`
+}
+
+func (x *StatRequest) Reset() {
+	*x = StatRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatRequest) ProtoMessage() {}
+
+func (x *StatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
+func (*StatRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StatRequest) GetShortuid() string {
+	if x != nil {
+		return x.Shortuid
+	}
+	return ""
+}
+
+// The response message for GetStat.
+type StatResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shorturl  string     `protobuf:"bytes,1,opt,name=shorturl,proto3" json:"shorturl,omitempty"`
+	Longurl   string     `protobuf:"bytes,2,opt,name=longurl,proto3" json:"longurl,omitempty"`
+	CreatedAt string     `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	Usage     uint64     `protobuf:"varint,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	Variants  []*Variant `protobuf:"bytes,5,rep,name=variants,proto3" json:"variants,omitempty"`
+	// The destinations of the protected URL are hidden.
+	Protected bool `protobuf:"varint,6,opt,name=protected,proto3" json:"protected,omitempty"`
+	// The result of the last check of the destination, empty for the unchecked URL.
+	Health *Health `protobuf:"bytes,7,opt,name=health,proto3" json:"health,omitempty"`
+	// The metadata of the destination page, empty until it's fetched.
+	Metadata *Metadata `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+}
+
+func (x *StatResponse) Reset() {
+	*x = StatResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatResponse) ProtoMessage() {}
+
+func (x *StatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatResponse.ProtoReflect.Descriptor instead.
+func (*StatResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StatResponse) GetShorturl() string {
+	if x != nil {
+		return x.Shorturl
+	}
+	return ""
+}
+
+func (x *StatResponse) GetLongurl() string {
+	if x != nil {
+		return x.Longurl
+	}
+	return ""
+}
+
+func (x *StatResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *StatResponse) GetUsage() uint64 {
+	if x != nil {
+		return x.Usage
+	}
+	return 0
+}
+
+func (x *StatResponse) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *StatResponse) GetProtected() bool {
+	if x != nil {
+		return x.Protected
+	}
+	return false
+}
+
+func (x *StatResponse) GetHealth() *Health {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *StatResponse) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+// The metadata of the destination page of URL, the URLs are absolute.
+type Metadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-// Empty simple is stub parameter.
-type Empty struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// The time of the fetch in RFC 3339 format.
+	FetchedAt   string `protobuf:"bytes,1,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	Title       string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The Open Graph image of the page.
+	Image   string `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	Favicon string `protobuf:"bytes,5,opt,name=favicon,proto3" json:"favicon,omitempty"`
 }
 
-func (m *Empty) Reset()         { *m = Empty{} }
-func (m *Empty) String() string { return proto.CompactTextString(m) }
-func (*Empty) ProtoMessage()    {}
-func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{0}
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (m *Empty) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Empty.Unmarshal(m, b)
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{10}
 }
-func (m *Empty) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Empty.Merge(m, src)
+
+func (x *Metadata) GetFetchedAt() string {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return ""
 }
-func (m *Empty) XXX_Size() int {
-	return xxx_messageInfo_Empty.Size(m)
+
+func (x *Metadata) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
 }
-func (m *Empty) XXX_DiscardUnknown() {
-	xxx_messageInfo_Empty.DiscardUnknown(m)
+
+func (x *Metadata) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
 }
 
-var xxx_messageInfo_Empty proto.InternalMessageInfo
+func (x *Metadata) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
 
-// The request message for ShortenURL.
-type ShortenRequest struct {
-	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *Metadata) GetFavicon() string {
+	if x != nil {
+		return x.Favicon
+	}
+	return ""
 }
 
-func (m *ShortenRequest) Reset()         { *m = ShortenRequest{} }
-func (m *ShortenRequest) String() string { return proto.CompactTextString(m) }
-func (*ShortenRequest) ProtoMessage()    {}
-func (*ShortenRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{1}
+// The result of the check of the destination of URL.
+//
+// The destination is broken if it responded with 4xx/5xx status code
+// or could not be reached, the reason of the failure is in error.
+type Health struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The time of the check in RFC 3339 format.
+	CheckedAt  string `protobuf:"bytes,1,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	StatusCode int32  `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	LatencyMs  int64  `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Error      string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Broken     bool   `protobuf:"varint,5,opt,name=broken,proto3" json:"broken,omitempty"`
+}
+
+func (x *Health) Reset() {
+	*x = Health{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *ShortenRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShortenRequest.Unmarshal(m, b)
+func (x *Health) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ShortenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShortenRequest.Marshal(b, m, deterministic)
+
+func (*Health) ProtoMessage() {}
+
+func (x *Health) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *ShortenRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShortenRequest.Merge(m, src)
+
+// Deprecated: Use Health.ProtoReflect.Descriptor instead.
+func (*Health) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{11}
 }
-func (m *ShortenRequest) XXX_Size() int {
-	return xxx_messageInfo_ShortenRequest.Size(m)
+
+func (x *Health) GetCheckedAt() string {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return ""
 }
-func (m *ShortenRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShortenRequest.DiscardUnknown(m)
+
+func (x *Health) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
 }
 
-var xxx_messageInfo_ShortenRequest proto.InternalMessageInfo
+func (x *Health) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
 
-func (m *ShortenRequest) GetUrl() string {
-	if m != nil {
-		return m.Url
+func (x *Health) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-// The response message for ShortenURL.
-type ShortenResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	Error                string   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Result               string   `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-	Success              bool     `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+func (x *Health) GetBroken() bool {
+	if x != nil {
+		return x.Broken
+	}
+	return false
 }
 
-func (m *ShortenResponse) Reset()         { *m = ShortenResponse{} }
-func (m *ShortenResponse) String() string { return proto.CompactTextString(m) }
-func (*ShortenResponse) ProtoMessage()    {}
-func (*ShortenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{2}
+// The request message for GetUsersURL.
+//
+// The fields are the same as the query parameters of GET /api/user/urls.
+type UsersURLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The cursor of the page, empty for the first page.
+	Cursor string `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// The maximum number of URLs on the page.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The sort field: created_at or clicks, "-" prefix for the descending order.
+	Sort string `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	// The creation time range in RFC 3339 or YYYY-MM-DD format, created_to is exclusive.
+	CreatedFrom string `protobuf:"bytes,4,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo   string `protobuf:"bytes,5,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	// The domain of the original URL including its subdomains.
+	Domain string `protobuf:"bytes,6,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The status of URLs: active (by default), removed or all.
+	Status string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	// The tag attached to URLs.
+	Tag string `protobuf:"bytes,8,opt,name=tag,proto3" json:"tag,omitempty"`
+	// The folder (campaign) of URLs.
+	Folder string `protobuf:"bytes,9,opt,name=folder,proto3" json:"folder,omitempty"`
+	// Only URLs whose destination was found dead by the last check.
+	Broken bool `protobuf:"varint,10,opt,name=broken,proto3" json:"broken,omitempty"`
+}
+
+func (x *UsersURLRequest) Reset() {
+	*x = UsersURLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *ShortenResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShortenResponse.Unmarshal(m, b)
-}
-func (m *ShortenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShortenResponse.Marshal(b, m, deterministic)
+func (x *UsersURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ShortenResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShortenResponse.Merge(m, src)
+
+func (*UsersURLRequest) ProtoMessage() {}
+
+func (x *UsersURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *ShortenResponse) XXX_Size() int {
-	return xxx_messageInfo_ShortenResponse.Size(m)
+
+// Deprecated: Use UsersURLRequest.ProtoReflect.Descriptor instead.
+func (*UsersURLRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{12}
 }
-func (m *ShortenResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShortenResponse.DiscardUnknown(m)
+
+func (x *UsersURLRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
 }
 
-var xxx_messageInfo_ShortenResponse proto.InternalMessageInfo
+func (x *UsersURLRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
 
-func (m *ShortenResponse) GetError() string {
-	if m != nil {
-		return m.Error
+func (x *UsersURLRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
 	}
 	return ""
 }
 
-func (m *ShortenResponse) GetResult() string {
-	if m != nil {
-		return m.Result
+func (x *UsersURLRequest) GetCreatedFrom() string {
+	if x != nil {
+		return x.CreatedFrom
 	}
 	return ""
 }
 
-func (m *ShortenResponse) GetSuccess() bool {
-	if m != nil {
-		return m.Success
+func (x *UsersURLRequest) GetCreatedTo() string {
+	if x != nil {
+		return x.CreatedTo
 	}
-	return false
+	return ""
 }
 
-// The request message for GetStat.
-type StatRequest struct {
-	Shortuid             string   `protobuf:"bytes,1,opt,name=shortuid,proto3" json:"shortuid,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *UsersURLRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
 }
 
-func (m *StatRequest) Reset()         { *m = StatRequest{} }
-func (m *StatRequest) String() string { return proto.CompactTextString(m) }
-func (*StatRequest) ProtoMessage()    {}
-func (*StatRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{3}
+func (x *UsersURLRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
 }
 
-func (m *StatRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StatRequest.Unmarshal(m, b)
+func (x *UsersURLRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
 }
-func (m *StatRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StatRequest.Marshal(b, m, deterministic)
+
+func (x *UsersURLRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
 }
-func (m *StatRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StatRequest.Merge(m, src)
+
+func (x *UsersURLRequest) GetBroken() bool {
+	if x != nil {
+		return x.Broken
+	}
+	return false
 }
-func (m *StatRequest) XXX_Size() int {
-	return xxx_messageInfo_StatRequest.Size(m)
+
+// The response message for GetUsersURL.
+type UsersURL struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Urls []*UserURL `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	// The cursor of the next page, empty for the last page.
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// The number of URLs matching the filters on all pages.
+	Total int64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *UsersURL) Reset() {
+	*x = UsersURL{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *StatRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_StatRequest.DiscardUnknown(m)
+
+func (x *UsersURL) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_StatRequest proto.InternalMessageInfo
+func (*UsersURL) ProtoMessage() {}
 
-func (m *StatRequest) GetShortuid() string {
-	if m != nil {
-		return m.Shortuid
+func (x *UsersURL) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-// The response message for GetStat.
-type StatResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	Shorturl             string   `protobuf:"bytes,1,opt,name=shorturl,proto3" json:"shorturl,omitempty"`
-	Longurl              string   `protobuf:"bytes,2,opt,name=longurl,proto3" json:"longurl,omitempty"`
-	CreatedAt            string   `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	XXX_unrecognized     []byte   `json:"-"`
-	Usage                uint64   `protobuf:"varint,4,opt,name=usage,proto3" json:"usage,omitempty"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *StatResponse) Reset()         { *m = StatResponse{} }
-func (m *StatResponse) String() string { return proto.CompactTextString(m) }
-func (*StatResponse) ProtoMessage()    {}
-func (*StatResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{4}
+// Deprecated: Use UsersURL.ProtoReflect.Descriptor instead.
+func (*UsersURL) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UsersURL) GetUrls() []*UserURL {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
 }
 
-func (m *StatResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StatResponse.Unmarshal(m, b)
+func (x *UsersURL) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
 }
-func (m *StatResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StatResponse.Marshal(b, m, deterministic)
+
+func (x *UsersURL) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
 }
-func (m *StatResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StatResponse.Merge(m, src)
+
+// The item for UsersURL.
+//
+// The times are in RFC 3339 format, expires_at is empty for URLs without expiry.
+type UserURL struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ShortUrl      string     `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	LongUrl       string     `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	CreatedAt     string     `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Clicks        uint64     `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	CorrelationId string     `protobuf:"bytes,5,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Removed       bool       `protobuf:"varint,6,opt,name=removed,proto3" json:"removed,omitempty"`
+	ExpiresAt     string     `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Tags          []string   `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	Folder        string     `protobuf:"bytes,9,opt,name=folder,proto3" json:"folder,omitempty"`
+	Utm           *UTM       `protobuf:"bytes,10,opt,name=utm,proto3" json:"utm,omitempty"`
+	ForwardQuery  bool       `protobuf:"varint,11,opt,name=forward_query,json=forwardQuery,proto3" json:"forward_query,omitempty"`
+	Rules         []*Rule    `protobuf:"bytes,12,rep,name=rules,proto3" json:"rules,omitempty"`
+	Variants      []*Variant `protobuf:"bytes,13,rep,name=variants,proto3" json:"variants,omitempty"`
+	Protected     bool       `protobuf:"varint,14,opt,name=protected,proto3" json:"protected,omitempty"`
+	MaxClicks     uint32     `protobuf:"varint,15,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	Health        *Health    `protobuf:"bytes,16,opt,name=health,proto3" json:"health,omitempty"`
+	Metadata      *Metadata  `protobuf:"bytes,17,opt,name=metadata,proto3" json:"metadata,omitempty"`
+}
+
+func (x *UserURL) Reset() {
+	*x = UserURL{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *StatResponse) XXX_Size() int {
-	return xxx_messageInfo_StatResponse.Size(m)
+
+func (x *UserURL) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *StatResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_StatResponse.DiscardUnknown(m)
+
+func (*UserURL) ProtoMessage() {}
+
+func (x *UserURL) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_StatResponse proto.InternalMessageInfo
+// Deprecated: Use UserURL.ProtoReflect.Descriptor instead.
+func (*UserURL) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{14}
+}
 
-func (m *StatResponse) GetShorturl() string {
-	if m != nil {
-		return m.Shorturl
+func (x *UserURL) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
 	}
 	return ""
 }
 
-func (m *StatResponse) GetLongurl() string {
-	if m != nil {
-		return m.Longurl
+func (x *UserURL) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
 	}
 	return ""
 }
 
-func (m *StatResponse) GetCreatedAt() string {
-	if m != nil {
-		return m.CreatedAt
+func (x *UserURL) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return ""
 }
 
-func (m *StatResponse) GetUsage() uint64 {
-	if m != nil {
-		return m.Usage
+func (x *UserURL) GetClicks() uint64 {
+	if x != nil {
+		return x.Clicks
 	}
 	return 0
 }
 
-// The response message for GetUsersURL.
-type UsersURL struct {
-	Urls                 []*UserURL `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+func (x *UserURL) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
 }
 
-func (m *UsersURL) Reset()         { *m = UsersURL{} }
-func (m *UsersURL) String() string { return proto.CompactTextString(m) }
-func (*UsersURL) ProtoMessage()    {}
-func (*UsersURL) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{5}
+func (x *UserURL) GetRemoved() bool {
+	if x != nil {
+		return x.Removed
+	}
+	return false
 }
 
-func (m *UsersURL) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UsersURL.Unmarshal(m, b)
-}
-func (m *UsersURL) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UsersURL.Marshal(b, m, deterministic)
-}
-func (m *UsersURL) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UsersURL.Merge(m, src)
-}
-func (m *UsersURL) XXX_Size() int {
-	return xxx_messageInfo_UsersURL.Size(m)
-}
-func (m *UsersURL) XXX_DiscardUnknown() {
-	xxx_messageInfo_UsersURL.DiscardUnknown(m)
+func (x *UserURL) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
 }
 
-var xxx_messageInfo_UsersURL proto.InternalMessageInfo
-
-func (m *UsersURL) GetUrls() []*UserURL {
-	if m != nil {
-		return m.Urls
+func (x *UserURL) GetTags() []string {
+	if x != nil {
+		return x.Tags
 	}
 	return nil
 }
 
-// The item for UsersURL.
-type UserURL struct {
-	ShortUrl             string   `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
-	LongUrl              string   `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (x *UserURL) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
 }
 
-func (m *UserURL) Reset()         { *m = UserURL{} }
-func (m *UserURL) String() string { return proto.CompactTextString(m) }
-func (*UserURL) ProtoMessage()    {}
-func (*UserURL) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{6}
+func (x *UserURL) GetUtm() *UTM {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
 }
 
-func (m *UserURL) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UserURL.Unmarshal(m, b)
-}
-func (m *UserURL) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UserURL.Marshal(b, m, deterministic)
+func (x *UserURL) GetForwardQuery() bool {
+	if x != nil {
+		return x.ForwardQuery
+	}
+	return false
 }
-func (m *UserURL) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UserURL.Merge(m, src)
+
+func (x *UserURL) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
 }
-func (m *UserURL) XXX_Size() int {
-	return xxx_messageInfo_UserURL.Size(m)
+
+func (x *UserURL) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
 }
-func (m *UserURL) XXX_DiscardUnknown() {
-	xxx_messageInfo_UserURL.DiscardUnknown(m)
+
+func (x *UserURL) GetProtected() bool {
+	if x != nil {
+		return x.Protected
+	}
+	return false
 }
 
-var xxx_messageInfo_UserURL proto.InternalMessageInfo
+func (x *UserURL) GetMaxClicks() uint32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
 
-func (m *UserURL) GetShortUrl() string {
-	if m != nil {
-		return m.ShortUrl
+func (x *UserURL) GetHealth() *Health {
+	if x != nil {
+		return x.Health
 	}
-	return ""
+	return nil
 }
 
-func (m *UserURL) GetLongUrl() string {
-	if m != nil {
-		return m.LongUrl
+func (x *UserURL) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
 	}
-	return ""
+	return nil
 }
 
 // The request message for ShortenURLBatch.
+//
+// The response to the request with the idempotency-key metadata
+// (the Idempotency-Key header in the gateway) is stored and returned
+// to the retried requests with the same key.
 type ShortenBatchRequest struct {
-	Urls                 []*ShortenBatchRequestItem `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
-	XXX_unrecognized     []byte                     `json:"-"`
-	XXX_sizecache        int32                      `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *ShortenBatchRequest) Reset()         { *m = ShortenBatchRequest{} }
-func (m *ShortenBatchRequest) String() string { return proto.CompactTextString(m) }
-func (*ShortenBatchRequest) ProtoMessage()    {}
-func (*ShortenBatchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{7}
+	Urls []*ShortenBatchRequestItem `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	// No URL is shortened if any URL is invalid.
+	Atomic bool `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
 }
 
-func (m *ShortenBatchRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShortenBatchRequest.Unmarshal(m, b)
-}
-func (m *ShortenBatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShortenBatchRequest.Marshal(b, m, deterministic)
-}
-func (m *ShortenBatchRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShortenBatchRequest.Merge(m, src)
+func (x *ShortenBatchRequest) Reset() {
+	*x = ShortenBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ShortenBatchRequest) XXX_Size() int {
-	return xxx_messageInfo_ShortenBatchRequest.Size(m)
+
+func (x *ShortenBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ShortenBatchRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShortenBatchRequest.DiscardUnknown(m)
+
+func (*ShortenBatchRequest) ProtoMessage() {}
+
+func (x *ShortenBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ShortenBatchRequest proto.InternalMessageInfo
+// Deprecated: Use ShortenBatchRequest.ProtoReflect.Descriptor instead.
+func (*ShortenBatchRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{15}
+}
 
-func (m *ShortenBatchRequest) GetUrls() []*ShortenBatchRequestItem {
-	if m != nil {
-		return m.Urls
+func (x *ShortenBatchRequest) GetUrls() []*ShortenBatchRequestItem {
+	if x != nil {
+		return x.Urls
 	}
 	return nil
 }
 
+func (x *ShortenBatchRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
 // The item ShortenBatchReques.
 type ShortenBatchRequestItem struct {
-	CorrelationId        string   `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	OriginalUrl          string   `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *ShortenBatchRequestItem) Reset()         { *m = ShortenBatchRequestItem{} }
-func (m *ShortenBatchRequestItem) String() string { return proto.CompactTextString(m) }
-func (*ShortenBatchRequestItem) ProtoMessage()    {}
-func (*ShortenBatchRequestItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{8}
+	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	OriginalUrl   string `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
 }
 
-func (m *ShortenBatchRequestItem) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShortenBatchRequestItem.Unmarshal(m, b)
-}
-func (m *ShortenBatchRequestItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShortenBatchRequestItem.Marshal(b, m, deterministic)
-}
-func (m *ShortenBatchRequestItem) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShortenBatchRequestItem.Merge(m, src)
+func (x *ShortenBatchRequestItem) Reset() {
+	*x = ShortenBatchRequestItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ShortenBatchRequestItem) XXX_Size() int {
-	return xxx_messageInfo_ShortenBatchRequestItem.Size(m)
+
+func (x *ShortenBatchRequestItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ShortenBatchRequestItem) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShortenBatchRequestItem.DiscardUnknown(m)
+
+func (*ShortenBatchRequestItem) ProtoMessage() {}
+
+func (x *ShortenBatchRequestItem) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ShortenBatchRequestItem proto.InternalMessageInfo
+// Deprecated: Use ShortenBatchRequestItem.ProtoReflect.Descriptor instead.
+func (*ShortenBatchRequestItem) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{16}
+}
 
-func (m *ShortenBatchRequestItem) GetCorrelationId() string {
-	if m != nil {
-		return m.CorrelationId
+func (x *ShortenBatchRequestItem) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
 	}
 	return ""
 }
 
-func (m *ShortenBatchRequestItem) GetOriginalUrl() string {
-	if m != nil {
-		return m.OriginalUrl
+func (x *ShortenBatchRequestItem) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
 	}
 	return ""
 }
 
 // The response message for ShortenURLBatch.
 type ShortenBatchResponse struct {
-	Urls                 []*ShortenBatchResponseItem `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
-	XXX_unrecognized     []byte                      `json:"-"`
-	XXX_sizecache        int32                       `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *ShortenBatchResponse) Reset()         { *m = ShortenBatchResponse{} }
-func (m *ShortenBatchResponse) String() string { return proto.CompactTextString(m) }
-func (*ShortenBatchResponse) ProtoMessage()    {}
-func (*ShortenBatchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{9}
+	Urls []*ShortenBatchResponseItem `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
 }
 
-func (m *ShortenBatchResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShortenBatchResponse.Unmarshal(m, b)
-}
-func (m *ShortenBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShortenBatchResponse.Marshal(b, m, deterministic)
-}
-func (m *ShortenBatchResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShortenBatchResponse.Merge(m, src)
+func (x *ShortenBatchResponse) Reset() {
+	*x = ShortenBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ShortenBatchResponse) XXX_Size() int {
-	return xxx_messageInfo_ShortenBatchResponse.Size(m)
+
+func (x *ShortenBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ShortenBatchResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShortenBatchResponse.DiscardUnknown(m)
+
+func (*ShortenBatchResponse) ProtoMessage() {}
+
+func (x *ShortenBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ShortenBatchResponse proto.InternalMessageInfo
+// Deprecated: Use ShortenBatchResponse.ProtoReflect.Descriptor instead.
+func (*ShortenBatchResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{17}
+}
 
-func (m *ShortenBatchResponse) GetUrls() []*ShortenBatchResponseItem {
-	if m != nil {
-		return m.Urls
+func (x *ShortenBatchResponse) GetUrls() []*ShortenBatchResponseItem {
+	if x != nil {
+		return x.Urls
 	}
 	return nil
 }
 
 // The item for ShortenBatchResponse.
 type ShortenBatchResponseItem struct {
-	CorrelationId        string   `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	ShortUrl             string   `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
-	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *ShortenBatchResponseItem) Reset()         { *m = ShortenBatchResponseItem{} }
-func (m *ShortenBatchResponseItem) String() string { return proto.CompactTextString(m) }
-func (*ShortenBatchResponseItem) ProtoMessage()    {}
-func (*ShortenBatchResponseItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{10}
+	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	ShortUrl      string `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (m *ShortenBatchResponseItem) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShortenBatchResponseItem.Unmarshal(m, b)
-}
-func (m *ShortenBatchResponseItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShortenBatchResponseItem.Marshal(b, m, deterministic)
-}
-func (m *ShortenBatchResponseItem) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShortenBatchResponseItem.Merge(m, src)
+func (x *ShortenBatchResponseItem) Reset() {
+	*x = ShortenBatchResponseItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ShortenBatchResponseItem) XXX_Size() int {
-	return xxx_messageInfo_ShortenBatchResponseItem.Size(m)
+
+func (x *ShortenBatchResponseItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ShortenBatchResponseItem) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShortenBatchResponseItem.DiscardUnknown(m)
+
+func (*ShortenBatchResponseItem) ProtoMessage() {}
+
+func (x *ShortenBatchResponseItem) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ShortenBatchResponseItem proto.InternalMessageInfo
+// Deprecated: Use ShortenBatchResponseItem.ProtoReflect.Descriptor instead.
+func (*ShortenBatchResponseItem) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{18}
+}
 
-func (m *ShortenBatchResponseItem) GetCorrelationId() string {
-	if m != nil {
-		return m.CorrelationId
+func (x *ShortenBatchResponseItem) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
 	}
 	return ""
 }
 
-func (m *ShortenBatchResponseItem) GetShortUrl() string {
-	if m != nil {
-		return m.ShortUrl
+func (x *ShortenBatchResponseItem) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
 	}
 	return ""
 }
 
-func (m *ShortenBatchResponseItem) GetError() string {
-	if m != nil {
-		return m.Error
+func (x *ShortenBatchResponseItem) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
 // The request message for ShortenDelete.
 type ShortenDeleteRequest struct {
-	Urls                 []string `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *ShortenDeleteRequest) Reset()         { *m = ShortenDeleteRequest{} }
-func (m *ShortenDeleteRequest) String() string { return proto.CompactTextString(m) }
-func (*ShortenDeleteRequest) ProtoMessage()    {}
-func (*ShortenDeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{11}
+	Urls []string `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
 }
 
-func (m *ShortenDeleteRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ShortenDeleteRequest.Unmarshal(m, b)
-}
-func (m *ShortenDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ShortenDeleteRequest.Marshal(b, m, deterministic)
-}
-func (m *ShortenDeleteRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ShortenDeleteRequest.Merge(m, src)
+func (x *ShortenDeleteRequest) Reset() {
+	*x = ShortenDeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ShortenDeleteRequest) XXX_Size() int {
-	return xxx_messageInfo_ShortenDeleteRequest.Size(m)
+
+func (x *ShortenDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ShortenDeleteRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ShortenDeleteRequest.DiscardUnknown(m)
+
+func (*ShortenDeleteRequest) ProtoMessage() {}
+
+func (x *ShortenDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ShortenDeleteRequest proto.InternalMessageInfo
+// Deprecated: Use ShortenDeleteRequest.ProtoReflect.Descriptor instead.
+func (*ShortenDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{19}
+}
 
-func (m *ShortenDeleteRequest) GetUrls() []string {
-	if m != nil {
-		return m.Urls
+func (x *ShortenDeleteRequest) GetUrls() []string {
+	if x != nil {
+		return x.Urls
 	}
 	return nil
 }
 
 // The response message for StatsInternalReponse.
 type StatsInternalReponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	Urls                 int64    `protobuf:"varint,1,opt,name=urls,proto3" json:"urls,omitempty"`
-	Users                int64    `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
-	XXX_sizecache        int32    `json:"-"`
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Urls  int64 `protobuf:"varint,1,opt,name=urls,proto3" json:"urls,omitempty"`
+	Users int64 `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *StatsInternalReponse) Reset() {
+	*x = StatsInternalReponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsInternalReponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsInternalReponse) ProtoMessage() {}
+
+func (x *StatsInternalReponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (m *StatsInternalReponse) Reset()         { *m = StatsInternalReponse{} }
-func (m *StatsInternalReponse) String() string { return proto.CompactTextString(m) }
-func (*StatsInternalReponse) ProtoMessage()    {}
+// Deprecated: Use StatsInternalReponse.ProtoReflect.Descriptor instead.
 func (*StatsInternalReponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6a64040fb43d257f, []int{12}
+	return file_shortener_proto_rawDescGZIP(), []int{20}
 }
 
-func (m *StatsInternalReponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StatsInternalReponse.Unmarshal(m, b)
+func (x *StatsInternalReponse) GetUrls() int64 {
+	if x != nil {
+		return x.Urls
+	}
+	return 0
 }
-func (m *StatsInternalReponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StatsInternalReponse.Marshal(b, m, deterministic)
+
+func (x *StatsInternalReponse) GetUsers() int64 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
 }
-func (m *StatsInternalReponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StatsInternalReponse.Merge(m, src)
+
+// The request message for ExportUserURLs.
+type ExportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The cursor from which the export is continued, empty to start from the beginning.
+	Cursor string `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// The number of URLs in one response message.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
 }
-func (m *StatsInternalReponse) XXX_Size() int {
-	return xxx_messageInfo_StatsInternalReponse.Size(m)
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *StatsInternalReponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_StatsInternalReponse.DiscardUnknown(m)
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_StatsInternalReponse proto.InternalMessageInfo
+func (*ExportRequest) ProtoMessage() {}
 
-func (m *StatsInternalReponse) GetUrls() int64 {
-	if m != nil {
-		return m.Urls
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (m *StatsInternalReponse) GetUsers() int64 {
-	if m != nil {
-		return m.Users
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExportRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
 	}
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*Empty)(nil), "github.com.alaleks.shortener.Empty")
-	proto.RegisterType((*ShortenRequest)(nil), "github.com.alaleks.shortener.ShortenRequest")
-	proto.RegisterType((*ShortenResponse)(nil), "github.com.alaleks.shortener.ShortenResponse")
-	proto.RegisterType((*StatRequest)(nil), "github.com.alaleks.shortener.StatRequest")
-	proto.RegisterType((*StatResponse)(nil), "github.com.alaleks.shortener.StatResponse")
-	proto.RegisterType((*UsersURL)(nil), "github.com.alaleks.shortener.UsersURL")
-	proto.RegisterType((*UserURL)(nil), "github.com.alaleks.shortener.UserURL")
-	proto.RegisterType((*ShortenBatchRequest)(nil), "github.com.alaleks.shortener.ShortenBatchRequest")
-	proto.RegisterType((*ShortenBatchRequestItem)(nil), "github.com.alaleks.shortener.ShortenBatchRequestItem")
-	proto.RegisterType((*ShortenBatchResponse)(nil), "github.com.alaleks.shortener.ShortenBatchResponse")
-	proto.RegisterType((*ShortenBatchResponseItem)(nil), "github.com.alaleks.shortener.ShortenBatchResponseItem")
-	proto.RegisterType((*ShortenDeleteRequest)(nil), "github.com.alaleks.shortener.ShortenDeleteRequest")
-	proto.RegisterType((*StatsInternalReponse)(nil), "github.com.alaleks.shortener.StatsInternalReponse")
-}
-
-func init() {
-	proto.RegisterFile("shortener.proto", fileDescriptor_6a64040fb43d257f)
-}
-
-var fileDescriptor_6a64040fb43d257f = []byte{
-	// 591 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0xdf, 0x6f, 0xd3, 0x40,
-	0x0c, 0x6e, 0x96, 0x6d, 0x6d, 0xdc, 0xfd, 0x40, 0xc7, 0x04, 0x21, 0xec, 0xa1, 0x1c, 0x0c, 0x75,
-	0x13, 0x64, 0xa2, 0x08, 0x24, 0xde, 0xd8, 0xc4, 0x34, 0x15, 0xed, 0x29, 0x53, 0x1e, 0x40, 0x48,
-	0x25, 0x4d, 0xad, 0xb6, 0xec, 0x9a, 0x94, 0xbb, 0x8b, 0x04, 0xfc, 0x11, 0xfc, 0xcd, 0x28, 0xc9,
-	0xe5, 0x9a, 0x54, 0xac, 0x4d, 0x9f, 0x5a, 0xbb, 0x9f, 0xed, 0xcf, 0xf6, 0xe7, 0x2b, 0x1c, 0x8a,
-	0x49, 0xcc, 0x25, 0x46, 0xc8, 0xdd, 0x39, 0x8f, 0x65, 0x4c, 0x8e, 0xc7, 0x53, 0x39, 0x49, 0x86,
-	0x6e, 0x18, 0xcf, 0xdc, 0x80, 0x05, 0x0c, 0xef, 0x84, 0xab, 0x31, 0xb4, 0x09, 0x3b, 0x57, 0xb3,
-	0xb9, 0xfc, 0x4d, 0x29, 0x1c, 0xdc, 0xe6, 0x5e, 0x0f, 0x7f, 0x26, 0x28, 0x24, 0x79, 0x00, 0x66,
-	0xc2, 0x99, 0x6d, 0x74, 0x8c, 0xae, 0xe5, 0xa5, 0x5f, 0xe9, 0x17, 0x38, 0xd4, 0x18, 0x31, 0x8f,
-	0x23, 0x81, 0xe4, 0x08, 0x76, 0x90, 0xf3, 0x98, 0x2b, 0x58, 0x6e, 0x90, 0x47, 0xb0, 0xcb, 0x51,
-	0x24, 0x4c, 0xda, 0x5b, 0x99, 0x5b, 0x59, 0xc4, 0x86, 0xa6, 0x48, 0xc2, 0x10, 0x85, 0xb0, 0xcd,
-	0x8e, 0xd1, 0x6d, 0x79, 0x85, 0x49, 0x4f, 0xa1, 0x7d, 0x2b, 0x03, 0x59, 0xd4, 0x76, 0xa0, 0x95,
-	0x71, 0x4c, 0xa6, 0x23, 0x95, 0x59, 0xdb, 0xf4, 0x17, 0xec, 0xe5, 0x50, 0x45, 0x41, 0x63, 0x35,
-	0x59, 0x6d, 0xa7, 0x05, 0x59, 0x1c, 0x8d, 0xd3, 0x9f, 0x72, 0x26, 0x85, 0x49, 0x8e, 0xc1, 0x0a,
-	0x39, 0x06, 0x12, 0x47, 0x17, 0x32, 0x23, 0x63, 0x79, 0x0b, 0x47, 0xda, 0x56, 0x22, 0x82, 0x31,
-	0xda, 0xdb, 0x1d, 0xa3, 0xbb, 0xed, 0xe5, 0x06, 0xbd, 0x82, 0x96, 0x2f, 0x90, 0x0b, 0xdf, 0xbb,
-	0x21, 0x1f, 0x60, 0x3b, 0xe1, 0x4c, 0xd8, 0x46, 0xc7, 0xec, 0xb6, 0x7b, 0x27, 0xee, 0xaa, 0x29,
-	0xbb, 0x69, 0x94, 0xef, 0xdd, 0x78, 0x59, 0x08, 0xbd, 0x80, 0xa6, 0x72, 0x90, 0xa7, 0x60, 0x65,
-	0xa8, 0xc1, 0x32, 0x79, 0x9f, 0x33, 0xf2, 0x04, 0x5a, 0x29, 0xdb, 0xc1, 0x12, 0x7b, 0x9f, 0x33,
-	0xfa, 0x1d, 0x1e, 0xaa, 0x4d, 0x5c, 0x06, 0x32, 0x9c, 0x14, 0x63, 0xeb, 0x57, 0x48, 0xbd, 0x5b,
-	0x4d, 0xea, 0x3f, 0x09, 0xfa, 0x12, 0x67, 0x8a, 0x64, 0x08, 0x8f, 0xef, 0x01, 0x90, 0x13, 0x38,
-	0x08, 0x63, 0xce, 0x91, 0x05, 0x72, 0x1a, 0x47, 0x03, 0xbd, 0xa2, 0xfd, 0x92, 0xb7, 0x3f, 0x22,
-	0xcf, 0x60, 0x2f, 0xe6, 0xd3, 0xf1, 0x34, 0x0a, 0x58, 0xa9, 0x85, 0x76, 0xe1, 0x4b, 0xdb, 0x18,
-	0xc2, 0x51, 0xb5, 0x88, 0x5a, 0xe9, 0xe7, 0x4a, 0x1f, 0xef, 0x37, 0xe9, 0x23, 0xcf, 0x50, 0x6a,
-	0x44, 0x82, 0x7d, 0x1f, 0xa2, 0x6e, 0x27, 0x95, 0x2d, 0x6d, 0x2d, 0x6d, 0x49, 0x5f, 0x80, 0x59,
-	0xba, 0x00, 0x7a, 0xa6, 0x3b, 0xfb, 0x84, 0x0c, 0x25, 0x16, 0x1b, 0x22, 0xa5, 0xce, 0x2c, 0xc5,
-	0xf0, 0x23, 0x1c, 0xa5, 0x82, 0x16, 0xfd, 0x48, 0x22, 0x8f, 0x02, 0xe6, 0x61, 0x3e, 0x85, 0x05,
-	0xd6, 0xe8, 0x9a, 0x39, 0x36, 0x17, 0x26, 0x72, 0x91, 0xd1, 0x30, 0xbd, 0xdc, 0xe8, 0xfd, 0xdd,
-	0x01, 0xeb, 0xb6, 0x18, 0x08, 0xb9, 0x03, 0x50, 0x46, 0x2a, 0xb1, 0x57, 0xb5, 0xa6, 0xa7, 0xf8,
-	0x39, 0xaf, 0x6b, 0xa2, 0xf3, 0x21, 0xd2, 0x06, 0x19, 0x42, 0xf3, 0x1a, 0x65, 0xca, 0x9f, 0x9c,
-	0xae, 0x89, 0x5d, 0xdc, 0xb7, 0x73, 0x56, 0x07, 0xaa, 0x6b, 0x7c, 0x83, 0xf6, 0x35, 0x4a, 0x7d,
-	0x7a, 0xcf, 0x57, 0x07, 0x67, 0xef, 0x99, 0xf3, 0x72, 0xfd, 0x45, 0xa6, 0xc9, 0x68, 0x83, 0xfc,
-	0xd1, 0xaf, 0x9a, 0xef, 0xdd, 0x64, 0x1a, 0x21, 0x6f, 0x36, 0xbe, 0x1c, 0xa7, 0xb7, 0xb9, 0x48,
-	0x69, 0x83, 0xfc, 0x80, 0xfd, 0x8a, 0x4c, 0x48, 0xbd, 0x34, 0x15, 0x4d, 0x39, 0x75, 0xe6, 0xa1,
-	0x6a, 0x95, 0x65, 0x56, 0x6f, 0x8e, 0xbd, 0xf5, 0x9b, 0x5a, 0x16, 0x2e, 0x6d, 0x5c, 0xbe, 0xf8,
-	0x4a, 0x17, 0x61, 0xe7, 0x2a, 0xec, 0x5c, 0x87, 0x9d, 0x67, 0x7f, 0x4d, 0xc3, 0xdd, 0xec, 0xe3,
-	0xed, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x84, 0xff, 0x6a, 0xf5, 0xb4, 0x06, 0x00, 0x00,
+// The response message for ExportUserURLs containing one page of URLs.
+type ExportResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Urls []*UserURL `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	// The cursor of the next page, empty for the last page.
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+}
+
+func (x *ExportResponse) Reset() {
+	*x = ExportResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shortener_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportResponse) ProtoMessage() {}
+
+func (x *ExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shortener_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportResponse.ProtoReflect.Descriptor instead.
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return file_shortener_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExportResponse) GetUrls() []*UserURL {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *ExportResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_shortener_proto protoreflect.FileDescriptor
+
+var file_shortener_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x1c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c,
+	0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x1a,
+	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x07, 0x0a,
+	0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x91, 0x03, 0x0a, 0x0e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x03, 0x75, 0x74, 0x6d, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65,
+	0x6e, 0x65, 0x72, 0x2e, 0x55, 0x54, 0x4d, 0x52, 0x03, 0x75, 0x74, 0x6d, 0x12, 0x23, 0x0a, 0x0d,
+	0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x12, 0x38, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x22, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c,
+	0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e,
+	0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x08, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65,
+	0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x56, 0x61, 0x72,
+	0x69, 0x61, 0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61,
+	0x78, 0x5f, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x72, 0x5f,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x71, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x22, 0x4b, 0x0a, 0x07, 0x56, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x22, 0x74, 0x0a, 0x12, 0x53, 0x65, 0x74, 0x56, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x41, 0x0a, 0x08, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b,
+	0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x56, 0x61, 0x72, 0x69,
+	0x61, 0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x22, 0x7c, 0x0a,
+	0x04, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x69, 0x64, 0x72, 0x73, 0x22, 0x68, 0x0a, 0x0f, 0x53,
+	0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x38, 0x0a, 0x05, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x7f, 0x0a, 0x03, 0x55, 0x54, 0x4d, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x75, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x75, 0x6d, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x61, 0x6d, 0x70, 0x61, 0x69, 0x67, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x61, 0x6d, 0x70, 0x61, 0x69, 0x67, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x72, 0x6d,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x72, 0x0a, 0x0f, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x12, 0x17, 0x0a, 0x07, 0x71, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x71, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x29, 0x0a, 0x0b, 0x53, 0x74,
+	0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x75, 0x69, 0x64, 0x22, 0xdb, 0x02, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75,
+	0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x75,
+	0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x6f, 0x6e, 0x67, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x6e, 0x67, 0x75, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x41, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x25, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e,
+	0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65,
+	0x72, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x12, 0x3c, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x24, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61,
+	0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12,
+	0x42, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x26, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61,
+	0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x91, 0x01, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x66, 0x61, 0x76, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x66, 0x61, 0x76, 0x69, 0x63, 0x6f, 0x6e, 0x22, 0x95, 0x01, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x6c,
+	0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x87, 0x02, 0x0a, 0x0f, 0x55, 0x73, 0x65, 0x72, 0x73, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x54, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x7c, 0x0a, 0x08, 0x55, 0x73, 0x65,
+	0x72, 0x73, 0x55, 0x52, 0x4c, 0x12, 0x39, 0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e,
+	0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x55, 0x52, 0x4c, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x9a, 0x05, 0x0a, 0x07, 0x55, 0x73, 0x65, 0x72,
+	0x55, 0x52, 0x4c, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c,
+	0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x6e, 0x67, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x6e, 0x67, 0x55, 0x72, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c,
+	0x69, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x63,
+	0x6b, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72,
+	0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73,
+	0x41, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x33,
+	0x0a, 0x03, 0x75, 0x74, 0x6d, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73,
+	0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x55, 0x54, 0x4d, 0x52, 0x03,
+	0x75, 0x74, 0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x71,
+	0x75, 0x65, 0x72, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x66, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x38, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65,
+	0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c,
+	0x65, 0x73, 0x12, 0x41, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x0d,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65,
+	0x6e, 0x65, 0x72, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x08, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x63, 0x6b,
+	0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x63,
+	0x6b, 0x73, 0x12, 0x3c, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e,
+	0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65,
+	0x72, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68,
+	0x12, 0x42, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e,
+	0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65,
+	0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x78, 0x0a, 0x13, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x49, 0x0a, 0x04, 0x75,
+	0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x74, 0x6f, 0x6d, 0x69, 0x63,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x22, 0x63,
+	0x0a, 0x17, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72,
+	0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c,
+	0x55, 0x72, 0x6c, 0x22, 0x62, 0x0a, 0x14, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x04, 0x75,
+	0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x49, 0x74, 0x65,
+	0x6d, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x22, 0x74, 0x0a, 0x18, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x65, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x49,
+	0x74, 0x65, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72,
+	0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x2a, 0x0a,
+	0x14, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x22, 0x40, 0x0a, 0x14, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x75, 0x72, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0x44, 0x0a, 0x0d, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75,
+	0x72, 0x73, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x22, 0x6c, 0x0a, 0x0e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x25, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61,
+	0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x2e, 0x55, 0x73, 0x65, 0x72, 0x55, 0x52, 0x4c, 0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x32,
+	0xbc, 0x0a, 0x0a, 0x09, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12, 0x7e, 0x0a,
+	0x0a, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x55, 0x52, 0x4c, 0x12, 0x2c, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73,
+	0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73,
+	0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0d,
+	0x3a, 0x01, 0x2a, 0x22, 0x08, 0x2f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x12, 0x7a, 0x0a,
+	0x07, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x12, 0x29, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e,
+	0x65, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x2f, 0x7b,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x75, 0x69, 0x64, 0x7d, 0x12, 0x78, 0x0a, 0x0b, 0x47, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x73, 0x55, 0x52, 0x4c, 0x12, 0x2d, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x73, 0x55, 0x52, 0x4c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x73, 0x55, 0x52, 0x4c, 0x22,
+	0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x75,
+	0x72, 0x6c, 0x73, 0x12, 0x93, 0x01, 0x0a, 0x0f, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x55,
+	0x52, 0x4c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x31, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65,
+	0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x19,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x3a, 0x01, 0x2a, 0x22, 0x0e, 0x2f, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x65, 0x6e, 0x2f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x12, 0x7f, 0x0a, 0x0d, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x65, 0x6e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x32, 0x2e, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65,
+	0x6e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c,
+	0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x3a, 0x01, 0x2a, 0x2a, 0x0a,
+	0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x75, 0x72, 0x6c, 0x73, 0x12, 0x81, 0x01, 0x0a, 0x0d, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x23, 0x2e, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b,
+	0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x32, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61,
+	0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x12, 0x0f, 0x2f,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x8b,
+	0x01, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x2d, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73,
+	0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x52, 0x75,
+	0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x2b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x25, 0x3a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x1a, 0x1c,
+	0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x75, 0x72, 0x6c, 0x73, 0x2f, 0x7b, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x5f, 0x75, 0x72, 0x6c, 0x7d, 0x2f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x97, 0x01, 0x0a,
+	0x0b, 0x53, 0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x30, 0x2e, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b,
+	0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x65, 0x74, 0x56,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c,
+	0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x31, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2b, 0x3a, 0x08, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x6e, 0x74, 0x73, 0x1a, 0x1f, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x75, 0x72, 0x6c,
+	0x73, 0x2f, 0x7b, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x7d, 0x2f, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x84, 0x01, 0x0a, 0x0d, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x65, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x35, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x1a,
+	0x36, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61,
+	0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x6f, 0x0a,
+	0x0e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x55, 0x73, 0x65, 0x72, 0x55, 0x52, 0x4c, 0x73, 0x12,
+	0x2b, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61,
+	0x6c, 0x65, 0x6b, 0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x6c, 0x61, 0x6c, 0x65, 0x6b,
+	0x73, 0x2e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x24,
+	0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x6c, 0x61,
+	0x6c, 0x65, 0x6b, 0x73, 0x2f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_shortener_proto_rawDescOnce sync.Once
+	file_shortener_proto_rawDescData = file_shortener_proto_rawDesc
+)
+
+func file_shortener_proto_rawDescGZIP() []byte {
+	file_shortener_proto_rawDescOnce.Do(func() {
+		file_shortener_proto_rawDescData = protoimpl.X.CompressGZIP(file_shortener_proto_rawDescData)
+	})
+	return file_shortener_proto_rawDescData
+}
+
+var file_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_shortener_proto_goTypes = []interface{}{
+	(*Empty)(nil),                    // 0: github.com.alaleks.shortener.Empty
+	(*ShortenRequest)(nil),           // 1: github.com.alaleks.shortener.ShortenRequest
+	(*Variant)(nil),                  // 2: github.com.alaleks.shortener.Variant
+	(*SetVariantsRequest)(nil),       // 3: github.com.alaleks.shortener.SetVariantsRequest
+	(*Rule)(nil),                     // 4: github.com.alaleks.shortener.Rule
+	(*SetRulesRequest)(nil),          // 5: github.com.alaleks.shortener.SetRulesRequest
+	(*UTM)(nil),                      // 6: github.com.alaleks.shortener.UTM
+	(*ShortenResponse)(nil),          // 7: github.com.alaleks.shortener.ShortenResponse
+	(*StatRequest)(nil),              // 8: github.com.alaleks.shortener.StatRequest
+	(*StatResponse)(nil),             // 9: github.com.alaleks.shortener.StatResponse
+	(*Metadata)(nil),                 // 10: github.com.alaleks.shortener.Metadata
+	(*Health)(nil),                   // 11: github.com.alaleks.shortener.Health
+	(*UsersURLRequest)(nil),          // 12: github.com.alaleks.shortener.UsersURLRequest
+	(*UsersURL)(nil),                 // 13: github.com.alaleks.shortener.UsersURL
+	(*UserURL)(nil),                  // 14: github.com.alaleks.shortener.UserURL
+	(*ShortenBatchRequest)(nil),      // 15: github.com.alaleks.shortener.ShortenBatchRequest
+	(*ShortenBatchRequestItem)(nil),  // 16: github.com.alaleks.shortener.ShortenBatchRequestItem
+	(*ShortenBatchResponse)(nil),     // 17: github.com.alaleks.shortener.ShortenBatchResponse
+	(*ShortenBatchResponseItem)(nil), // 18: github.com.alaleks.shortener.ShortenBatchResponseItem
+	(*ShortenDeleteRequest)(nil),     // 19: github.com.alaleks.shortener.ShortenDeleteRequest
+	(*StatsInternalReponse)(nil),     // 20: github.com.alaleks.shortener.StatsInternalReponse
+	(*ExportRequest)(nil),            // 21: github.com.alaleks.shortener.ExportRequest
+	(*ExportResponse)(nil),           // 22: github.com.alaleks.shortener.ExportResponse
+}
+var file_shortener_proto_depIdxs = []int32{
+	6,  // 0: github.com.alaleks.shortener.ShortenRequest.utm:type_name -> github.com.alaleks.shortener.UTM
+	4,  // 1: github.com.alaleks.shortener.ShortenRequest.rules:type_name -> github.com.alaleks.shortener.Rule
+	2,  // 2: github.com.alaleks.shortener.ShortenRequest.variants:type_name -> github.com.alaleks.shortener.Variant
+	2,  // 3: github.com.alaleks.shortener.SetVariantsRequest.variants:type_name -> github.com.alaleks.shortener.Variant
+	4,  // 4: github.com.alaleks.shortener.SetRulesRequest.rules:type_name -> github.com.alaleks.shortener.Rule
+	2,  // 5: github.com.alaleks.shortener.StatResponse.variants:type_name -> github.com.alaleks.shortener.Variant
+	11, // 6: github.com.alaleks.shortener.StatResponse.health:type_name -> github.com.alaleks.shortener.Health
+	10, // 7: github.com.alaleks.shortener.StatResponse.metadata:type_name -> github.com.alaleks.shortener.Metadata
+	14, // 8: github.com.alaleks.shortener.UsersURL.urls:type_name -> github.com.alaleks.shortener.UserURL
+	6,  // 9: github.com.alaleks.shortener.UserURL.utm:type_name -> github.com.alaleks.shortener.UTM
+	4,  // 10: github.com.alaleks.shortener.UserURL.rules:type_name -> github.com.alaleks.shortener.Rule
+	2,  // 11: github.com.alaleks.shortener.UserURL.variants:type_name -> github.com.alaleks.shortener.Variant
+	11, // 12: github.com.alaleks.shortener.UserURL.health:type_name -> github.com.alaleks.shortener.Health
+	10, // 13: github.com.alaleks.shortener.UserURL.metadata:type_name -> github.com.alaleks.shortener.Metadata
+	16, // 14: github.com.alaleks.shortener.ShortenBatchRequest.urls:type_name -> github.com.alaleks.shortener.ShortenBatchRequestItem
+	18, // 15: github.com.alaleks.shortener.ShortenBatchResponse.urls:type_name -> github.com.alaleks.shortener.ShortenBatchResponseItem
+	14, // 16: github.com.alaleks.shortener.ExportResponse.urls:type_name -> github.com.alaleks.shortener.UserURL
+	1,  // 17: github.com.alaleks.shortener.Shortener.ShortenURL:input_type -> github.com.alaleks.shortener.ShortenRequest
+	8,  // 18: github.com.alaleks.shortener.Shortener.GetStat:input_type -> github.com.alaleks.shortener.StatRequest
+	12, // 19: github.com.alaleks.shortener.Shortener.GetUsersURL:input_type -> github.com.alaleks.shortener.UsersURLRequest
+	15, // 20: github.com.alaleks.shortener.Shortener.ShortenURLBatch:input_type -> github.com.alaleks.shortener.ShortenBatchRequest
+	19, // 21: github.com.alaleks.shortener.Shortener.ShortenDelete:input_type -> github.com.alaleks.shortener.ShortenDeleteRequest
+	0,  // 22: github.com.alaleks.shortener.Shortener.StatsInternal:input_type -> github.com.alaleks.shortener.Empty
+	5,  // 23: github.com.alaleks.shortener.Shortener.SetRules:input_type -> github.com.alaleks.shortener.SetRulesRequest
+	3,  // 24: github.com.alaleks.shortener.Shortener.SetVariants:input_type -> github.com.alaleks.shortener.SetVariantsRequest
+	16, // 25: github.com.alaleks.shortener.Shortener.ShortenStream:input_type -> github.com.alaleks.shortener.ShortenBatchRequestItem
+	21, // 26: github.com.alaleks.shortener.Shortener.ExportUserURLs:input_type -> github.com.alaleks.shortener.ExportRequest
+	7,  // 27: github.com.alaleks.shortener.Shortener.ShortenURL:output_type -> github.com.alaleks.shortener.ShortenResponse
+	9,  // 28: github.com.alaleks.shortener.Shortener.GetStat:output_type -> github.com.alaleks.shortener.StatResponse
+	13, // 29: github.com.alaleks.shortener.Shortener.GetUsersURL:output_type -> github.com.alaleks.shortener.UsersURL
+	17, // 30: github.com.alaleks.shortener.Shortener.ShortenURLBatch:output_type -> github.com.alaleks.shortener.ShortenBatchResponse
+	0,  // 31: github.com.alaleks.shortener.Shortener.ShortenDelete:output_type -> github.com.alaleks.shortener.Empty
+	20, // 32: github.com.alaleks.shortener.Shortener.StatsInternal:output_type -> github.com.alaleks.shortener.StatsInternalReponse
+	0,  // 33: github.com.alaleks.shortener.Shortener.SetRules:output_type -> github.com.alaleks.shortener.Empty
+	0,  // 34: github.com.alaleks.shortener.Shortener.SetVariants:output_type -> github.com.alaleks.shortener.Empty
+	18, // 35: github.com.alaleks.shortener.Shortener.ShortenStream:output_type -> github.com.alaleks.shortener.ShortenBatchResponseItem
+	22, // 36: github.com.alaleks.shortener.Shortener.ExportUserURLs:output_type -> github.com.alaleks.shortener.ExportResponse
+	27, // [27:37] is the sub-list for method output_type
+	17, // [17:27] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_shortener_proto_init() }
+func file_shortener_proto_init() {
+	if File_shortener_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_shortener_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Variant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetVariantsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UTM); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Metadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Health); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UsersURLRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UsersURL); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserURL); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenBatchRequestItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenBatchResponseItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortenDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsInternalReponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shortener_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_shortener_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_shortener_proto_goTypes,
+		DependencyIndexes: file_shortener_proto_depIdxs,
+		MessageInfos:      file_shortener_proto_msgTypes,
+	}.Build()
+	File_shortener_proto = out.File
+	file_shortener_proto_rawDesc = nil
+	file_shortener_proto_goTypes = nil
+	file_shortener_proto_depIdxs = nil
 }
@@ -25,6 +25,10 @@ const (
 	Shortener_ShortenURLBatch_FullMethodName = "/github.com.alaleks.shortener.Shortener/ShortenURLBatch"
 	Shortener_ShortenDelete_FullMethodName   = "/github.com.alaleks.shortener.Shortener/ShortenDelete"
 	Shortener_StatsInternal_FullMethodName   = "/github.com.alaleks.shortener.Shortener/StatsInternal"
+	Shortener_SetRules_FullMethodName        = "/github.com.alaleks.shortener.Shortener/SetRules"
+	Shortener_SetVariants_FullMethodName     = "/github.com.alaleks.shortener.Shortener/SetVariants"
+	Shortener_ShortenStream_FullMethodName   = "/github.com.alaleks.shortener.Shortener/ShortenStream"
+	Shortener_ExportUserURLs_FullMethodName  = "/github.com.alaleks.shortener.Shortener/ExportUserURLs"
 )
 
 // ShortenerClient is the client API for Shortener service.
@@ -33,10 +37,14 @@ const (
 type ShortenerClient interface {
 	ShortenURL(ctx context.Context, in *ShortenRequest, opts ...grpc.CallOption) (*ShortenResponse, error)
 	GetStat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error)
-	GetUsersURL(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UsersURL, error)
+	GetUsersURL(ctx context.Context, in *UsersURLRequest, opts ...grpc.CallOption) (*UsersURL, error)
 	ShortenURLBatch(ctx context.Context, in *ShortenBatchRequest, opts ...grpc.CallOption) (*ShortenBatchResponse, error)
 	ShortenDelete(ctx context.Context, in *ShortenDeleteRequest, opts ...grpc.CallOption) (*Empty, error)
 	StatsInternal(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*StatsInternalReponse, error)
+	SetRules(ctx context.Context, in *SetRulesRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetVariants(ctx context.Context, in *SetVariantsRequest, opts ...grpc.CallOption) (*Empty, error)
+	ShortenStream(ctx context.Context, opts ...grpc.CallOption) (Shortener_ShortenStreamClient, error)
+	ExportUserURLs(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Shortener_ExportUserURLsClient, error)
 }
 
 type shortenerClient struct {
@@ -65,7 +73,7 @@ func (c *shortenerClient) GetStat(ctx context.Context, in *StatRequest, opts ...
 	return out, nil
 }
 
-func (c *shortenerClient) GetUsersURL(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UsersURL, error) {
+func (c *shortenerClient) GetUsersURL(ctx context.Context, in *UsersURLRequest, opts ...grpc.CallOption) (*UsersURL, error) {
 	out := new(UsersURL)
 	err := c.cc.Invoke(ctx, Shortener_GetUsersURL_FullMethodName, in, out, opts...)
 	if err != nil {
@@ -101,16 +109,101 @@ func (c *shortenerClient) StatsInternal(ctx context.Context, in *Empty, opts ...
 	return out, nil
 }
 
+func (c *shortenerClient) SetRules(ctx context.Context, in *SetRulesRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Shortener_SetRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) SetVariants(ctx context.Context, in *SetVariantsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Shortener_SetVariants_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) ShortenStream(ctx context.Context, opts ...grpc.CallOption) (Shortener_ShortenStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Shortener_ServiceDesc.Streams[0], Shortener_ShortenStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shortenerShortenStreamClient{stream}
+	return x, nil
+}
+
+type Shortener_ShortenStreamClient interface {
+	Send(*ShortenBatchRequestItem) error
+	Recv() (*ShortenBatchResponseItem, error)
+	grpc.ClientStream
+}
+
+type shortenerShortenStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *shortenerShortenStreamClient) Send(m *ShortenBatchRequestItem) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *shortenerShortenStreamClient) Recv() (*ShortenBatchResponseItem, error) {
+	m := new(ShortenBatchResponseItem)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shortenerClient) ExportUserURLs(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Shortener_ExportUserURLsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Shortener_ServiceDesc.Streams[1], Shortener_ExportUserURLs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shortenerExportUserURLsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Shortener_ExportUserURLsClient interface {
+	Recv() (*ExportResponse, error)
+	grpc.ClientStream
+}
+
+type shortenerExportUserURLsClient struct {
+	grpc.ClientStream
+}
+
+func (x *shortenerExportUserURLsClient) Recv() (*ExportResponse, error) {
+	m := new(ExportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ShortenerServer is the server API for Shortener service.
 // All implementations must embed UnimplementedShortenerServer
 // for forward compatibility
 type ShortenerServer interface {
 	ShortenURL(context.Context, *ShortenRequest) (*ShortenResponse, error)
 	GetStat(context.Context, *StatRequest) (*StatResponse, error)
-	GetUsersURL(context.Context, *Empty) (*UsersURL, error)
+	GetUsersURL(context.Context, *UsersURLRequest) (*UsersURL, error)
 	ShortenURLBatch(context.Context, *ShortenBatchRequest) (*ShortenBatchResponse, error)
 	ShortenDelete(context.Context, *ShortenDeleteRequest) (*Empty, error)
 	StatsInternal(context.Context, *Empty) (*StatsInternalReponse, error)
+	SetRules(context.Context, *SetRulesRequest) (*Empty, error)
+	SetVariants(context.Context, *SetVariantsRequest) (*Empty, error)
+	ShortenStream(Shortener_ShortenStreamServer) error
+	ExportUserURLs(*ExportRequest, Shortener_ExportUserURLsServer) error
 	mustEmbedUnimplementedShortenerServer()
 }
 
@@ -124,7 +217,7 @@ func (UnimplementedShortenerServer) ShortenURL(context.Context, *ShortenRequest)
 func (UnimplementedShortenerServer) GetStat(context.Context, *StatRequest) (*StatResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStat not implemented")
 }
-func (UnimplementedShortenerServer) GetUsersURL(context.Context, *Empty) (*UsersURL, error) {
+func (UnimplementedShortenerServer) GetUsersURL(context.Context, *UsersURLRequest) (*UsersURL, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUsersURL not implemented")
 }
 func (UnimplementedShortenerServer) ShortenURLBatch(context.Context, *ShortenBatchRequest) (*ShortenBatchResponse, error) {
@@ -136,6 +229,18 @@ func (UnimplementedShortenerServer) ShortenDelete(context.Context, *ShortenDelet
 func (UnimplementedShortenerServer) StatsInternal(context.Context, *Empty) (*StatsInternalReponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StatsInternal not implemented")
 }
+func (UnimplementedShortenerServer) SetRules(context.Context, *SetRulesRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRules not implemented")
+}
+func (UnimplementedShortenerServer) SetVariants(context.Context, *SetVariantsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVariants not implemented")
+}
+func (UnimplementedShortenerServer) ShortenStream(Shortener_ShortenStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ShortenStream not implemented")
+}
+func (UnimplementedShortenerServer) ExportUserURLs(*ExportRequest, Shortener_ExportUserURLsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUserURLs not implemented")
+}
 func (UnimplementedShortenerServer) mustEmbedUnimplementedShortenerServer() {}
 
 // UnsafeShortenerServer may be embedded to opt out of forward compatibility for this service.
@@ -186,7 +291,7 @@ func _Shortener_GetStat_Handler(srv interface{}, ctx context.Context, dec func(i
 }
 
 func _Shortener_GetUsersURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(UsersURLRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -198,7 +303,7 @@ func _Shortener_GetUsersURL_Handler(srv interface{}, ctx context.Context, dec fu
 		FullMethod: Shortener_GetUsersURL_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShortenerServer).GetUsersURL(ctx, req.(*Empty))
+		return srv.(ShortenerServer).GetUsersURL(ctx, req.(*UsersURLRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -257,6 +362,89 @@ func _Shortener_StatsInternal_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Shortener_SetRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetRules(ctx, req.(*SetRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_SetVariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).SetVariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_SetVariants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).SetVariants(ctx, req.(*SetVariantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_ShortenStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShortenerServer).ShortenStream(&shortenerShortenStreamServer{stream})
+}
+
+type Shortener_ShortenStreamServer interface {
+	Send(*ShortenBatchResponseItem) error
+	Recv() (*ShortenBatchRequestItem, error)
+	grpc.ServerStream
+}
+
+type shortenerShortenStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *shortenerShortenStreamServer) Send(m *ShortenBatchResponseItem) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *shortenerShortenStreamServer) Recv() (*ShortenBatchRequestItem, error) {
+	m := new(ShortenBatchRequestItem)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Shortener_ExportUserURLs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShortenerServer).ExportUserURLs(m, &shortenerExportUserURLsServer{stream})
+}
+
+type Shortener_ExportUserURLsServer interface {
+	Send(*ExportResponse) error
+	grpc.ServerStream
+}
+
+type shortenerExportUserURLsServer struct {
+	grpc.ServerStream
+}
+
+func (x *shortenerExportUserURLsServer) Send(m *ExportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Shortener_ServiceDesc is the grpc.ServiceDesc for Shortener service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -288,7 +476,27 @@ var Shortener_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StatsInternal",
 			Handler:    _Shortener_StatsInternal_Handler,
 		},
+		{
+			MethodName: "SetRules",
+			Handler:    _Shortener_SetRules_Handler,
+		},
+		{
+			MethodName: "SetVariants",
+			Handler:    _Shortener_SetVariants_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ShortenStream",
+			Handler:       _Shortener_ShortenStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportUserURLs",
+			Handler:       _Shortener_ExportUserURLs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "shortener.proto",
 }
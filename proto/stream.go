@@ -0,0 +1,89 @@
+package proto
+
+import (
+	"errors"
+	"io"
+
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+	status "google.golang.org/grpc/status"
+)
+
+// ShortenStream implements URL shortening in a bidirectional stream.
+//
+// Each received URL is shortened and the result is sent back immediately,
+// so the number of URLs is not limited by the maximum message size.
+func (s *Server) ShortenStream(stream Shortener_ShortenStreamServer) error {
+	userID, err := definitionUser(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for {
+		item, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result := s.service.ShortenItem(userID, usecase.BatchItem{
+			CorID:       item.CorrelationId,
+			OriginalURL: item.OriginalUrl,
+		})
+
+		if err := stream.Send(batchResponseItem(result)); err != nil {
+			return err
+		}
+	}
+}
+
+// ExportUserURLs exports all shortened URLs for current user in a server stream.
+//
+// URLs are read from storage page by page and each page is sent
+// in a separate message with the cursor of the next page,
+// which allows to continue the export after the connection is broken.
+func (s *Server) ExportUserURLs(in *ExportRequest, stream Shortener_ExportUserURLsServer) error {
+	userID, err := definitionUser(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	cursor := in.Cursor
+
+	for {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		page, err := s.service.ListPage(userID, storage.ListOptions{
+			Cursor: cursor,
+			Limit:  int(in.PageSize),
+		})
+
+		if err != nil {
+			return statusError(err)
+		}
+
+		out := &ExportResponse{
+			Urls:       make([]*UserURL, 0, len(page.URLs)),
+			NextCursor: page.NextCursor,
+		}
+
+		for _, v := range page.URLs {
+			out.Urls = append(out.Urls, userURL(v))
+		}
+
+		if err := stream.Send(out); err != nil {
+			return err
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+
+		cursor = page.NextCursor
+	}
+}
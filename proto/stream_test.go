@@ -0,0 +1,137 @@
+package proto_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/alaleks/shortener/internal/app/config"
+	"github.com/alaleks/shortener/internal/app/logger"
+	"github.com/alaleks/shortener/internal/app/serv/middleware/auth"
+	"github.com/alaleks/shortener/internal/app/storage"
+	"github.com/alaleks/shortener/internal/app/usecase"
+	pb "github.com/alaleks/shortener/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+// newClient starts the grpc server in memory and returns the client
+// and the context with the authorization token.
+func newClient(t *testing.T) (pb.ShortenerClient, context.Context) {
+	t.Helper()
+
+	appConf := config.New(config.Options{Env: false, Flag: false})
+	logger := logger.NewLogger()
+	st := storage.InitStore(appConf, logger)
+	listener := bufconn.Listen(bufSize)
+
+	authorization := auth.TurnOn(st.St, appConf.GetSecretKey())
+	authorization.Methods(auth.Required, pb.Shortener_ShortenStream_FullMethodName,
+		pb.Shortener_ExportUserURLs_FullMethodName)
+
+	server := grpc.NewServer(grpc.StreamInterceptor(authorization.StreamInterceptor))
+	pb.RegisterShortenerServer(server, pb.New(usecase.New(st, appConf, nil), logger))
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed dial bufnet: %s", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"Authorization", authorization.CreateSigning(st.St.Create()))
+
+	return pb.NewShortenerClient(conn), ctx
+}
+
+func TestShortenStreamAndExport(t *testing.T) {
+	t.Parallel()
+
+	client, ctx := newClient(t)
+
+	// данные для теста
+	const qty = 25
+
+	stream, err := client.ShortenStream(ctx)
+	if err != nil {
+		t.Fatalf("failed open stream: %s", err)
+	}
+
+	// отправляем ссылки и сразу читаем результаты
+	for i := 0; i < qty; i++ {
+		err := stream.Send(&pb.ShortenBatchRequestItem{
+			CorrelationId: strconv.Itoa(i),
+			OriginalUrl:   "https://example.com/" + strconv.Itoa(i),
+		})
+		if err != nil {
+			t.Fatalf("failed send: %s", err)
+		}
+
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("failed receive: %s", err)
+		}
+
+		if res.CorrelationId != strconv.Itoa(i) || res.ShortUrl == "" {
+			t.Errorf("unexpected result for correlation id %d: %v", i, res)
+		}
+	}
+
+	// невалидная ссылка возвращает ошибку в элементе, а не обрывает поток
+	_ = stream.Send(&pb.ShortenBatchRequestItem{CorrelationId: "bad", OriginalUrl: "example"})
+
+	res, err := stream.Recv()
+	if err != nil || res.Error == "" {
+		t.Errorf("invalid url should return error in item but received %v, %v", res, err)
+	}
+
+	_ = stream.CloseSend()
+
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("stream should be closed but received %v", err)
+	}
+
+	// экспортируем ссылки страницами по 10
+	export, err := client.ExportUserURLs(ctx, &pb.ExportRequest{PageSize: 10})
+	if err != nil {
+		t.Fatalf("failed open export stream: %s", err)
+	}
+
+	var pages, urls int
+
+	for {
+		page, err := export.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("failed receive page: %s", err)
+		}
+
+		pages++
+		urls += len(page.Urls)
+	}
+
+	if pages != 3 || urls != qty {
+		t.Errorf("export should return 3 pages and %d urls but received %d pages and %d urls", qty, pages, urls)
+	}
+}